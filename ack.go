@@ -0,0 +1,260 @@
+package gowp
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// states of a delivery.
+const (
+	deliveryPending uint32 = iota
+	deliveryAcked
+	deliveryNacked  // explicitly, or the task returned without acknowledging it.
+	deliveryExpired // the ack timeout elapsed, the task has been delivered again.
+)
+
+type (
+	// AckTask is a task that acknowledges its delivery explicitly, see SubmitAcked.
+	AckTask func(d *Delivery)
+
+	// Delivery is a single attempt at executing an AckTask.
+	Delivery struct {
+		a       *acked
+		attempt int
+		state   uint32 // one of the delivery states. Should be manipulated by sync/atomic.
+	}
+
+	// redelivery is the policy of SubmitAcked, see WithRedelivery.
+	redelivery struct {
+		timeout     time.Duration
+		maxAttempts int
+	}
+
+	// acked tracks the deliveries of a task submitted with SubmitAcked.
+	acked struct {
+		p       *Pool
+		t       AckTask
+		f       *Future // reports the outcome of the task, once acknowledged or given up on.
+		opts    []TaskOption
+		backoff Backoff // of the task if set with TaskBackoff, of the pool otherwise. nil, if none.
+
+		mu       sync.Mutex
+		attempts int
+		settled  bool
+	}
+)
+
+// WithRedelivery returns an Option that sets the policy of SubmitAcked. A delivery that isn't acknowledged
+// within timeout is delivered again, to another worker if one is available. The task is given up on
+// after maxAttempts deliveries, its Future reports ErrNotAcked. Zero means no timeout and no limit,
+// which is the default. Negative values result in ErrInvalidRedelivery on Pool initialization.
+func WithRedelivery(timeout time.Duration, maxAttempts int) Option {
+	return func(o *config) {
+		o.redelivery = redelivery{timeout: timeout, maxAttempts: maxAttempts}
+	}
+}
+
+// SubmitAcked submits t for at-least-once processing: t has to call Delivery.Ack once its work is done.
+// If it doesn't, i.e. it calls Delivery.Nack, returns without acknowledging, panics or exceeds the timeout
+// set by WithRedelivery, t is delivered again, after a delay if WithBackoff or TaskBackoff is used.
+// A panic is recovered and counts as a missing acknowledgement.
+// t may therefore run more than once, possibly at the same time on two workers after a timeout.
+//
+// The Future reports nil once a delivery is acknowledged, or ErrNotAcked once the deliveries are exhausted.
+// Each delivery is a task of the pool that doesn't fail, ErrNotAcked is reported only by the Future.
+// The pool stays open until the task is settled.
+// Cancelling the Future prevents further deliveries.
+func (p *Pool) SubmitAcked(t AckTask, opts ...TaskOption) (*Future, error) {
+	if t == nil {
+		return nil, fmt.Errorf("gowp.Pool.SubmitAcked(): %w", ErrNilTask)
+	}
+
+	a := &acked{p: p, t: t, f: newFuture(), backoff: p.backoff}
+	if b := taskBackoff(opts); b != nil {
+		a.backoff = b
+	}
+	a.f.pool = p
+	a.f.id = p.nextID() // deliveries get their own.
+
+	// don't modify the backing array of the caller's options.
+	a.opts = append(opts[:len(opts):len(opts)], func(j *job) { j.delayed, j.key, j.cacheKey = true, "", "" })
+
+	err := p.hold(a.f, false, func() (stop func()) {
+		return func() { a.stop() }
+	})
+	if err != nil {
+		return nil, fmt.Errorf("gowp.Pool.SubmitAcked(): %w", err)
+	}
+
+	if err := a.deliver(); err != nil {
+		a.stop()
+		p.unhold(a.f)
+
+		return nil, fmt.Errorf("gowp.Pool.SubmitAcked(): %w", err)
+	}
+
+	return a.f, nil
+}
+
+// Attempt returns the number of the delivery, starting at 1.
+func (d *Delivery) Attempt() int {
+	return d.attempt
+}
+
+// Ack acknowledges the task, it won't be delivered again. A late acknowledgement, after the timeout
+// elapsed, is accepted as long as the task is not settled. It reports whether the acknowledgement settled the task.
+func (d *Delivery) Ack() bool {
+	if !atomic.CompareAndSwapUint32(&d.state, deliveryPending, deliveryAcked) &&
+		!atomic.CompareAndSwapUint32(&d.state, deliveryExpired, deliveryAcked) {
+		return false
+	}
+
+	return d.a.settle(nil)
+}
+
+// Nack rejects the delivery, the task is delivered again unless the deliveries are exhausted.
+// It reports whether the delivery was pending.
+func (d *Delivery) Nack() bool {
+	if !atomic.CompareAndSwapUint32(&d.state, deliveryPending, deliveryNacked) {
+		return false
+	}
+
+	d.a.redeliver()
+
+	return true
+}
+
+// deliver submits the first delivery. Further ones are submitted by redeliver.
+func (a *acked) deliver() error {
+	d := a.next()
+	if d == nil {
+		return nil
+	}
+
+	return a.p.submitJob(a.run(d), nil, a.opts, false)
+}
+
+// redeliver delivers the task again, unless it is settled, once the backoff has elapsed. The delivery is held, like a task submitted with
+// SubmitAfter, so that the pool stays open and workers never wait for room in the queue for it.
+func (a *acked) redeliver() {
+	d := a.next()
+	if d == nil {
+		return
+	}
+
+	var delay time.Duration
+	if a.backoff != nil {
+		delay = a.backoff.Delay(d.attempt - 1)
+	}
+
+	t, f := a.run(d), newFuture()
+	f.pool = a.p
+
+	_ = a.p.hold(f, true, func() (stop func()) {
+		tm := a.p.clock.AfterFunc(delay, func() { a.p.fire(t, f, a.opts, nil) })
+		return func() { tm.Stop() }
+	})
+}
+
+// next returns the next delivery, nil if the task is settled or the deliveries are exhausted.
+func (a *acked) next() *Delivery {
+	a.mu.Lock()
+	if a.settled {
+		a.mu.Unlock()
+		return nil
+	}
+
+	max := a.p.redelivery.maxAttempts
+	if max > 0 && a.attempts >= max {
+		a.mu.Unlock()
+		a.settle(fmt.Errorf("%w after %d attempts", ErrNotAcked, max))
+
+		return nil
+	}
+
+	if !a.budget() {
+		n := a.attempts
+		a.mu.Unlock()
+		a.settle(fmt.Errorf("%w after %d attempts", ErrRetryBudgetExhausted, n))
+
+		return nil
+	}
+
+	a.attempts++
+	d := &Delivery{a: a, attempt: a.attempts}
+	a.mu.Unlock()
+
+	return d
+}
+
+// budget reports whether the retry budget of the pool, if any, allows the next delivery. a.mu must be held.
+func (a *acked) budget() bool {
+	b := a.p.retries
+	if b == nil {
+		return true
+	}
+
+	if a.attempts == 0 {
+		b.deposit(a.p.clock.Now())
+		return true
+	}
+
+	return b.withdraw(a.p.clock.Now())
+}
+
+// run returns the pool task executing d.
+func (a *acked) run(d *Delivery) Task {
+	return func() error {
+		a.mu.Lock()
+		settled := a.settled
+		a.mu.Unlock()
+
+		if settled {
+			return nil // acknowledged late by a previous delivery.
+		}
+
+		if timeout := a.p.redelivery.timeout; timeout > 0 {
+			tm := a.p.clock.AfterFunc(timeout, func() {
+				if atomic.CompareAndSwapUint32(&d.state, deliveryPending, deliveryExpired) {
+					a.redeliver()
+				}
+			})
+			defer tm.Stop()
+		}
+
+		defer func() {
+			_ = recover() // a panic is a missing acknowledgement.
+			d.Nack()
+		}()
+
+		a.t(d)
+
+		return nil
+	}
+}
+
+// settle reports the outcome of the task. It returns false if the task was settled already.
+func (a *acked) settle(err error) bool {
+	a.mu.Lock()
+	if a.settled {
+		a.mu.Unlock()
+		return false
+	}
+	a.settled = true
+	a.mu.Unlock()
+
+	if a.p.unhold(a.f) && a.f.start() {
+		a.f.complete(err)
+	}
+
+	return true
+}
+
+// stop prevents further deliveries, the task is cancelled or discarded.
+func (a *acked) stop() {
+	a.mu.Lock()
+	a.settled = true
+	a.mu.Unlock()
+}
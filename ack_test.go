@@ -0,0 +1,131 @@
+package gowp
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestPool_SubmitAcked(t *testing.T) {
+	tests := []struct {
+		name         string
+		opts         []Option
+		task         func(d *Delivery)
+		wantErr      error
+		wantAttempts int32
+	}{
+		{
+			name:         "acked",
+			task:         func(d *Delivery) { d.Ack() },
+			wantAttempts: 1,
+		},
+		{
+			name: "nacked then acked",
+			task: func(d *Delivery) {
+				if d.Attempt() < 3 {
+					d.Nack()
+					return
+				}
+				d.Ack()
+			},
+			wantAttempts: 3,
+		},
+		{
+			name: "panic then acked",
+			task: func(d *Delivery) {
+				if d.Attempt() == 1 {
+					panic("boom")
+				}
+				d.Ack()
+			},
+			wantAttempts: 2,
+		},
+		{
+			name: "timed out then acked",
+			opts: []Option{WithNumWorkers(2), WithRedelivery(5*time.Millisecond, 0)},
+			task: func(d *Delivery) {
+				if d.Attempt() == 1 {
+					time.Sleep(50 * time.Millisecond) // acknowledged by the second delivery meanwhile.
+				}
+				d.Ack()
+			},
+			wantAttempts: 2,
+		},
+		{
+			name:         "exhausted",
+			opts:         []Option{WithRedelivery(0, 2)},
+			task:         func(d *Delivery) {},
+			wantErr:      ErrNotAcked,
+			wantAttempts: 2,
+		},
+		{
+			name:         "retry budget exhausted",
+			opts:         []Option{WithRetryBudget(1, 0)},
+			task:         func(d *Delivery) { d.Nack() },
+			wantErr:      ErrRetryBudgetExhausted,
+			wantAttempts: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := New(testDefaultNumTasks, append([]Option{WithContext(context.Background())}, tt.opts...)...)
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+
+			var attempts int32
+			f, err := p.SubmitAcked(func(d *Delivery) {
+				atomic.AddInt32(&attempts, 1)
+				tt.task(d)
+			})
+			if err != nil {
+				t.Fatalf("Pool.SubmitAcked() error = %v", err)
+			}
+
+			if err := p.Wait(); err != nil {
+				t.Errorf("Pool.Wait() = %v, want nil", err)
+			}
+
+			if err := f.Err(); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Future.Err() = %v, want %v", err, tt.wantErr)
+			}
+
+			if n := atomic.LoadInt32(&attempts); n != tt.wantAttempts {
+				t.Errorf("attempts = %d, want %d", n, tt.wantAttempts)
+			}
+		})
+	}
+}
+
+func TestPool_SubmitAcked_cancel(t *testing.T) {
+	p := testPool(context.Background(), 1, testDefaultNumTasks, false)
+
+	release := make(chan struct{})
+	var attempts int32
+	f, _ := p.SubmitAcked(func(d *Delivery) {
+		atomic.AddInt32(&attempts, 1)
+		<-release
+		d.Nack()
+	})
+
+	time.Sleep(5 * time.Millisecond)
+	if !f.Cancel() {
+		t.Error("Future.Cancel() = false before the task was acknowledged")
+	}
+	close(release)
+
+	if err := p.Wait(); err != nil {
+		t.Errorf("Pool.Wait() = %v, want nil", err)
+	}
+
+	if n := atomic.LoadInt32(&attempts); n != 1 {
+		t.Errorf("attempts = %d, want 1", n)
+	}
+
+	if _, err := New(1, WithRedelivery(-1, 0)); !errors.Is(err, ErrInvalidRedelivery) {
+		t.Errorf("New() error = %v, want %v", err, ErrInvalidRedelivery)
+	}
+}
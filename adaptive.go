@@ -0,0 +1,98 @@
+package gowp
+
+import (
+	"math"
+	"time"
+)
+
+const (
+	adaptiveBackoff = 0.9 // factor applied to the limit when latency degrades.
+	adaptiveDrift   = 64  // the baseline moves 1/adaptiveDrift of the way towards slower samples.
+)
+
+// adaptiveLimit adjusts the number of tasks allowed to run at once, see WithAdaptiveConcurrency.
+type adaptiveLimit struct {
+	min, max  int
+	tolerance float64 // latency above tolerance times the baseline signals saturation.
+
+	limit    float64       // current limit, between min and max.
+	baseline time.Duration // latency of a healthy task, zero until the first sample.
+}
+
+// WithAdaptiveConcurrency returns an Option that adapts the number of tasks running at once to the
+// latency of the tasks, like TCP congestion control (AIMD). The pool keeps track of a baseline latency,
+// the lowest it has seen, slowly drifting towards recent samples so that it follows lasting changes.
+// A task slower than tolerance times the baseline is taken as a sign that whatever the tasks call into
+// is saturated, and the limit is cut by 10%. Otherwise the limit grows by one task per limit tasks.
+// The limit stays between min and the number of workers, which is also where it starts.
+//
+// min should be between one and the number of workers and tolerance greater than one,
+// otherwise ErrInvalidAdaptive will be returned on Pool initialization.
+func WithAdaptiveConcurrency(min int, tolerance float64) Option {
+	return func(o *config) {
+		o.adaptive = &adaptiveLimit{min: min, tolerance: tolerance}
+	}
+}
+
+func newAdaptiveLimit(a adaptiveLimit, numWorkers int) *adaptiveLimit {
+	a.max = numWorkers
+	a.limit = float64(numWorkers)
+
+	return &a
+}
+
+// allowed returns the number of tasks that may run at once.
+func (a *adaptiveLimit) allowed() int {
+	return int(a.limit)
+}
+
+// sample updates the limit with the latency of a finished task.
+func (a *adaptiveLimit) sample(took time.Duration) {
+	switch {
+	case a.baseline == 0 || took < a.baseline:
+		a.baseline = took
+	default:
+		a.baseline += (took - a.baseline) / adaptiveDrift
+	}
+
+	if float64(took) > a.tolerance*float64(a.baseline) {
+		a.limit = math.Max(float64(a.min), a.limit*adaptiveBackoff)
+		return
+	}
+
+	a.limit = math.Min(float64(a.max), a.limit+1/a.limit)
+}
+
+// limited reports whether the number of tasks running at once is limited, by WithAdaptiveConcurrency or
+// WithGCPressureLimit. Running tasks are tracked then.
+func (p *Pool) limited() bool {
+	return p.adaptive != nil || p.gc != nil
+}
+
+// allowed returns the number of tasks that may run at once, the lower of the limits, zero if there is none.
+// p.mu must be held.
+func (p *Pool) allowed() int {
+	n := 0
+	if p.adaptive != nil {
+		n = p.adaptive.allowed()
+	}
+
+	if p.gc != nil && (n == 0 || p.gc.limit < n) {
+		n = p.gc.limit
+	}
+
+	return n
+}
+
+// adapt accounts for a job processed by a worker. took is considered only if the task ran.
+func (p *Pool) adapt(took time.Duration, ran bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.running--
+	if ran && p.adaptive != nil {
+		p.adaptive.sample(took)
+	}
+
+	p.ready.Broadcast() // workers held back by the limit may proceed, an idle one might be woken by Signal instead.
+}
@@ -0,0 +1,88 @@
+package gowp
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestAdaptiveLimit_sample(t *testing.T) {
+	tests := []struct {
+		name    string
+		samples []time.Duration
+		want    int
+	}{
+		{name: "healthy", samples: []time.Duration{10, 10, 11, 10}, want: 8},
+		{name: "saturated", samples: []time.Duration{10, 50, 50, 50, 50}, want: 5},
+		{name: "floor", samples: append([]time.Duration{10}, repeat(100, 50)...), want: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := newAdaptiveLimit(adaptiveLimit{min: 2, tolerance: 2}, 8)
+			for _, d := range tt.samples {
+				a.sample(d)
+			}
+
+			if got := a.allowed(); got != tt.want {
+				t.Errorf("adaptiveLimit.allowed() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func repeat(d time.Duration, n int) []time.Duration {
+	ds := make([]time.Duration, n)
+	for i := range ds {
+		ds[i] = d
+	}
+
+	return ds
+}
+
+func TestWithAdaptiveConcurrency(t *testing.T) {
+	const numWorkers = 8
+
+	p := testPool(context.Background(), numWorkers, 100, false, WithAdaptiveConcurrency(1, 2))
+
+	// the first tasks are fast, the following ones slow down as if a downstream service was saturated.
+	var done, running int32
+	for i := 0; i < 100; i++ {
+		_ = p.Submit(func() error {
+			n := atomic.AddInt32(&running, 1)
+			defer atomic.AddInt32(&running, -1)
+
+			d := time.Millisecond
+			if atomic.AddInt32(&done, 1) > 10 {
+				d = time.Duration(n) * 5 * time.Millisecond // latency grows with concurrency.
+			}
+
+			time.Sleep(d)
+
+			return nil
+		})
+	}
+
+	if err := p.Wait(); err != nil {
+		t.Fatalf("Pool.Wait() error = %v", err)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.running != 0 {
+		t.Errorf("running = %d after Wait, want 0", p.running)
+	}
+
+	if got := p.adaptive.allowed(); got >= numWorkers {
+		t.Errorf("adaptive limit = %d, want less than %d under saturation", got, numWorkers)
+	}
+}
+
+func TestWithAdaptiveConcurrency_validation(t *testing.T) {
+	_, err := New(testDefaultNumTasks, WithNumWorkers(4), WithAdaptiveConcurrency(5, 2))
+	if !errors.Is(err, ErrInvalidAdaptive) {
+		t.Errorf("New() = %v, want %v", err, ErrInvalidAdaptive)
+	}
+}
@@ -0,0 +1,323 @@
+// Package admin exposes gowp pools over HTTP, so that operators can inspect and control them in running services.
+//
+// The handler of a pool serves JSON on the following paths:
+//
+//	GET  /stats                 workers, queue depth, task counters and the latency histograms.
+//	GET  /health                200 if the pool is healthy, 503 otherwise, see gowp.Pool.Healthy.
+//	POST /pause                 stops the workers from picking new tasks.
+//	POST /resume                lets the workers pick tasks again.
+//	POST /resize?workers=N      changes the number of workers, up to a maximum, see WithMaxWorkers.
+//
+// Actions respond with the stats of the pool once applied. Errors are reported as {"error": "..."}.
+//
+// Registry serves all the pools registered with gowp.Register instead: GET / lists their stats by name and the
+// paths above are served under /{name}, e.g. POST /ingest/pause.
+//
+// Example:
+//	wp, _ := gowp.New(100)
+//
+//	http.Handle("/admin/pools/ingest/", http.StripPrefix("/admin/pools/ingest", admin.Handler(wp)))
+package admin // import "github.com/akshaybharambe14/gowp/admin"
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/akshaybharambe14/gowp"
+)
+
+// interface guards
+var _ Pool = (*gowp.Pool)(nil)
+
+// growth bounds a resize by default: to this many times the current number of workers, or of CPUs if greater.
+const growth = 4
+
+type (
+	// Stats is the JSON representation of gowp.Stats.
+	Stats struct {
+		Workers Workers `json:"workers"`
+		Queue   Queue   `json:"queue"`
+		Tasks   Tasks   `json:"tasks"`
+		Paused  bool    `json:"paused"`
+		Closed  bool    `json:"closed"`
+
+		Throttled bool `json:"throttled"` // see gowp.WithMemoryThrottle.
+
+		QueueWait Histogram `json:"queue_wait"` // time tasks waited for a worker.
+		Duration  Histogram `json:"duration"`   // time tasks took to execute.
+	}
+
+	// Histogram is the JSON representation of gowp.Histogram, with cumulative buckets like Prometheus:
+	// each bucket counts the durations up to its bound, Count includes the durations above the last one.
+	// The percentiles are estimated with gowp.Histogram.Quantile.
+	Histogram struct {
+		Buckets    []Bucket `json:"buckets"`
+		Count      int      `json:"count"`
+		SumSeconds float64  `json:"sum_seconds"`
+		P50Seconds float64  `json:"p50_seconds"`
+		P95Seconds float64  `json:"p95_seconds"`
+		P99Seconds float64  `json:"p99_seconds"`
+	}
+
+	// Bucket is a bucket of a Histogram.
+	Bucket struct {
+		LESeconds float64 `json:"le_seconds"`
+		Count     int     `json:"count"`
+	}
+
+	// Workers counts the workers of a pool by state.
+	Workers struct {
+		Total int `json:"total"`
+		Idle  int `json:"idle"`
+		Busy  int `json:"busy"`
+	}
+
+	// Queue is the depth of the queue of a pool.
+	Queue struct {
+		Queued int `json:"queued"`
+		Held   int `json:"held"`
+	}
+
+	// Tasks counts the tasks of a pool by outcome.
+	Tasks struct {
+		Submitted int `json:"submitted"`
+		Succeeded int `json:"succeeded"`
+		Failed    int `json:"failed"`
+		Ignored   int `json:"ignored"`
+		Canceled  int `json:"canceled"`
+		Discarded int `json:"discarded"`
+		Expired   int `json:"expired"`
+	}
+
+	// Pool is the part of a pool the handler needs. Both gowp.Pool and the Pool of gowp/v2 implement it.
+	Pool interface {
+		Checker
+		Stats() gowp.Stats
+		Pause()
+		Resume()
+		Resize(n int) error
+	}
+
+	// Checker reports the health of a pool, see gowp.Pool.Healthy.
+	Checker interface {
+		Healthy() error
+	}
+
+	// Option configures a handler.
+	Option func(cfg *config)
+
+	config struct {
+		maxWorkers int // see WithMaxWorkers.
+	}
+
+	handler struct {
+		p   Pool
+		mux *http.ServeMux
+		cfg config
+	}
+)
+
+// WithMaxWorkers returns an Option that sets the maximum number of workers a pool can be resized to, larger
+// sizes are rejected with 400 Bad Request. It defaults to 4 times the current number of workers, or of CPUs
+// if greater, so that a single request can't spawn an arbitrary number of goroutines.
+func WithMaxWorkers(n int) Option {
+	return func(cfg *config) {
+		if n > 0 {
+			cfg.maxWorkers = n
+		}
+	}
+}
+
+// Handler returns an http.Handler that serves the stats of p and the actions on it, see the package documentation.
+// Mount it with http.StripPrefix to serve several pools from the same server.
+func Handler(p Pool, opts ...Option) http.Handler {
+	h := &handler{p: p, mux: http.NewServeMux()}
+	for _, opt := range opts {
+		opt(&h.cfg)
+	}
+
+	h.mux.HandleFunc("/stats", h.only(http.MethodGet, func(r *http.Request) error {
+		return nil
+	}))
+
+	h.mux.Handle("/health", Health(p))
+
+	h.mux.HandleFunc("/pause", h.only(http.MethodPost, func(r *http.Request) error {
+		p.Pause()
+		return nil
+	}))
+
+	h.mux.HandleFunc("/resume", h.only(http.MethodPost, func(r *http.Request) error {
+		p.Resume()
+		return nil
+	}))
+
+	h.mux.HandleFunc("/resize", h.only(http.MethodPost, func(r *http.Request) error {
+		n, err := strconv.Atoi(r.URL.Query().Get("workers"))
+		if err != nil {
+			return gowp.ErrInvalidWorkerCnt
+		}
+
+		if limit := h.maxWorkers(); n > limit {
+			return fmt.Errorf("%w: %d exceeds the maximum of %d", gowp.ErrInvalidWorkerCnt, n, limit)
+		}
+
+		return p.Resize(n)
+	}))
+
+	return h
+}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mux.ServeHTTP(w, r)
+}
+
+// maxWorkers returns the maximum number of workers the pool can be resized to.
+func (h *handler) maxWorkers() int {
+	if h.cfg.maxWorkers > 0 {
+		return h.cfg.maxWorkers
+	}
+
+	n := h.p.Stats().Workers
+	if cpus := runtime.NumCPU(); cpus > n {
+		n = cpus
+	}
+
+	return growth * n
+}
+
+// only serves the requests with the given method by applying action and responding with the stats of the pool.
+func (h *handler) only(method string, action func(r *http.Request) error) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != method {
+			w.Header().Set("Allow", method)
+			writeJSON(w, http.StatusMethodNotAllowed, errorBody{Error: http.StatusText(http.StatusMethodNotAllowed)})
+			return
+		}
+
+		if err := action(r); err != nil {
+			writeJSON(w, statusOf(err), errorBody{Error: err.Error()})
+			return
+		}
+
+		writeJSON(w, http.StatusOK, NewStats(h.p.Stats()))
+	}
+}
+
+// Registry returns an http.Handler that serves the pools registered with gowp.Register, see the package
+// documentation. Pools registered later are picked up as they come. opts apply to the handler of each pool.
+func Registry(opts ...Option) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name, rest, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+		if name == "" {
+			if r.Method != http.MethodGet {
+				w.Header().Set("Allow", http.MethodGet)
+				writeJSON(w, http.StatusMethodNotAllowed, errorBody{Error: http.StatusText(http.StatusMethodNotAllowed)})
+				return
+			}
+
+			pools := gowp.Registered()
+			stats := make(map[string]Stats, len(pools))
+			for name, p := range pools {
+				stats[name] = NewStats(p.Stats())
+			}
+
+			writeJSON(w, http.StatusOK, stats)
+			return
+		}
+
+		p, ok := gowp.Lookup(name)
+		if !ok {
+			writeJSON(w, http.StatusNotFound, errorBody{Error: "unknown pool " + strconv.Quote(name)})
+			return
+		}
+
+		r2 := r.Clone(r.Context())
+		r2.URL.Path = "/" + rest
+		r2.URL.RawPath = ""
+		Handler(p, opts...).ServeHTTP(w, r2)
+	})
+}
+
+// Health returns a tiny http.Handler for liveness and readiness probes. It responds with 200 and {"status": "ok"}
+// if c is healthy, and with 503 and the reason otherwise.
+func Health(c Checker) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := c.Healthy(); err != nil {
+			writeJSON(w, http.StatusServiceUnavailable, errorBody{Error: err.Error()})
+			return
+		}
+
+		writeJSON(w, http.StatusOK, struct {
+			Status string `json:"status"`
+		}{Status: "ok"})
+	})
+}
+
+// NewStats converts s to its JSON representation.
+func NewStats(s gowp.Stats) Stats {
+	return Stats{
+		Workers: Workers{Total: s.Workers, Idle: s.Idle, Busy: s.Workers - s.Idle},
+		Queue:   Queue{Queued: s.Queued, Held: s.Held},
+		Tasks: Tasks{
+			Submitted: s.Submitted,
+			Succeeded: s.Succeeded,
+			Failed:    s.Failed,
+			Ignored:   s.Ignored,
+			Canceled:  s.Canceled,
+			Discarded: s.Discarded,
+			Expired:   s.Expired,
+		},
+		Paused:    s.Paused,
+		Closed:    s.Closed,
+		Throttled: s.Throttled,
+		QueueWait: NewHistogram(s.QueueWait),
+		Duration:  NewHistogram(s.Duration),
+	}
+}
+
+// NewHistogram converts h to its JSON representation.
+func NewHistogram(h gowp.Histogram) Histogram {
+	j := Histogram{
+		Buckets:    make([]Bucket, len(h.Bounds)),
+		Count:      h.Count,
+		SumSeconds: h.Sum.Seconds(),
+		P50Seconds: h.Quantile(0.5).Seconds(),
+		P95Seconds: h.Quantile(0.95).Seconds(),
+		P99Seconds: h.Quantile(0.99).Seconds(),
+	}
+
+	n := 0
+	for i, bound := range h.Bounds {
+		n += h.Counts[i]
+		j.Buckets[i] = Bucket{LESeconds: bound.Seconds(), Count: n}
+	}
+
+	return j
+}
+
+type errorBody struct {
+	Error string `json:"error"`
+}
+
+func statusOf(err error) int {
+	switch {
+	case errors.Is(err, gowp.ErrInvalidWorkerCnt):
+		return http.StatusBadRequest
+	case errors.Is(err, gowp.ErrPoolClosed):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
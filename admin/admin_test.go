@@ -0,0 +1,150 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/akshaybharambe14/gowp"
+)
+
+func TestHandler(t *testing.T) {
+	p, err := gowp.New(10, gowp.WithNumWorkers(1))
+	if err != nil {
+		t.Fatalf("gowp.New() error = %v", err)
+	}
+
+	h := Handler(p)
+
+	tests := []struct {
+		name       string
+		method     string
+		target     string
+		wantStatus int
+		check      func(s Stats) bool
+	}{
+		{name: "stats", method: http.MethodGet, target: "/stats", wantStatus: http.StatusOK,
+			check: func(s Stats) bool { return s.Workers.Total == 1 && !s.Paused && len(s.QueueWait.Buckets) > 0 && len(s.Duration.Buckets) > 0 }},
+		{name: "health", method: http.MethodGet, target: "/health", wantStatus: http.StatusOK},
+		{name: "pause", method: http.MethodPost, target: "/pause", wantStatus: http.StatusOK,
+			check: func(s Stats) bool { return s.Paused }},
+		{name: "resume", method: http.MethodPost, target: "/resume", wantStatus: http.StatusOK,
+			check: func(s Stats) bool { return !s.Paused }},
+		{name: "resize", method: http.MethodPost, target: "/resize?workers=3", wantStatus: http.StatusOK,
+			check: func(s Stats) bool { return s.Workers.Total == 3 }},
+		{name: "invalid size", method: http.MethodPost, target: "/resize?workers=x", wantStatus: http.StatusBadRequest},
+		{name: "size above maximum", method: http.MethodPost, target: "/resize?workers=1000000", wantStatus: http.StatusBadRequest},
+		{name: "wrong method", method: http.MethodGet, target: "/pause", wantStatus: http.StatusMethodNotAllowed},
+		{name: "unknown path", method: http.MethodGet, target: "/unknown", wantStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, httptest.NewRequest(tt.method, tt.target, nil))
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body)
+			}
+
+			if tt.check == nil {
+				return
+			}
+
+			var s Stats
+			if err := json.Unmarshal(w.Body.Bytes(), &s); err != nil {
+				t.Fatalf("json.Unmarshal() error = %v", err)
+			}
+
+			if !tt.check(s) {
+				t.Errorf("unexpected stats %+v", s)
+			}
+		})
+	}
+
+	_ = p.Wait()
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/resize?workers=2", nil))
+	if w.Code != http.StatusConflict {
+		t.Errorf("status after Wait = %d, want %d", w.Code, http.StatusConflict)
+	}
+}
+
+func TestHealth(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	p, _ := gowp.New(1, gowp.WithContext(ctx))
+	h := Health(p)
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+
+	cancel()
+	for deadline := time.Now().Add(time.Second); p.Healthy() == nil && time.Now().Before(deadline); {
+		time.Sleep(time.Millisecond) // the pool stops asynchronously, Wait could complete it normally before.
+	}
+	_ = p.Wait()
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	if w.Code != http.StatusServiceUnavailable || !strings.Contains(w.Body.String(), gowp.ErrPoolStopped.Error()) {
+		t.Errorf("status = %d with %s, want %d", w.Code, w.Body, http.StatusServiceUnavailable)
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	p, err := gowp.New(10, gowp.WithNumWorkers(1))
+	if err != nil {
+		t.Fatalf("gowp.New() error = %v", err)
+	}
+	defer func() {
+		p.Close()
+		_ = p.Wait()
+	}()
+
+	if err := gowp.Register("ingest", p); err != nil {
+		t.Fatalf("gowp.Register() error = %v", err)
+	}
+	t.Cleanup(func() { gowp.Unregister("ingest") })
+
+	h := Registry(WithMaxWorkers(2))
+
+	tests := []struct {
+		name       string
+		method     string
+		target     string
+		wantStatus int
+		wantBody   string
+	}{
+		{name: "list", method: http.MethodGet, target: "/", wantStatus: http.StatusOK, wantBody: `"ingest":{"workers":{"total":1`},
+		{name: "list wrong method", method: http.MethodPost, target: "/", wantStatus: http.StatusMethodNotAllowed},
+		{name: "pool stats", method: http.MethodGet, target: "/ingest/stats", wantStatus: http.StatusOK, wantBody: `"paused":false`},
+		{name: "pool action", method: http.MethodPost, target: "/ingest/pause", wantStatus: http.StatusOK, wantBody: `"paused":true`},
+		{name: "pool size above maximum", method: http.MethodPost, target: "/ingest/resize?workers=3", wantStatus: http.StatusBadRequest},
+		{name: "unknown pool", method: http.MethodGet, target: "/billing/stats", wantStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, httptest.NewRequest(tt.method, tt.target, nil))
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body)
+			}
+
+			if !strings.Contains(w.Body.String(), tt.wantBody) {
+				t.Errorf("body = %s, want it to contain %s", w.Body, tt.wantBody)
+			}
+		})
+	}
+
+	p.Resume()
+}
@@ -0,0 +1,34 @@
+package gowp
+
+import "time"
+
+// TaskAffinity returns a TaskOption that sets the affinity key of the task, a hint that tasks with the same key
+// should run on the same worker, e.g. so that state the worker keeps for them, such as caches or sessions, stays
+// warm. Once a worker has run a task with a key, it picks a queued task with that key, if any, before the other
+// tasks: the one the scheduling policy would run first among them, within the quotas of WithTenantQuotas. Tasks
+// that have waited for the maxAge of WithAging still go first. Tasks with a key are still run by any worker,
+// e.g. when the worker that ran the previous one is busy. Looking for a related task scans the queue, keys are
+// best kept for pools whose queue is short.
+func TaskAffinity(key string) TaskOption {
+	return func(j *job) {
+		j.affinity = key
+	}
+}
+
+// related pops the next queued job with the given affinity key that the class limits admit, nil if there is none
+// or key is empty. A job that has waited for maxAge counts as related, so that it still goes first. p.mu must be held.
+func (p *Pool) related(key string) *job {
+	if key == "" || p.queue.len() == 0 {
+		return nil
+	}
+
+	var now time.Time
+	if p.maxAge > 0 {
+		now = p.clock.Now()
+	}
+
+	return p.queue.popIf(func(j *job) bool {
+		aged := p.maxAge > 0 && now.Sub(j.submittedAt) >= p.maxAge
+		return (j.affinity == key || aged) && p.admits(j)
+	})
+}
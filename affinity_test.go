@@ -0,0 +1,94 @@
+package gowp
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestTaskAffinity(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+		keys []string     // affinity keys of the tasks, in submission order. The first one runs while the others are queued.
+		more []TaskOption // more options of the tasks, by index. nil, if none.
+		want string       // order in which the tasks ran, by their index.
+	}{
+		{name: "related first", keys: []string{"a", "b", "a", "b"}, want: "0213"},
+		{name: "run of related", keys: []string{"a", "b", "a", "a", "b"}, want: "02314"},
+		{name: "no key", keys: []string{"", "", "", ""}, want: "0123"},
+		{name: "no related queued", keys: []string{"a", "b", "c", "b"}, want: "0132"},
+		{
+			name: "related of another tenant",
+			opts: []Option{WithTenantQuotas(1, 0)},
+			keys: []string{"k", "k", "a"},
+			more: []TaskOption{TaskTenant("x"), TaskTenant("y"), TaskTenant("x")},
+			want: "012",
+		},
+		{
+			name: "related in priority order",
+			opts: []Option{WithSchedulingPolicy(HighestPriority)},
+			keys: []string{"a", "a", "a", "b"},
+			more: []TaskOption{TaskPriority(9), TaskPriority(1), TaskPriority(5), TaskPriority(9)},
+			want: "0213",
+		},
+		{
+			name: "related of another label",
+			opts: []Option{WithWeightedFairQueuing(map[string]int{"x": 1, "y": 1})},
+			keys: []string{"a", "b", "a", "b"},
+			more: []TaskOption{TaskLabel("x"), TaskLabel("x"), TaskLabel("y"), TaskLabel("y")},
+			want: "0213",
+		},
+		{
+			name: "aged unrelated first",
+			opts: []Option{WithSchedulingPolicy(ShortestFirst), WithAging(time.Nanosecond)},
+			keys: []string{"a", "b", "a"},
+			want: "012",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := testPool(context.Background(), 1, testDefaultNumTasks, false, tt.opts...)
+
+			var (
+				mu  sync.Mutex
+				ran []string
+			)
+			release := make(chan struct{})
+			for i, key := range tt.keys {
+				i := i
+				opts := []TaskOption{TaskAffinity(key)}
+				if tt.more != nil {
+					opts = append(opts, tt.more[i])
+				}
+
+				_, err := p.SubmitFuture(func() error {
+					if i == 0 {
+						<-release
+					}
+
+					mu.Lock()
+					ran = append(ran, string(rune('0'+i)))
+					mu.Unlock()
+
+					return nil
+				}, opts...)
+				if err != nil {
+					t.Fatalf("Pool.SubmitFuture() error = %v", err)
+				}
+			}
+			close(release)
+
+			if err := p.Wait(); err != nil {
+				t.Fatalf("Pool.Wait() error = %v", err)
+			}
+
+			if got := strings.Join(ran, ""); got != tt.want {
+				t.Errorf("tasks ran in order %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package gowp
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	autoscaleInterval = time.Second // period over which the latency of the tasks is measured.
+	autoscaleSamples  = 1024        // latencies kept per period, the oldest ones are overwritten beyond.
+)
+
+// autoscaler sizes the pool to a latency objective, see WithLatencyAutoscaler.
+type autoscaler struct {
+	target     time.Duration
+	percentile float64
+	min, max   int
+	interval   time.Duration
+
+	mu      sync.Mutex
+	samples []time.Duration // latencies of the tasks finished during the current period.
+	next    int             // index of the sample to overwrite once samples is full.
+}
+
+// WithLatencyAutoscaler returns an Option that resizes the pool to keep the given percentile of the latency of the
+// tasks within target, e.g. WithLatencyAutoscaler(100*time.Millisecond, 0.95, 2, 32) targets a p95 of 100ms.
+// The latency of a task is the time from its submission to its end, waiting in the queue included.
+// Every second, the percentile of the tasks finished over the second is measured: above target while tasks are
+// queued, one worker is added, below half of target, one worker is removed, like with Resize. In between, or if
+// no task finished, the size is kept. The pool starts with the number of workers and stays between min and max.
+// Adding workers only helps as long as the tasks are not slowed down by what they call into, see
+// WithAdaptiveConcurrency for that case. It has no effect along with WithInlineExecution.
+//
+// target should be greater than zero, percentile within (0, 1] and the number of workers between min and max,
+// with min greater than zero, otherwise ErrInvalidAutoscaler will be returned on Pool initialization.
+func WithLatencyAutoscaler(target time.Duration, percentile float64, min, max int) Option {
+	return func(o *config) {
+		o.autoscale = &autoscaler{target: target, percentile: percentile, min: min, max: max, interval: autoscaleInterval}
+	}
+}
+
+func newAutoscaler(a *autoscaler) *autoscaler {
+	return &autoscaler{
+		target:     a.target,
+		percentile: a.percentile,
+		min:        a.min,
+		max:        a.max,
+		interval:   a.interval,
+		samples:    make([]time.Duration, 0, autoscaleSamples),
+	}
+}
+
+// record accounts for the latency of a finished task, from its submission to its end.
+func (a *autoscaler) record(latency time.Duration) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if len(a.samples) < cap(a.samples) {
+		a.samples = append(a.samples, latency)
+		return
+	}
+
+	a.samples[a.next] = latency
+	a.next = (a.next + 1) % len(a.samples)
+}
+
+// latency returns the percentile of the latencies recorded since the last call, ok is false if there are none.
+func (a *autoscaler) latency() (d time.Duration, ok bool) {
+	a.mu.Lock()
+	samples := append([]time.Duration(nil), a.samples...)
+	a.samples, a.next = a.samples[:0], 0
+	a.mu.Unlock()
+
+	if len(samples) == 0 {
+		return 0, false
+	}
+
+	sort.Slice(samples, func(i, j int) bool { return samples[i] < samples[j] })
+	i := int(math.Ceil(a.percentile*float64(len(samples)))) - 1
+	if i < 0 {
+		i = 0
+	}
+
+	return samples[i], true
+}
+
+// size returns the number of workers the pool should run, given its current size, the measured latency
+// and whether tasks are queued.
+func (a *autoscaler) size(n int, latency time.Duration, queued bool) int {
+	switch {
+	case latency > a.target && queued && n < a.max:
+		return n + 1
+	case latency < a.target/2 && n > a.min:
+		return n - 1
+	}
+
+	return n
+}
+
+// autoscale resizes the pool to the latency of its tasks, until the pool stops.
+func (p *Pool) autoscale() {
+	a := p.scaler
+
+	t := p.clock.NewTicker(a.interval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-p.quit:
+			return
+		case <-p.exitFromErrG:
+			return
+		case <-t.C():
+			latency, ok := a.latency()
+			if !ok {
+				continue
+			}
+
+			p.mu.Lock()
+			n := p.target
+			p.mu.Unlock()
+
+			if m := a.size(n, latency, p.Pending() > 0); m != n {
+				if p.resize(m) != nil {
+					return // the workers have exited.
+				}
+
+				p.emit(Event{Kind: EventResized, Workers: m})
+			}
+		}
+	}
+}
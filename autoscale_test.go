@@ -0,0 +1,128 @@
+package gowp
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestAutoscaler_size(t *testing.T) {
+	a := autoscaler{target: 100 * time.Millisecond, min: 2, max: 4}
+
+	tests := []struct {
+		name    string
+		n       int
+		latency time.Duration
+		queued  bool
+		want    int
+	}{
+		{name: "slow with a backlog", n: 2, latency: 150 * time.Millisecond, queued: true, want: 3},
+		{name: "slow at max", n: 4, latency: 150 * time.Millisecond, queued: true, want: 4},
+		{name: "slow without a backlog", n: 2, latency: 150 * time.Millisecond, want: 2},
+		{name: "within target", n: 3, latency: 80 * time.Millisecond, queued: true, want: 3},
+		{name: "well below target", n: 3, latency: 20 * time.Millisecond, queued: true, want: 2},
+		{name: "well below target at min", n: 2, latency: 20 * time.Millisecond, want: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := a.size(tt.n, tt.latency, tt.queued); got != tt.want {
+				t.Errorf("autoscaler.size(%d, %v, %v) = %d, want %d", tt.n, tt.latency, tt.queued, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAutoscaler_latency(t *testing.T) {
+	tests := []struct {
+		name       string
+		percentile float64
+		samples    int // latencies recorded, 1ms to samples ms.
+		want       time.Duration
+		wantOK     bool
+	}{
+		{name: "no samples", percentile: 0.95},
+		{name: "p95", percentile: 0.95, samples: 100, want: 95 * time.Millisecond, wantOK: true},
+		{name: "p50", percentile: 0.5, samples: 10, want: 5 * time.Millisecond, wantOK: true},
+		{name: "max", percentile: 1, samples: 10, want: 10 * time.Millisecond, wantOK: true},
+		{name: "oldest overwritten", percentile: 0.0005, samples: autoscaleSamples + 10, want: 11 * time.Millisecond, wantOK: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := newAutoscaler(&autoscaler{percentile: tt.percentile})
+			for i := 1; i <= tt.samples; i++ {
+				a.record(time.Duration(i) * time.Millisecond)
+			}
+
+			got, ok := a.latency()
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("autoscaler.latency() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOK)
+			}
+
+			if _, ok := a.latency(); ok {
+				t.Error("autoscaler.latency() ok = true after the samples were consumed, want false")
+			}
+		})
+	}
+}
+
+func TestWithLatencyAutoscaler(t *testing.T) {
+	fast := func(o *config) { o.autoscale.interval = time.Millisecond }
+
+	p := testPool(context.Background(), 1, testDefaultNumTasks, false, WithLatencyAutoscaler(time.Millisecond, 0.95, 1, 3), fast)
+
+	for i := 0; i < testDefaultNumTasks; i++ {
+		if err := p.Submit(func() error {
+			time.Sleep(5 * time.Millisecond)
+			return nil
+		}); err != nil {
+			t.Fatalf("Pool.Submit() error = %v", err)
+		}
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for p.Stats().Workers != 3 {
+		if time.Now().After(deadline) {
+			t.Fatalf("Stats().Workers = %d, want 3", p.Stats().Workers)
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	if err := p.Wait(); err != nil {
+		t.Fatalf("Pool.Wait() error = %v", err)
+	}
+
+	if _, err := New(1, WithNumWorkers(4), WithLatencyAutoscaler(time.Second, 0.95, 1, 3)); !errors.Is(err, ErrInvalidAutoscaler) {
+		t.Errorf("New() error = %v, want %v", err, ErrInvalidAutoscaler)
+	}
+}
+
+func TestWithLatencyAutoscaler_queueing(t *testing.T) {
+	fast := func(o *config) { o.autoscale.interval = 5 * time.Millisecond }
+
+	// the tasks run well within target, it is only missed because of the time they wait in the queue.
+	p := testPool(context.Background(), 1, 200, false, WithLatencyAutoscaler(20*time.Millisecond, 0.95, 1, 3), fast)
+
+	for i := 0; i < 200; i++ {
+		if err := p.Submit(func() error {
+			time.Sleep(time.Millisecond)
+			return nil
+		}); err != nil {
+			t.Fatalf("Pool.Submit() error = %v", err)
+		}
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for p.Stats().Workers != 3 {
+		if time.Now().After(deadline) {
+			t.Fatalf("Stats().Workers = %d, want 3", p.Stats().Workers)
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	if err := p.Wait(); err != nil {
+		t.Fatalf("Pool.Wait() error = %v", err)
+	}
+}
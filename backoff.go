@@ -0,0 +1,117 @@
+package gowp
+
+import (
+	"math/rand"
+	"time"
+)
+
+// Backoff tells how long to wait before delivering a task again, see WithBackoff.
+type Backoff interface {
+	// Delay returns the time to wait after the given delivery, starting at 1, wasn't acknowledged.
+	Delay(attempt int) time.Duration
+}
+
+type (
+	constantBackoff struct {
+		d time.Duration
+	}
+
+	exponentialBackoff struct {
+		base, max time.Duration
+		jitter    bool
+	}
+
+	fibonacciBackoff struct {
+		base, max time.Duration
+	}
+)
+
+// WithBackoff returns an Option that sets the Backoff of the redeliveries of the tasks submitted with
+// SubmitAcked, TaskBackoff overrides it for a single task. By default, and if b is nil, tasks are delivered
+// again right away.
+func WithBackoff(b Backoff) Option {
+	return func(o *config) {
+		o.backoff = b
+	}
+}
+
+// TaskBackoff returns a TaskOption that sets the Backoff of the redeliveries of the task, see WithBackoff.
+func TaskBackoff(b Backoff) TaskOption {
+	return func(j *job) {
+		j.backoff = b
+	}
+}
+
+// taskBackoff returns the Backoff set by opts, nil if none.
+func taskBackoff(opts []TaskOption) Backoff {
+	var j job
+	for _, opt := range opts {
+		opt(&j)
+	}
+
+	return j.backoff
+}
+
+// ConstantBackoff returns a Backoff waiting d before every redelivery.
+func ConstantBackoff(d time.Duration) Backoff {
+	return constantBackoff{d: d}
+}
+
+// ExponentialBackoff returns a Backoff waiting base before the first redelivery and doubling the delay for
+// every further one, up to max. max <= 0 means no limit.
+func ExponentialBackoff(base, max time.Duration) Backoff {
+	return exponentialBackoff{base: base, max: max}
+}
+
+// ExponentialJitterBackoff returns a Backoff like ExponentialBackoff, that waits a random delay between zero
+// and the exponential one, so that tasks failing together don't all come back at the same time.
+func ExponentialJitterBackoff(base, max time.Duration) Backoff {
+	return exponentialBackoff{base: base, max: max, jitter: true}
+}
+
+// FibonacciBackoff returns a Backoff waiting base times the Fibonacci numbers, i.e. base, base, 2*base,
+// 3*base, 5*base and so on, up to max. It grows slower than ExponentialBackoff. max <= 0 means no limit.
+func FibonacciBackoff(base, max time.Duration) Backoff {
+	return fibonacciBackoff{base: base, max: max}
+}
+
+func (b constantBackoff) Delay(int) time.Duration {
+	return b.d
+}
+
+func (b exponentialBackoff) Delay(attempt int) time.Duration {
+	d := b.base
+	for i := 1; i < attempt && !capped(d, b.max); i++ {
+		d *= 2
+	}
+
+	d = limit(d, b.max)
+	if b.jitter && d > 0 {
+		d = time.Duration(rand.Int63n(int64(d) + 1))
+	}
+
+	return d
+}
+
+func (b fibonacciBackoff) Delay(attempt int) time.Duration {
+	prev, d := time.Duration(0), b.base
+	for i := 1; i < attempt && !capped(d, b.max); i++ {
+		prev, d = d, prev+d
+	}
+
+	return limit(d, b.max)
+}
+
+// capped reports whether a growing delay d has reached max, or is about to overflow.
+func capped(d, max time.Duration) bool {
+	return d <= 0 || (max > 0 && d >= max) || d > time.Duration(1<<62)
+}
+
+// limit returns d, capped to max if there is one.
+func limit(d, max time.Duration) time.Duration {
+	if max > 0 && d > max {
+		return max
+	}
+
+	return d
+}
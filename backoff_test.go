@@ -0,0 +1,127 @@
+package gowp
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestBackoff_Delay(t *testing.T) {
+	tests := []struct {
+		name string
+		b    Backoff
+		want []time.Duration // delays after the first attempts.
+	}{
+		{
+			name: "constant",
+			b:    ConstantBackoff(time.Second),
+			want: []time.Duration{time.Second, time.Second, time.Second},
+		},
+		{
+			name: "exponential",
+			b:    ExponentialBackoff(time.Second, 5*time.Second),
+			want: []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second},
+		},
+		{
+			name: "exponential without limit",
+			b:    ExponentialBackoff(time.Millisecond, 0),
+			want: []time.Duration{time.Millisecond, 2 * time.Millisecond, 4 * time.Millisecond, 8 * time.Millisecond},
+		},
+		{
+			name: "fibonacci",
+			b:    FibonacciBackoff(time.Second, 6*time.Second),
+			want: []time.Duration{time.Second, time.Second, 2 * time.Second, 3 * time.Second, 5 * time.Second, 6 * time.Second},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i, want := range tt.want {
+				if got := tt.b.Delay(i + 1); got != want {
+					t.Errorf("Delay(%d) = %v, want %v", i+1, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestBackoff_Delay_bounds(t *testing.T) {
+	tests := []struct {
+		name string
+		b    Backoff
+		max  time.Duration
+	}{
+		{name: "jitter", b: ExponentialJitterBackoff(time.Second, 8*time.Second), max: 8 * time.Second},
+		{name: "exponential overflow", b: ExponentialBackoff(time.Hour, 0), max: 1<<63 - 1},
+		{name: "fibonacci overflow", b: FibonacciBackoff(time.Hour, 0), max: 1<<63 - 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for attempt := 1; attempt <= 200; attempt++ {
+				if d := tt.b.Delay(attempt); d < 0 || d > tt.max {
+					t.Fatalf("Delay(%d) = %v, want within [0, %v]", attempt, d, tt.max)
+				}
+			}
+		})
+	}
+}
+
+func TestPool_SubmitAcked_backoff(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     []Option
+		taskOpts []TaskOption
+		minGap   time.Duration
+	}{
+		{name: "pool backoff", opts: []Option{WithBackoff(ConstantBackoff(20 * time.Millisecond))}, minGap: 20 * time.Millisecond},
+		{
+			name:     "task overrides pool",
+			opts:     []Option{WithBackoff(ConstantBackoff(time.Hour))},
+			taskOpts: []TaskOption{TaskBackoff(ConstantBackoff(20 * time.Millisecond))},
+			minGap:   20 * time.Millisecond,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := New(testDefaultNumTasks, append([]Option{WithContext(context.Background())}, tt.opts...)...)
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+
+			var (
+				mu    sync.Mutex
+				times []time.Time
+			)
+			f, err := p.SubmitAcked(func(d *Delivery) {
+				mu.Lock()
+				times = append(times, time.Now())
+				mu.Unlock()
+
+				if d.Attempt() < 3 {
+					d.Nack()
+					return
+				}
+				d.Ack()
+			}, tt.taskOpts...)
+			if err != nil {
+				t.Fatalf("Pool.SubmitAcked() error = %v", err)
+			}
+
+			if err := f.Err(); err != nil {
+				t.Fatalf("Future.Err() = %v, want nil", err)
+			}
+
+			p.Close()
+			_ = p.Wait()
+
+			for i := 1; i < len(times); i++ {
+				if gap := times[i].Sub(times[i-1]); gap < tt.minGap {
+					t.Errorf("delivery %d came %v after the previous one, want at least %v", i+1, gap, tt.minGap)
+				}
+			}
+		})
+	}
+}
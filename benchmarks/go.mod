@@ -1,11 +1,20 @@
 module github.com/akshaybharambe14/gowp/benchmarks
 
-go 1.16
+go 1.20
 
 require (
 	github.com/akshaybharambe14/gowp v0.0.0-20210905071743-e85d9517ffcc
 	github.com/alitto/pond v1.5.1
 	github.com/gammazero/workerpool v1.1.2
+	github.com/panjf2000/ants/v2 v2.9.1
+	github.com/sourcegraph/conc v0.3.0
+	golang.org/x/sync v0.7.0
+)
+
+require (
+	github.com/gammazero/deque v0.1.0 // indirect
+	go.uber.org/atomic v1.7.0 // indirect
+	go.uber.org/multierr v1.9.0 // indirect
 )
 
 replace github.com/akshaybharambe14/gowp => ./..
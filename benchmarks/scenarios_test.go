@@ -0,0 +1,187 @@
+package benchmarks
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/akshaybharambe14/gowp"
+	"github.com/alitto/pond"
+	"github.com/gammazero/workerpool"
+	"github.com/panjf2000/ants/v2"
+	"github.com/sourcegraph/conc/pool"
+	"golang.org/x/sync/errgroup"
+	"golang.org/x/sync/semaphore"
+)
+
+type (
+	// scenario is a batch of tasks run by a pool, a single operation of the benchmarks.
+	scenario struct {
+		name       string
+		tasks      int
+		workers    int
+		submitters int // goroutines submitting tasks at once, tasks are split evenly among them.
+		task       func() error
+	}
+
+	// runner runs a scenario on a pool implementation and returns the first error of the tasks.
+	runner struct {
+		name string
+		run  func(s scenario) error
+	}
+
+	// firstErr records the first error of tasks, for pools that don't report errors.
+	firstErr struct {
+		once sync.Once
+		err  error
+	}
+)
+
+var errTask = errors.New("task failed")
+
+var scenarios = []scenario{
+	{name: "simple", tasks: numTasks10, workers: numWorkers4, submitters: 1, task: noOpErr},
+	{name: "large", tasks: 10000, workers: numWorkers4, submitters: 1, task: noOpErr},
+	{name: "long", tasks: 40, workers: numWorkers4, submitters: 1, task: func() error {
+		time.Sleep(time.Millisecond)
+		return nil
+	}},
+	{name: "errors", tasks: 1000, workers: numWorkers4, submitters: 1, task: func() error { return errTask }},
+	{name: "concurrent", tasks: 1000, workers: numWorkers4, submitters: 4, task: noOpErr},
+}
+
+var runners = []runner{
+	{name: "gowp", run: run_gowp},
+	{name: "workerpool", run: run_workerpool},
+	{name: "pond", run: run_pond},
+	{name: "ants", run: run_ants},
+	{name: "conc", run: run_conc},
+	{name: "errgroup", run: run_errgroup},
+}
+
+func (fe *firstErr) record(err error) {
+	if err != nil {
+		fe.once.Do(func() { fe.err = err })
+	}
+}
+
+// submit calls fn for each task of s, from s.submitters goroutines.
+func (s scenario) submit(fn func(task func() error)) {
+	var wg sync.WaitGroup
+	for g := 0; g < s.submitters; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for i := 0; i < s.tasks/s.submitters; i++ {
+				fn(s.task)
+			}
+		}()
+	}
+
+	wg.Wait()
+}
+
+func run_gowp(s scenario) error {
+	wp, _ := gowp.New(s.tasks, gowp.WithNumWorkers(s.workers))
+
+	s.submit(func(task func() error) {
+		_ = wp.Submit(task)
+	})
+
+	return wp.Wait()
+}
+
+func run_workerpool(s scenario) error {
+	wp := workerpool.New(s.workers)
+
+	var fe firstErr
+	s.submit(func(task func() error) {
+		wp.Submit(func() { fe.record(task()) })
+	})
+
+	wp.StopWait()
+
+	return fe.err
+}
+
+func run_pond(s scenario) error {
+	p := pond.New(s.workers, s.tasks)
+
+	var fe firstErr
+	s.submit(func(task func() error) {
+		p.Submit(func() { fe.record(task()) })
+	})
+
+	p.StopAndWait()
+
+	return fe.err
+}
+
+func run_ants(s scenario) error {
+	p, _ := ants.NewPool(s.workers)
+	defer p.Release()
+
+	var (
+		fe firstErr
+		wg sync.WaitGroup
+	)
+	s.submit(func(task func() error) {
+		wg.Add(1)
+		_ = p.Submit(func() {
+			defer wg.Done()
+			fe.record(task())
+		})
+	})
+
+	wg.Wait()
+
+	return fe.err
+}
+
+func run_conc(s scenario) error {
+	p := pool.New().WithMaxGoroutines(s.workers).WithErrors().WithFirstError()
+
+	var mu sync.Mutex // conc pools don't support concurrent submitters.
+	s.submit(func(task func() error) {
+		mu.Lock()
+		p.Go(task)
+		mu.Unlock()
+	})
+
+	return p.Wait()
+}
+
+func run_errgroup(s scenario) error {
+	var g errgroup.Group
+	sem := semaphore.NewWeighted(int64(s.workers))
+
+	s.submit(func(task func() error) {
+		_ = sem.Acquire(context.Background(), 1)
+		g.Go(func() error {
+			defer sem.Release(1)
+			return task()
+		})
+	})
+
+	return g.Wait()
+}
+
+func Benchmark_scenarios(b *testing.B) {
+	for _, s := range scenarios {
+		for _, r := range runners {
+			s, r := s, r
+			b.Run(s.name+"/"+r.name, func(b *testing.B) {
+				b.ReportAllocs()
+
+				for i := 0; i < b.N; i++ {
+					if err := r.run(s); (err != nil) != (s.name == "errors") {
+						b.Fatalf("%s: unexpected error %v", r.name, err)
+					}
+				}
+			})
+		}
+	}
+}
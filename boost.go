@@ -0,0 +1,65 @@
+package gowp
+
+import "time"
+
+type boostPolicy struct {
+	threshold time.Duration
+	ceiling   int
+}
+
+// WithWorkerBoost returns an Option that temporarily adds workers when tasks wait in the queue for too long.
+// Whenever the oldest queued task has waited longer than threshold, one more worker is started, as long as
+// the total number of workers stays within ceiling. The check is repeated every threshold/2, so the boost
+// ramps up while the alarm persists. Extra workers exit as soon as they find the queue empty, bringing the
+// pool back to its regular size.
+//
+// threshold should be greater than zero and ceiling should not be less than the number of workers,
+// otherwise ErrInvalidBoost will be returned on Pool initialization.
+func WithWorkerBoost(threshold time.Duration, ceiling int) Option {
+	return func(o *config) {
+		o.boost = &boostPolicy{threshold: threshold, ceiling: ceiling}
+	}
+}
+
+// boost watches the queue and starts temporary workers as per the policy, until the pool stops.
+func (p *Pool) boost(bp boostPolicy, numWorkers int) {
+	interval := bp.threshold / 2
+	if interval <= 0 {
+		interval = bp.threshold
+	}
+
+	t := p.clock.NewTicker(interval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-p.quit:
+			return
+		case <-p.exitFromErrG:
+			return
+		case now := <-t.C():
+			p.checkBoost(now, bp, numWorkers)
+		}
+	}
+}
+
+func (p *Pool) checkBoost(now time.Time, bp boostPolicy, numWorkers int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	select {
+	case <-p.quit:
+		return
+	default:
+	}
+
+	j := p.queue.oldest()
+	if j == nil || now.Sub(j.submittedAt) <= bp.threshold || numWorkers+p.boosted >= bp.ceiling {
+		return
+	}
+
+	// the queue is not empty and the pool didn't quit, so the regular workers are still around
+	// and Wait is blocked on them, it is safe to add to the wait group.
+	p.boosted++
+	p.spawn(true)
+}
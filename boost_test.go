@@ -0,0 +1,59 @@
+package gowp
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWithWorkerBoost(t *testing.T) {
+	const (
+		numTasks  = 20
+		threshold = 5 * time.Millisecond
+		ceiling   = 4
+	)
+
+	p := testPool(context.Background(), 1, numTasks, false, WithWorkerBoost(threshold, ceiling))
+
+	var running, peak int32
+	for i := 0; i < numTasks; i++ {
+		_ = p.Submit(func() error {
+			n := atomic.AddInt32(&running, 1)
+			for {
+				old := atomic.LoadInt32(&peak)
+				if n <= old || atomic.CompareAndSwapInt32(&peak, old, n) {
+					break
+				}
+			}
+
+			time.Sleep(2 * threshold)
+			atomic.AddInt32(&running, -1)
+
+			return nil
+		})
+	}
+
+	if err := p.Wait(); err != nil {
+		t.Fatalf("Pool.Wait() error = %v", err)
+	}
+
+	if peak < 2 || peak > ceiling {
+		t.Errorf("peak concurrency = %d, want within [2, %d]", peak, ceiling)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.boosted != 0 {
+		t.Errorf("boosted workers = %d after Wait, want 0", p.boosted)
+	}
+}
+
+func TestWithWorkerBoost_validation(t *testing.T) {
+	_, err := New(testDefaultNumTasks, WithNumWorkers(4), WithWorkerBoost(time.Second, 2))
+	if !errors.Is(err, ErrInvalidBoost) {
+		t.Errorf("New() = %v, want %v", err, ErrInvalidBoost)
+	}
+}
@@ -0,0 +1,86 @@
+package gowp
+
+import (
+	"sync"
+	"time"
+)
+
+// states of a circuit breaker.
+const (
+	circuitClosed   = iota // tasks run, consecutive failures are counted.
+	circuitOpen            // tasks fail fast until the cooldown has elapsed.
+	circuitHalfOpen        // a single trial task runs, its outcome closes or reopens the circuit.
+)
+
+// circuitBreaker fails tasks fast while whatever they call into looks unhealthy, see WithCircuitBreaker.
+type circuitBreaker struct {
+	threshold int
+	cooldown  time.Duration
+
+	mu       sync.Mutex
+	state    int
+	failures int       // consecutive failures while closed.
+	openedAt time.Time // time at which the circuit last opened.
+}
+
+// WithCircuitBreaker returns an Option that stops running tasks for a while once threshold tasks in a row
+// have failed, protecting the systems they call into during incidents. While the circuit is open, tasks
+// are not executed and fail fast with ErrCircuitOpen instead. Once cooldown has elapsed, a single trial task
+// is let through: the circuit closes if it succeeds and opens for another cooldown if it fails.
+//
+// Errors classified as SeverityIgnore, see WithErrorClassifier, are not counted as failures.
+// Fast failures are reported like any other error, classify ErrCircuitOpen to keep the pool going.
+// threshold and cooldown should be greater than zero, otherwise ErrInvalidBreaker will be returned on Pool initialization.
+func WithCircuitBreaker(threshold int, cooldown time.Duration) Option {
+	return func(o *config) {
+		o.breaker = &circuitBreaker{threshold: threshold, cooldown: cooldown}
+	}
+}
+
+// allow reports whether a task may run. probe is true if the task is the trial of a half-open circuit,
+// its outcome should be passed to record.
+func (b *circuitBreaker) allow(now time.Time) (ok, probe bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case circuitClosed:
+		return true, false
+
+	case circuitOpen:
+		if now.Sub(b.openedAt) < b.cooldown {
+			return false, false
+		}
+
+		b.state = circuitHalfOpen
+		return true, true
+
+	default:
+		return false, false // the trial is in flight.
+	}
+}
+
+// record updates the circuit with the outcome of a task that was allowed to run.
+func (b *circuitBreaker) record(failed, probe bool, now time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch {
+	case probe && failed:
+		b.state, b.openedAt = circuitOpen, now
+
+	case probe:
+		b.state, b.failures = circuitClosed, 0
+
+	case b.state != circuitClosed:
+		// a task that started before the circuit opened, the trial decides.
+
+	case !failed:
+		b.failures = 0
+
+	default:
+		if b.failures++; b.failures >= b.threshold {
+			b.state, b.openedAt, b.failures = circuitOpen, now, 0
+		}
+	}
+}
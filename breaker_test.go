@@ -0,0 +1,94 @@
+package gowp
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	b := &circuitBreaker{threshold: 2, cooldown: time.Second}
+	now := time.Now()
+
+	steps := []struct {
+		name      string
+		at        time.Duration
+		failed    bool
+		wantOK    bool
+		wantProbe bool
+	}{
+		{name: "closed", wantOK: true, failed: true},
+		{name: "success resets the count", wantOK: true},
+		{name: "first failure", wantOK: true, failed: true},
+		{name: "threshold reached", wantOK: true, failed: true},
+		{name: "open", at: 500 * time.Millisecond},
+		{name: "failed trial", at: time.Second, wantOK: true, wantProbe: true, failed: true},
+		{name: "reopened", at: 1500 * time.Millisecond},
+		{name: "successful trial", at: 2 * time.Second, wantOK: true, wantProbe: true},
+		{name: "closed again", at: 2 * time.Second, wantOK: true},
+	}
+
+	for _, s := range steps {
+		t.Run(s.name, func(t *testing.T) {
+			ok, probe := b.allow(now.Add(s.at))
+			if ok != s.wantOK || probe != s.wantProbe {
+				t.Fatalf("circuitBreaker.allow() = %v, %v, want %v, %v", ok, probe, s.wantOK, s.wantProbe)
+			}
+
+			if ok {
+				b.record(s.failed, probe, now.Add(s.at))
+			}
+		})
+	}
+}
+
+func TestWithCircuitBreaker(t *testing.T) {
+	if _, err := New(1, WithCircuitBreaker(0, time.Second)); !errors.Is(err, ErrInvalidBreaker) {
+		t.Errorf("New() error = %v, want %v", err, ErrInvalidBreaker)
+	}
+
+	if _, err := New(1, WithCircuitBreaker(1, 0)); !errors.Is(err, ErrInvalidBreaker) {
+		t.Errorf("New() error = %v, want %v", err, ErrInvalidBreaker)
+	}
+
+	p, err := New(testDefaultNumTasks, WithContext(context.Background()), WithCircuitBreaker(2, time.Hour),
+		WithErrorClassifier(func(err error) Severity {
+			if errors.Is(err, ErrCircuitOpen) {
+				return SeverityIgnore
+			}
+
+			return SeverityError
+		}))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	var ran int
+	var fs []*Future
+	for i := 0; i < 5; i++ {
+		f, _ := p.SubmitFuture(func() error {
+			ran++
+			return testErr
+		})
+		fs = append(fs, f)
+	}
+
+	if err := p.Wait(); !errors.Is(err, testErr) {
+		t.Errorf("Pool.Wait() = %v, want %v", err, testErr)
+	}
+
+	if ran != 2 {
+		t.Errorf("tasks ran %d times, want 2", ran)
+	}
+
+	for _, f := range fs[2:] {
+		if err := f.Err(); !errors.Is(err, ErrCircuitOpen) {
+			t.Errorf("Future.Err() = %v, want %v", err, ErrCircuitOpen)
+		}
+	}
+
+	if r := p.report(); r.Failed != 2 || r.Ignored != 3 {
+		t.Errorf("Report.Failed, Ignored = %d, %d, want 2, 3", r.Failed, r.Ignored)
+	}
+}
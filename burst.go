@@ -0,0 +1,70 @@
+package gowp
+
+import "time"
+
+// burstBucket is a token bucket of temporary workers, see WithBurst.
+type burstBucket struct {
+	ceiling    int           // maximum number of workers, regular and temporary.
+	numWorkers int           // number of regular workers.
+	refill     time.Duration // time it takes to regain one token.
+
+	size   int       // capacity of the bucket, the number of temporary workers allowed at once.
+	tokens int       // tokens left.
+	last   time.Time // time at which the last token was regained.
+}
+
+// WithBurst returns an Option that lets the pool run more than the configured number of workers for
+// a while, up to ceiling workers in total. When a task is queued while no worker is idle, a temporary
+// worker is started for it if a token is available. The bucket holds ceiling minus the number of workers
+// tokens, it starts full and regains one token every refill. Temporary workers exit as soon as they find
+// the queue empty, so that spikes are absorbed quickly without raising the concurrency for good.
+//
+// ceiling should not be less than the number of workers and refill should be greater than zero,
+// otherwise ErrInvalidBurst will be returned on Pool initialization.
+func WithBurst(ceiling int, refill time.Duration) Option {
+	return func(o *config) {
+		o.burst = &burstBucket{ceiling: ceiling, refill: refill}
+	}
+}
+
+func newBurstBucket(b burstBucket, numWorkers int, now time.Time) *burstBucket {
+	b.numWorkers = numWorkers
+	b.size = b.ceiling - numWorkers
+	b.tokens = b.size
+	b.last = now
+
+	return &b
+}
+
+// take takes a token, if there is one left.
+func (b *burstBucket) take(now time.Time) bool {
+	if n := int(now.Sub(b.last) / b.refill); n > 0 {
+		b.tokens += n
+		b.last = b.last.Add(time.Duration(n) * b.refill)
+
+		if b.tokens >= b.size {
+			b.tokens, b.last = b.size, now
+		}
+	}
+
+	if b.tokens == 0 {
+		return false
+	}
+
+	b.tokens--
+
+	return true
+}
+
+// checkBurst starts a temporary worker if the queue has more jobs than idle workers and the bucket allows it.
+// p.mu must be held and the intake must be open.
+func (p *Pool) checkBurst() {
+	if p.queue.len() <= p.idle || p.burst.numWorkers+p.boosted >= p.burst.ceiling || !p.burst.take(p.clock.Now()) {
+		return
+	}
+
+	// the intake is open, so the regular workers are still around and Wait is blocked on them
+	// or not called yet, it is safe to add to the wait group.
+	p.boosted++
+	p.spawn(true)
+}
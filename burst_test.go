@@ -0,0 +1,83 @@
+package gowp
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWithBurst(t *testing.T) {
+	const ceiling = 3
+
+	p := testPool(context.Background(), 1, testDefaultNumTasks, false, WithBurst(ceiling, time.Hour))
+
+	var running int32
+	release := make(chan struct{})
+	task := func() error {
+		atomic.AddInt32(&running, 1)
+		<-release
+		return nil
+	}
+
+	for i := 0; i < 5; i++ {
+		_ = p.Submit(task)
+	}
+
+	// one regular worker and two temporary ones, the bucket is empty afterwards.
+	deadline := time.Now().Add(time.Second)
+	for atomic.LoadInt32(&running) < ceiling && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+
+	time.Sleep(5 * time.Millisecond) // give a chance to a wrongly started worker.
+	if got := atomic.LoadInt32(&running); got != ceiling {
+		t.Errorf("running tasks = %d, want %d", got, ceiling)
+	}
+
+	close(release)
+
+	if err := p.Wait(); err != nil {
+		t.Fatalf("Pool.Wait() error = %v", err)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.boosted != 0 || p.burst.tokens != 0 {
+		t.Errorf("temporary workers = %d, tokens = %d after Wait, want 0 and 0", p.boosted, p.burst.tokens)
+	}
+}
+
+func TestBurstBucket_take(t *testing.T) {
+	now := time.Now()
+	b := newBurstBucket(burstBucket{ceiling: 3, refill: time.Second}, 1, now)
+
+	for i := 0; i < 2; i++ {
+		if !b.take(now) {
+			t.Fatalf("burstBucket.take() = false, want true for token %d", i)
+		}
+	}
+
+	if b.take(now) {
+		t.Error("burstBucket.take() = true on an empty bucket")
+	}
+
+	if !b.take(now.Add(time.Second)) || b.take(now.Add(time.Second)) {
+		t.Error("burstBucket.take() didn't regain exactly one token after refill")
+	}
+
+	// a long pause fills the bucket up to its size only.
+	later := now.Add(time.Hour)
+	if !b.take(later) || !b.take(later) || b.take(later) {
+		t.Error("burstBucket.take() regained more tokens than the bucket size")
+	}
+}
+
+func TestWithBurst_validation(t *testing.T) {
+	_, err := New(testDefaultNumTasks, WithNumWorkers(4), WithBurst(2, time.Second))
+	if !errors.Is(err, ErrInvalidBurst) {
+		t.Errorf("New() = %v, want %v", err, ErrInvalidBurst)
+	}
+}
@@ -0,0 +1,71 @@
+package gowp
+
+import "time"
+
+type (
+	// Cache holds the results of keyed tasks, see WithCache. It is called concurrently by the workers.
+	// Caches of LRU libraries, or a shared cache such as Redis, can be adapted to it.
+	Cache interface {
+		// Get returns the value stored under key, ok is false if there is none or it has expired.
+		Get(key string) (v any, ok bool)
+
+		// Set stores v under key, for ttl.
+		Set(key string, v any, ttl time.Duration)
+	}
+
+	// cacheSetting is the cache of the pool, see WithCache.
+	cacheSetting struct {
+		c   Cache
+		ttl time.Duration
+	}
+
+	// memo exposes the value of a task to the cache, see TypedPool.Submit. Tasks of a Pool have none.
+	memo struct {
+		value   func() any     // returns the value produced by the task.
+		restore func(any) bool // sets the value of the task from the cache. false, if the cached value doesn't fit.
+	}
+)
+
+// WithCache returns an Option that memoizes the results of the tasks that have a key, see TaskCacheKey.
+// Once a task has succeeded, tasks submitted with the same key within ttl are not executed, they succeed
+// with the cached result, i.e. the value of a TypedPool task. Failed tasks are not cached. Tasks are looked
+// up when a worker picks them, so cached tasks still wait for their turn in the queue, and two tasks with the
+// same key running at the same time both run, see SubmitShared to share an execution. Tasks submitted with
+// SubmitAcked are not cached. c should not be nil and ttl should be greater than zero, otherwise ErrInvalidCache
+// will be returned on Pool initialization.
+func WithCache(c Cache, ttl time.Duration) Option {
+	return func(o *config) {
+		o.cache = &cacheSetting{c: c, ttl: ttl}
+	}
+}
+
+// TaskCacheKey returns a TaskOption that sets the key the result of the task is cached under, see WithCache.
+// Tasks without a key are always executed.
+func TaskCacheKey(key string) TaskOption {
+	return func(j *job) {
+		j.cacheKey = key
+	}
+}
+
+// memoize returns the task of j, skipped if its result is cached and cached once it succeeds.
+func (p *Pool) memoize(j *job) Task {
+	t, key, m, c := j.fn, j.cacheKey, j.memo, p.cache
+
+	return func() error {
+		if v, ok := c.c.Get(key); ok && (m.restore == nil || m.restore(v)) {
+			return nil
+		}
+
+		if err := t(); err != nil {
+			return err
+		}
+
+		var v any
+		if m.value != nil {
+			v = m.value()
+		}
+		c.c.Set(key, v, c.ttl)
+
+		return nil
+	}
+}
@@ -0,0 +1,103 @@
+package gowp
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// testCache is a Cache in memory, entries never expire.
+type testCache struct {
+	mu      sync.Mutex
+	entries map[string]any
+	ttl     time.Duration // of the last Set.
+}
+
+func (c *testCache) Get(key string) (any, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	v, ok := c.entries[key]
+	return v, ok
+}
+
+func (c *testCache) Set(key string, v any, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[key], c.ttl = v, ttl
+}
+
+func TestWithCache(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	tests := []struct {
+		name     string
+		keys     []string
+		fail     bool
+		wantRuns int32
+	}{
+		{name: "same key", keys: []string{"a", "a", "a"}, wantRuns: 1},
+		{name: "different keys", keys: []string{"a", "b", "a"}, wantRuns: 2},
+		{name: "no key", keys: []string{"", ""}, wantRuns: 2},
+		{name: "failures are not cached", keys: []string{"a", "a"}, fail: true, wantRuns: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &testCache{entries: map[string]any{}}
+			tp, err := NewTyped[int](len(tt.keys), WithNumWorkers(1), WithCache(c, time.Minute))
+			if err != nil {
+				t.Fatalf("NewTyped() error = %v", err)
+			}
+
+			var runs int32
+			for _, key := range tt.keys {
+				f, err := tp.Submit(func() (int, error) {
+					atomic.AddInt32(&runs, 1)
+					if tt.fail {
+						return 0, errBoom
+					}
+					return 42, nil
+				}, TaskCacheKey(key))
+				if err != nil {
+					t.Fatalf("Submit() error = %v", err)
+				}
+
+				v, err := f.Result()
+				if tt.fail {
+					if !errors.Is(err, errBoom) {
+						t.Errorf("Result() error = %v, want %v", err, errBoom)
+					}
+					continue
+				}
+
+				if v != 42 || err != nil {
+					t.Errorf("Result() = %v, %v, want 42, nil", v, err)
+				}
+			}
+
+			_ = tp.Wait()
+
+			if got := atomic.LoadInt32(&runs); got != tt.wantRuns {
+				t.Errorf("tasks ran %d times, want %d", got, tt.wantRuns)
+			}
+
+			if !tt.fail && tt.keys[0] != "" && c.ttl != time.Minute {
+				t.Errorf("cached for %v, want %v", c.ttl, time.Minute)
+			}
+		})
+	}
+}
+
+func TestWithCache_invalid(t *testing.T) {
+	if _, err := New(1, WithCache(nil, time.Minute)); !errors.Is(err, ErrInvalidCache) {
+		t.Errorf("New() error = %v, want %v", err, ErrInvalidCache)
+	}
+
+	if _, err := New(1, WithCache(&testCache{}, 0)); !errors.Is(err, ErrInvalidCache) {
+		t.Errorf("New() error = %v, want %v", err, ErrInvalidCache)
+	}
+}
@@ -0,0 +1,113 @@
+package gowp
+
+import (
+	"fmt"
+	"sync"
+)
+
+type (
+	// capacity is a weighted semaphore bounding the total weight of running tasks, see WithCapacity.
+	// Waiters are served in order, so that a heavy task isn't starved by a stream of light ones.
+	capacity struct {
+		mu      sync.Mutex
+		size    int64
+		used    int64
+		waiters []capacityWaiter
+	}
+
+	capacityWaiter struct {
+		weight int64
+		ready  chan struct{} // closed once the weight is acquired on behalf of the waiter.
+	}
+)
+
+// WithCapacity returns an Option that bounds the total weight of the tasks running at once to size,
+// on top of the number of workers. Tasks weigh one, unless submitted with SubmitWeighted or TaskWeight,
+// so that a memory-heavy task can count as much as several light ones. A worker holding a task waits
+// until enough capacity is released, tasks are started in the order workers picked them up.
+//
+// size should be greater than zero, otherwise ErrInvalidCapacity will be returned on Pool initialization.
+func WithCapacity(size int64) Option {
+	return func(o *config) {
+		o.capacity = &capacity{size: size}
+	}
+}
+
+// TaskWeight returns a TaskOption that sets the weight of the task, see WithCapacity.
+// It has no effect if the pool has no capacity.
+func TaskWeight(weight int64) TaskOption {
+	return func(j *job) {
+		j.weight = weight
+	}
+}
+
+// SubmitWeighted submits t with the given weight, see WithCapacity. The weight should be greater
+// than zero and not exceed the capacity of the pool, otherwise ErrInvalidWeight is returned.
+func (p *Pool) SubmitWeighted(t Task, weight int64) error {
+	if err := p.submit(t, nil, []TaskOption{TaskWeight(weight)}); err != nil {
+		return fmt.Errorf("gowp.Pool.SubmitWeighted(): %w", err)
+	}
+
+	return nil
+}
+
+// acquire blocks until weight is available or quit is closed. It reports whether weight was acquired.
+func (c *capacity) acquire(weight int64, quit <-chan struct{}) bool {
+	c.mu.Lock()
+	if len(c.waiters) == 0 && c.used+weight <= c.size {
+		c.used += weight
+		c.mu.Unlock()
+		return true
+	}
+
+	w := capacityWaiter{weight: weight, ready: make(chan struct{})}
+	c.waiters = append(c.waiters, w)
+	c.mu.Unlock()
+
+	select {
+	case <-w.ready:
+		return true
+	case <-quit:
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	select {
+	case <-w.ready:
+		c.used -= weight // acquired in the meantime, give it back.
+	default:
+		for i := range c.waiters {
+			if c.waiters[i].ready == w.ready {
+				c.waiters = append(c.waiters[:i], c.waiters[i+1:]...)
+				break
+			}
+		}
+	}
+
+	c.notify() // the waiter might have been blocking the ones behind it.
+
+	return false
+}
+
+func (c *capacity) release(weight int64) {
+	c.mu.Lock()
+	c.used -= weight
+	c.notify()
+	c.mu.Unlock()
+}
+
+// notify hands the available weight to the waiters in order. c.mu must be held.
+func (c *capacity) notify() {
+	for len(c.waiters) > 0 {
+		w := c.waiters[0]
+		if c.used+w.weight > c.size {
+			return
+		}
+
+		c.used += w.weight
+		c.waiters[0] = capacityWaiter{}
+		c.waiters = c.waiters[1:]
+		close(w.ready)
+	}
+}
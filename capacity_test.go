@@ -0,0 +1,116 @@
+package gowp
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWithCapacity(t *testing.T) {
+	const size = 10
+
+	p := testPool(context.Background(), 4, testDefaultNumTasks, false, WithCapacity(size))
+
+	var load, peak int64
+	task := func(weight int64) Task {
+		return func() error {
+			n := atomic.AddInt64(&load, weight)
+			for {
+				old := atomic.LoadInt64(&peak)
+				if n <= old || atomic.CompareAndSwapInt64(&peak, old, n) {
+					break
+				}
+			}
+
+			time.Sleep(2 * time.Millisecond)
+			atomic.AddInt64(&load, -weight)
+
+			return nil
+		}
+	}
+
+	for i := 0; i < 5; i++ {
+		_ = p.SubmitWeighted(task(8), 8)
+		_ = p.Submit(task(1))
+	}
+
+	if err := p.Wait(); err != nil {
+		t.Fatalf("Pool.Wait() error = %v", err)
+	}
+
+	if peak > size {
+		t.Errorf("peak weight = %d, want at most %d", peak, size)
+	}
+}
+
+func TestPool_SubmitWeighted(t *testing.T) {
+	tests := []struct {
+		name    string
+		weight  int64
+		wantErr error
+	}{
+		{name: "within capacity", weight: 4},
+		{name: "zero", weight: 0, wantErr: ErrInvalidWeight},
+		{name: "over capacity", weight: 5, wantErr: ErrInvalidWeight},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := testPool(context.Background(), 1, testDefaultNumTasks, false, WithCapacity(4))
+
+			if err := p.SubmitWeighted(testNoOpFunc, tt.weight); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Pool.SubmitWeighted() = %v, want %v", err, tt.wantErr)
+			}
+
+			_ = p.Wait()
+		})
+	}
+
+	if _, err := New(testDefaultNumTasks, WithCapacity(0)); !errors.Is(err, ErrInvalidCapacity) {
+		t.Errorf("New() = %v, want %v", err, ErrInvalidCapacity)
+	}
+}
+
+func TestCapacity_acquire(t *testing.T) {
+	c := &capacity{size: 3}
+	quit := make(chan struct{})
+
+	if !c.acquire(2, quit) {
+		t.Fatal("capacity.acquire() = false with room available")
+	}
+
+	// a heavy waiter is served before lighter ones that would fit.
+	heavy := make(chan bool)
+	go func() { heavy <- c.acquire(3, quit) }()
+
+	for {
+		c.mu.Lock()
+		n := len(c.waiters)
+		c.mu.Unlock()
+
+		if n == 1 {
+			break
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+
+	light := make(chan bool)
+	go func() { light <- c.acquire(1, quit) }()
+
+	c.release(2)
+	if !<-heavy {
+		t.Error("heavy capacity.acquire() = false, want true")
+	}
+
+	close(quit)
+	if <-light {
+		t.Error("light capacity.acquire() = true after quit, want false")
+	}
+
+	c.release(3)
+	if c.used != 0 || len(c.waiters) != 0 {
+		t.Errorf("capacity used = %d, waiters = %d, want 0 and 0", c.used, len(c.waiters))
+	}
+}
@@ -0,0 +1,49 @@
+package gowp
+
+import (
+	"context"
+	"fmt"
+)
+
+// Context returns a context that is cancelled once the pool stops early or completes, e.g. to cut short work
+// related to the pool. context.Cause tells why: the error of the task that stopped the pool, the cause of the
+// context of the pool (see WithContext), ErrDrainTimeout, or ErrPoolClosed once the pool has completed without
+// error. The cause is context.Canceled if the pool stopped on its first successful task, see WithFirstSuccess.
+func (p *Pool) Context() context.Context {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.halt == nil {
+		p.halt, p.unhalt = context.WithCancelCause(p.ctx)
+		if p.halted {
+			p.unhalt(p.cause)
+		}
+	}
+
+	return p.halt
+}
+
+// halts records that the pool stops or completes because of cause and cancels its context, the first time only.
+// p.mu must be held.
+func (p *Pool) halts(cause error) {
+	if p.halted {
+		return
+	}
+
+	p.halted = true
+	p.cause = cause
+
+	if p.unhalt != nil {
+		p.unhalt(cause)
+	}
+}
+
+// ctxErr returns the error of the context of the pool, along with its cause if they differ, see context.Cause.
+func (p *Pool) ctxErr() error {
+	err := p.ctx.Err()
+	if cause := context.Cause(p.ctx); err != nil && cause != err {
+		return fmt.Errorf("%w: %w", err, cause)
+	}
+
+	return err
+}
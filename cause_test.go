@@ -0,0 +1,85 @@
+package gowp
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestPool_Context(t *testing.T) {
+	errUser := errors.New("user left")
+
+	tests := []struct {
+		name      string
+		exitOnErr bool
+		setup     func(p *Pool, cancel context.CancelCauseFunc)
+		lateCtx   bool // get the context once the pool has completed.
+		wantCause error
+		wantErr   []error
+	}{
+		{
+			name:      "task failed",
+			exitOnErr: true,
+			setup:     func(p *Pool, _ context.CancelCauseFunc) { _ = p.Submit(testFuncWithErr) },
+			wantCause: testErr,
+			wantErr:   []error{testErr},
+		},
+		{
+			name:      "context cancelled",
+			setup:     func(p *Pool, cancel context.CancelCauseFunc) { cancel(errUser) },
+			wantCause: errUser,
+			wantErr:   []error{context.Canceled, errUser},
+		},
+		{
+			name:      "completed",
+			setup:     func(p *Pool, _ context.CancelCauseFunc) { _ = p.Submit(testNoOpFunc) },
+			wantCause: ErrPoolClosed,
+		},
+		{
+			name:      "completed before the context is requested",
+			exitOnErr: true,
+			setup:     func(p *Pool, _ context.CancelCauseFunc) { _ = p.Submit(testFuncWithErr) },
+			lateCtx:   true,
+			wantCause: testErr,
+			wantErr:   []error{testErr},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parent, cancel := context.WithCancelCause(context.Background())
+			defer cancel(nil)
+
+			p := testPool(parent, testDefaultNumWorkers, testDefaultNumTasks, tt.exitOnErr)
+
+			var ctx context.Context
+			if !tt.lateCtx {
+				ctx = p.Context()
+				if ctx.Err() != nil {
+					t.Fatalf("Pool.Context() done before the pool stopped: %v", context.Cause(ctx))
+				}
+			}
+
+			tt.setup(p, cancel)
+
+			err := p.Wait()
+			if (err != nil) != (len(tt.wantErr) > 0) {
+				t.Errorf("Pool.Wait() error = %v, want %v", err, tt.wantErr)
+			}
+
+			for _, want := range tt.wantErr {
+				if !errors.Is(err, want) {
+					t.Errorf("Pool.Wait() = %v, want %v", err, want)
+				}
+			}
+
+			if tt.lateCtx {
+				ctx = p.Context()
+			}
+
+			<-ctx.Done()
+			if cause := context.Cause(ctx); !errors.Is(cause, tt.wantCause) {
+				t.Errorf("context.Cause(Pool.Context()) = %v, want %v", cause, tt.wantCause)
+			}
+		})
+	}
+}
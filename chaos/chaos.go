@@ -0,0 +1,203 @@
+// Package chaos injects faults into gowp pools, to soak-test the code that handles them.
+//
+// Faults are drawn from a random source seeded by Config.Seed. Every decision is taken when a task
+// is submitted, so the same seed and the same submission order produce the same faults.
+//
+// A Pool injects faults into the tasks submitted through it. WithChaos injects them into every task
+// of a pool instead, the rejections and cancellations being left out.
+//
+// Example:
+//	m := chaos.New(chaos.Config{Seed: 42, Delay: 0.1, MaxDelay: time.Second, Reject: 0.05, Cancel: 0.05})
+//
+//	wp, _ := gowp.New(10)
+//	cp := m.Pool(wp)
+//
+//	_ = cp.Submit(task) // may be rejected, cancelled or delayed.
+package chaos // import "github.com/akshaybharambe14/gowp/chaos"
+
+import (
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/akshaybharambe14/gowp"
+)
+
+// errors reported for injected faults.
+const (
+	ErrRejected = gowp.Error("task rejected by chaos")
+	ErrPanic    = gowp.Error("panic injected by chaos")
+	ErrFailed   = gowp.Error("task failed by chaos")
+)
+
+type (
+	// Config sets the probability of each fault, between 0 (never) and 1 (always).
+	Config struct {
+		Seed int64
+
+		Delay    float64       // probability to sleep before running the task.
+		MaxDelay time.Duration // upper bound of an injected delay, the actual delay is random.
+		Panic    float64       // probability that the task panics with ErrPanic instead of running.
+		Fail     float64       // probability that the task returns ErrFailed instead of running.
+		Reject   float64       // probability that Submit fails with ErrRejected.
+		Cancel   float64       // probability that the task is cancelled right after it is submitted.
+
+		// Clock measures the delays injected by Wrap and WithChaos, the system clock if nil. Set it to the
+		// clock of the pool, see gowp.WithClock. A Pool uses the clock of the wrapped gowp.Pool instead.
+		Clock gowp.Clock
+	}
+
+	// Monkey draws faults from a seeded random source. It is safe for concurrent use.
+	//
+	// Zero value is not usable. Use New() to create a Monkey.
+	Monkey struct {
+		cfg Config
+
+		mu  sync.Mutex
+		rnd *rand.Rand // guarded by mu.
+	}
+
+	// Pool wraps a gowp.Pool and injects faults into the tasks submitted through it.
+	Pool struct {
+		p *gowp.Pool
+		m *Monkey
+	}
+
+	// faults decided for a single task.
+	faults struct {
+		delay  time.Duration
+		panic  bool
+		fail   bool
+		reject bool
+		cancel bool
+	}
+)
+
+// New creates a Monkey with the given configuration.
+func New(cfg Config) *Monkey {
+	return &Monkey{cfg: cfg, rnd: rand.New(rand.NewSource(cfg.Seed))}
+}
+
+// WithChaos returns a gowp.Option that injects delays, panics and failures into the tasks of a pool,
+// as drawn by a Monkey with the given configuration. Config.Reject and Config.Cancel are ignored.
+// Injected panics crash the program unless the pool recovers them, see gowp.WithPanicRecovery.
+func WithChaos(cfg Config) gowp.Option {
+	return gowp.WithTaskMiddleware(New(cfg).Wrap)
+}
+
+// Wrap returns a task that may be delayed, panic or fail before running t.
+// It is useful to test recovery middlewares, wrap the task with Wrap first and then with the middleware.
+func (m *Monkey) Wrap(t gowp.Task) gowp.Task {
+	return m.draw().wrap(t, m.cfg.Clock)
+}
+
+// Pool returns a Pool that submits tasks to p.
+func (m *Monkey) Pool(p *gowp.Pool) *Pool {
+	return &Pool{p: p, m: m}
+}
+
+// Submit submits t to the pool, with the same semantics as gowp.Pool.Submit.
+// Injected panics crash the program unless Config.Panic is zero or the pool recovers them, see
+// gowp.WithPanicRecovery. Use Monkey.Wrap to inject panics under a recovery middleware otherwise.
+func (cp *Pool) Submit(t gowp.Task) error {
+	if _, err := cp.submit(t); err != nil {
+		return fmt.Errorf("chaos.Pool.Submit(): %w", err)
+	}
+
+	return nil
+}
+
+// SubmitFuture submits t to the pool, with the same semantics as gowp.Pool.SubmitFuture.
+// Cancellation is attempted right after submission, it has no effect if a worker has started the task already.
+func (cp *Pool) SubmitFuture(t gowp.Task, opts ...gowp.TaskOption) (*gowp.Future, error) {
+	fut, err := cp.submit(t, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("chaos.Pool.SubmitFuture(): %w", err)
+	}
+
+	return fut, nil
+}
+
+func (cp *Pool) submit(t gowp.Task, opts ...gowp.TaskOption) (*gowp.Future, error) {
+	if t == nil {
+		return cp.p.SubmitFuture(t, opts...) // let the pool report the nil task.
+	}
+
+	f := cp.m.draw()
+	if f.reject {
+		return nil, ErrRejected
+	}
+
+	fut, err := cp.p.SubmitFuture(f.wrap(t, cp.p.Clock()), opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	if f.cancel {
+		fut.Cancel()
+	}
+
+	return fut, nil
+}
+
+// Wait waits for the wrapped pool, see gowp.Pool.Wait.
+func (cp *Pool) Wait() error {
+	return cp.p.Wait()
+}
+
+// draw decides the faults of the next task. Draws depend only on the configuration
+// and on the number of previous draws, which keeps the faults reproducible for a seed.
+func (m *Monkey) draw() faults {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var f faults
+	if m.rnd.Float64() < m.cfg.Delay && m.cfg.MaxDelay > 0 {
+		f.delay = time.Duration(m.rnd.Int63n(int64(m.cfg.MaxDelay)))
+	}
+
+	f.panic = m.rnd.Float64() < m.cfg.Panic
+	f.reject = m.rnd.Float64() < m.cfg.Reject
+	f.cancel = m.rnd.Float64() < m.cfg.Cancel
+	f.fail = m.rnd.Float64() < m.cfg.Fail
+
+	return f
+}
+
+// wrap returns a task injecting f before running t, delays are measured with c, the system clock if nil.
+func (f faults) wrap(t gowp.Task, c gowp.Clock) gowp.Task {
+	if f.delay == 0 && !f.panic && !f.fail {
+		return t
+	}
+
+	return func() error {
+		sleep(c, f.delay)
+
+		if f.panic {
+			panic(ErrPanic)
+		}
+
+		if f.fail {
+			return ErrFailed
+		}
+
+		return t()
+	}
+}
+
+// sleep blocks for d, as measured by c, the system clock if nil.
+func sleep(c gowp.Clock, d time.Duration) {
+	if d <= 0 {
+		return
+	}
+
+	if c == nil {
+		time.Sleep(d)
+		return
+	}
+
+	done := make(chan struct{})
+	c.AfterFunc(d, func() { close(done) })
+	<-done
+}
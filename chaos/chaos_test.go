@@ -0,0 +1,207 @@
+package chaos
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/akshaybharambe14/gowp"
+	"github.com/akshaybharambe14/gowp/testutil"
+)
+
+func TestMonkey_draw(t *testing.T) {
+	cfg := Config{Seed: 7, Delay: 0.5, MaxDelay: time.Second, Panic: 0.2, Reject: 0.2, Cancel: 0.2}
+
+	draws := func() []faults {
+		m := New(cfg)
+		fs := make([]faults, 100)
+		for i := range fs {
+			fs[i] = m.draw()
+		}
+
+		return fs
+	}
+
+	first := draws()
+	if !reflect.DeepEqual(first, draws()) {
+		t.Error("Monkey.draw() is not reproducible for the same seed")
+	}
+
+	if !reflect.DeepEqual(New(Config{}).draw(), faults{}) {
+		t.Error("Monkey.draw() injected faults with zero probabilities")
+	}
+}
+
+func TestPool(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr error // returned by SubmitFuture.
+		wantFut error // reported by the future.
+	}{
+		{name: "no faults", cfg: Config{}},
+		{name: "reject", cfg: Config{Reject: 1}, wantErr: ErrRejected},
+		{name: "cancel", cfg: Config{Cancel: 1}, wantFut: gowp.ErrTaskCanceled},
+		{name: "delay", cfg: Config{Delay: 1, MaxDelay: time.Millisecond}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wp, _ := gowp.New(2, gowp.WithNumWorkers(1))
+
+			// hold the only worker, so that the cancellation happens before the task starts.
+			started, release := make(chan struct{}), make(chan struct{})
+			_ = wp.Submit(func() error { close(started); <-release; return nil })
+			<-started
+
+			cp := New(tt.cfg).Pool(wp)
+			fut, err := cp.SubmitFuture(func() error { return nil })
+			if !errors.Is(err, tt.wantErr) || (err != nil && !strings.HasPrefix(err.Error(), "chaos.Pool.SubmitFuture(): ")) {
+				t.Fatalf("Pool.SubmitFuture() error = %v, want %v", err, tt.wantErr)
+			}
+
+			close(release)
+
+			if fut != nil && !errors.Is(fut.Err(), tt.wantFut) {
+				t.Errorf("Future.Err() = %v, want %v", fut.Err(), tt.wantFut)
+			}
+
+			if err := cp.Wait(); err != nil {
+				t.Errorf("Pool.Wait() = %v, want nil", err)
+			}
+		})
+	}
+}
+
+func TestPool_nilTask(t *testing.T) {
+	wp, _ := gowp.New(1)
+	cp := New(Config{}).Pool(wp)
+
+	if err := cp.Submit(nil); !errors.Is(err, gowp.ErrNilTask) || !strings.HasPrefix(err.Error(), "chaos.Pool.Submit(): gowp.Pool") {
+		t.Errorf("Pool.Submit() error = %v, want %v", err, gowp.ErrNilTask)
+	}
+
+	if _, err := cp.SubmitFuture(nil); !errors.Is(err, gowp.ErrNilTask) {
+		t.Errorf("Pool.SubmitFuture() error = %v, want %v", err, gowp.ErrNilTask)
+	}
+}
+
+func TestDelay_clock(t *testing.T) {
+	cfg := Config{Delay: 1, MaxDelay: time.Hour}
+
+	tests := []struct {
+		name   string
+		submit func(c *testutil.FakeClock, task gowp.Task) (*gowp.Future, error)
+	}{
+		{
+			name: "Pool",
+			submit: func(c *testutil.FakeClock, task gowp.Task) (*gowp.Future, error) {
+				wp, _ := gowp.New(1, gowp.WithClock(c))
+				return New(cfg).Pool(wp).SubmitFuture(task)
+			},
+		},
+		{
+			name: "WithChaos",
+			submit: func(c *testutil.FakeClock, task gowp.Task) (*gowp.Future, error) {
+				cfg := cfg
+				cfg.Clock = c
+				wp, _ := gowp.New(1, gowp.WithClock(c), WithChaos(cfg))
+				return wp.SubmitFuture(task)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := testutil.NewFakeClock(time.Now())
+			ran := make(chan struct{})
+
+			fut, err := tt.submit(c, func() error { close(ran); return nil })
+			if err != nil {
+				t.Fatalf("SubmitFuture() error = %v", err)
+			}
+
+			c.WaitForTimers(1)
+			select {
+			case <-ran:
+				t.Fatal("the task ran before the clock was advanced")
+			default:
+			}
+
+			c.Advance(cfg.MaxDelay)
+			if err := fut.Err(); err != nil {
+				t.Errorf("Future.Err() = %v, want nil", err)
+			}
+		})
+	}
+}
+
+func TestMonkey_Wrap(t *testing.T) {
+	task := New(Config{Panic: 1}).Wrap(func() error { return nil })
+
+	defer func() {
+		if r := recover(); r != ErrPanic {
+			t.Errorf("recovered %v, want %v", r, ErrPanic)
+		}
+	}()
+
+	_ = task()
+}
+
+func TestWithChaos(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr error
+	}{
+		{name: "no faults", cfg: Config{Reject: 1, Cancel: 1}},
+		{name: "fail", cfg: Config{Fail: 1}, wantErr: ErrFailed},
+		{name: "delay", cfg: Config{Delay: 1, MaxDelay: time.Millisecond}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wp, _ := gowp.New(10, gowp.WithNumWorkers(2), WithChaos(tt.cfg))
+
+			ran := make(chan struct{}, 10)
+			for i := 0; i < 10; i++ {
+				if err := wp.Submit(func() error { ran <- struct{}{}; return nil }); err != nil {
+					t.Fatalf("Pool.Submit() error = %v", err)
+				}
+			}
+
+			if err := wp.Wait(); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Pool.Wait() = %v, want %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr == nil && len(ran) != 10 {
+				t.Errorf("%d tasks ran, want 10", len(ran))
+			}
+		})
+	}
+}
+
+func TestWithChaos_seed(t *testing.T) {
+	failures := func() []int {
+		var failed []int
+		wp, _ := gowp.New(100, gowp.WithNumWorkers(1), WithChaos(Config{Seed: 3, Fail: 0.3}))
+		for i := 0; i < 100; i++ {
+			i := i
+			f, _ := wp.SubmitFuture(func() error { return nil })
+			if f.Err() != nil {
+				failed = append(failed, i)
+			}
+		}
+		_ = wp.Wait()
+
+		return failed
+	}
+
+	first := failures()
+	if len(first) == 0 || len(first) == 100 {
+		t.Fatalf("%d of 100 tasks failed, want some", len(first))
+	}
+
+	if !reflect.DeepEqual(first, failures()) {
+		t.Error("failures are not reproducible for the same seed")
+	}
+}
@@ -0,0 +1,59 @@
+package gowp
+
+import "fmt"
+
+// Child creates a pool nested in p, with the same queue size, unless WithQueueSize is used, and the given options. It is bound to the context
+// of p, any WithContext option is overridden, and it follows p: closing p closes the child, its queued tasks are
+// still executed, and p stopping, e.g. on a failed task or a cancelled context, stops the child with the same
+// error. The child is independent otherwise, its tasks and errors don't count for p and it has to be waited for
+// on its own. Child fails with ErrPoolClosed once p is closed or stopped.
+func (p *Pool) Child(opts ...Option) (*Pool, error) {
+	cfg, err := newConfig(p.size, append(opts[:len(opts):len(opts)], WithContext(p.ctx)))
+	if err != nil {
+		return nil, fmt.Errorf("gowp.Pool.Child(): %w", err)
+	}
+
+	if cfg.queueSize == 0 {
+		cfg.queueSize = p.size
+	}
+
+	c := newPool(cfg, 0) // the number of tasks of the child is not known.
+
+	p.mu.Lock()
+	if p.intakeOff || p.halted {
+		p.mu.Unlock()
+		c.Close()
+		_ = c.wait()
+		return nil, fmt.Errorf("gowp.Pool.Child(): %w", ErrPoolClosed)
+	}
+
+	if p.children == nil {
+		p.children = make(map[*Pool]struct{})
+	}
+	p.children[c] = struct{}{}
+	c.parent = p
+	p.mu.Unlock()
+
+	return c, nil
+}
+
+// orphan stops tracking c, which has completed.
+func (p *Pool) orphan(c *Pool) {
+	p.mu.Lock()
+	delete(p.children, c)
+	p.mu.Unlock()
+}
+
+// childList returns the children of p. p.mu must be held.
+func (p *Pool) childList() []*Pool {
+	if len(p.children) == 0 {
+		return nil
+	}
+
+	children := make([]*Pool, 0, len(p.children))
+	for c := range p.children {
+		children = append(children, c)
+	}
+
+	return children
+}
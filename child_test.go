@@ -0,0 +1,105 @@
+package gowp
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+)
+
+func TestPool_Child(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	tests := []struct {
+		name    string
+		parent  func(p *Pool, cancel context.CancelFunc) // acts on the parent once the child has a task queued.
+		wantRan bool                                     // whether the queued task of the child runs.
+		wantErr error
+	}{
+		{
+			name:    "close cascades",
+			parent:  func(p *Pool, _ context.CancelFunc) { p.Close() },
+			wantRan: true,
+		},
+		{
+			name: "failure cascades",
+			parent: func(p *Pool, _ context.CancelFunc) {
+				_ = p.Submit(func() error { return errBoom })
+			},
+			wantErr: errBoom,
+		},
+		{
+			name:    "cancellation cascades",
+			parent:  func(_ *Pool, cancel context.CancelFunc) { cancel() },
+			wantErr: context.Canceled,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			p, err := New(4, WithNumWorkers(1), WithExitOnError(true), WithContext(ctx))
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+
+			c, err := p.Child(WithNumWorkers(1))
+			if err != nil {
+				t.Fatalf("Child() error = %v", err)
+			}
+
+			release := make(chan struct{})
+			if err := c.Submit(func() error { <-release; return nil }); err != nil {
+				t.Fatalf("Submit() error = %v", err)
+			}
+
+			var ran int32
+			if err := c.Submit(func() error { atomic.StoreInt32(&ran, 1); return nil }); err != nil {
+				t.Fatalf("Submit() error = %v", err)
+			}
+
+			tt.parent(p, cancel)
+
+			if tt.wantErr != nil {
+				<-c.quit // the child stops before the blocker is released.
+			} else {
+				<-c.closing
+			}
+			close(release)
+
+			if err := c.Wait(); !errors.Is(err, tt.wantErr) || (tt.wantErr == nil && err != nil) {
+				t.Errorf("child Wait() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if got := atomic.LoadInt32(&ran) == 1; got != tt.wantRan {
+				t.Errorf("queued task of the child ran = %v, want %v", got, tt.wantRan)
+			}
+
+			p.Close()
+			_ = p.Wait()
+
+			p.mu.Lock()
+			left := len(p.children)
+			p.mu.Unlock()
+			if left != 0 {
+				t.Errorf("parent tracks %d children after they completed, want 0", left)
+			}
+		})
+	}
+}
+
+func TestPool_Child_closed(t *testing.T) {
+	p, err := New(1)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	p.Close()
+	if _, err := p.Child(); !errors.Is(err, ErrPoolClosed) {
+		t.Errorf("Child() error = %v, want %v", err, ErrPoolClosed)
+	}
+
+	_ = p.Wait()
+}
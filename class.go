@@ -0,0 +1,75 @@
+package gowp
+
+import "fmt"
+
+// WithClassLimits returns an Option that bounds the number of tasks of each class running at once, on top of
+// the number of workers, e.g. WithClassLimits(map[string]int{"db": 4}) runs at most 4 "db" tasks at once,
+// while the tasks of other classes use the rest of the workers. Tasks are tagged with SubmitClass or TaskClass,
+// classes without a limit and tasks without a class are not bounded. Workers skip the queued tasks of a class
+// that is at its limit and pick the next task of another class, if any, the skipped ones keep their place.
+//
+// Limits should be greater than zero, otherwise ErrInvalidClassLimit will be returned on Pool initialization.
+func WithClassLimits(limits map[string]int) Option {
+	return func(o *config) {
+		o.classes = make(map[string]int, len(limits))
+		for class, n := range limits {
+			o.classes[class] = n
+		}
+	}
+}
+
+// TaskClass returns a TaskOption that tags the task with class, see WithClassLimits.
+func TaskClass(class string) TaskOption {
+	return func(j *job) {
+		j.class = class
+	}
+}
+
+// SubmitClass submits t tagged with class, see WithClassLimits.
+func (p *Pool) SubmitClass(class string, t Task) error {
+	if err := p.submit(t, nil, []TaskOption{TaskClass(class)}); err != nil {
+		return fmt.Errorf("gowp.Pool.SubmitClass(): %w", err)
+	}
+
+	return nil
+}
+
+// classLimit bounds the number of running tasks of a class, see WithClassLimits.
+type classLimit struct {
+	limit   int
+	running int // guarded by the mutex of the pool.
+}
+
+// newClassLimits returns the limit of each class.
+func newClassLimits(limits map[string]int) map[string]*classLimit {
+	classes := make(map[string]*classLimit, len(limits))
+	for class, n := range limits {
+		classes[class] = &classLimit{limit: n}
+	}
+
+	return classes
+}
+
+// admits reports whether j can start without exceeding the limit of its class. p.mu must be held.
+func (p *Pool) admits(j *job) bool {
+	c := p.classes[j.class]
+	return c == nil || c.running < c.limit
+}
+
+// pop pops the next queued job that the class limits admit, nil if there is none. p.mu must be held.
+func (p *Pool) pop() *job {
+	if p.classes == nil {
+		return p.queue.pop()
+	}
+
+	return p.queue.popIf(p.admits)
+}
+
+// exit accounts for a job of a limited class that a worker is done with, releasing its slot in the class.
+func (p *Pool) exit(j *job) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.classes[j.class].running--
+	p.ready.Broadcast() // workers may have skipped the jobs of the class because of its limit.
+}
@@ -0,0 +1,114 @@
+package gowp
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWithClassLimits(t *testing.T) {
+	tests := []struct {
+		name     string
+		limits   map[string]int
+		class    string
+		wantPeak int64 // maximum number of tasks of the class running at once.
+	}{
+		{name: "limited class", limits: map[string]int{"db": 2}, class: "db", wantPeak: 2},
+		{name: "class without limit", limits: map[string]int{"db": 2}, class: "cpu", wantPeak: 6},
+		{name: "no class", limits: map[string]int{"db": 2}, wantPeak: 6},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := testPool(context.Background(), 6, testDefaultNumTasks, false, WithClassLimits(tt.limits))
+
+			var load, peak int64
+			started := make(chan struct{}, 6)
+			release := make(chan struct{})
+			for i := 0; i < 6; i++ {
+				err := p.SubmitClass(tt.class, func() error {
+					n := atomic.AddInt64(&load, 1)
+					for {
+						old := atomic.LoadInt64(&peak)
+						if n <= old || atomic.CompareAndSwapInt64(&peak, old, n) {
+							break
+						}
+					}
+					started <- struct{}{}
+
+					<-release
+					atomic.AddInt64(&load, -1)
+
+					return nil
+				})
+				if err != nil {
+					t.Fatalf("Pool.SubmitClass() error = %v", err)
+				}
+			}
+
+			for i := int64(0); i < tt.wantPeak; i++ {
+				<-started
+			}
+			time.Sleep(10 * time.Millisecond) // give the workers a chance to go over the limit.
+			close(release)
+
+			if err := p.Wait(); err != nil {
+				t.Fatalf("Pool.Wait() error = %v", err)
+			}
+
+			if peak != tt.wantPeak {
+				t.Errorf("peak = %d, want %d", peak, tt.wantPeak)
+			}
+		})
+	}
+
+	if _, err := New(testDefaultNumTasks, WithClassLimits(map[string]int{"db": 0})); !errors.Is(err, ErrInvalidClassLimit) {
+		t.Errorf("New() = %v, want %v", err, ErrInvalidClassLimit)
+	}
+}
+
+func TestWithClassLimits_saturated(t *testing.T) {
+	p := testPool(context.Background(), 8, 40, false, WithClassLimits(map[string]int{"db": 2}))
+
+	release := make(chan struct{})
+	for i := 0; i < 20; i++ {
+		if err := p.SubmitClass("db", func() error { <-release; return nil }); err != nil {
+			t.Fatalf("Pool.SubmitClass() error = %v", err)
+		}
+	}
+
+	var ran int64
+	done := make(chan struct{}, 20)
+	for i := 0; i < 20; i++ {
+		_ = p.Submit(func() error {
+			atomic.AddInt64(&ran, 1)
+			done <- struct{}{}
+			return nil
+		})
+	}
+
+	// the tasks without a class run on the workers left while "db" is at its limit.
+	for i := 0; i < 20; i++ {
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatalf("%d of the 20 tasks without a class ran while db was saturated", atomic.LoadInt64(&ran))
+		}
+	}
+
+	for deadline := time.Now().Add(time.Second); p.Running() != 2 && time.Now().Before(deadline); {
+		time.Sleep(time.Millisecond) // the last tasks without a class are returning.
+	}
+
+	if n := p.Running(); n != 2 {
+		t.Errorf("Pool.Running() = %d, want the 2 db tasks", n)
+	}
+
+	close(release)
+
+	if err := p.Wait(); err != nil {
+		t.Errorf("Pool.Wait() error = %v", err)
+	}
+}
@@ -0,0 +1,57 @@
+package gowp
+
+import "time"
+
+type (
+	// Clock tells the time and runs functions after a delay. The pool measures durations, delays tasks
+	// and arms its timeouts with it, see WithClock. The system clock is used by default.
+	Clock interface {
+		Now() time.Time
+		// AfterFunc calls fn in its own goroutine once d has elapsed, see time.AfterFunc.
+		AfterFunc(d time.Duration, fn func()) Timer
+		// NewTicker returns a Ticker delivering the time every d, see time.NewTicker.
+		NewTicker(d time.Duration) Ticker
+	}
+
+	// Timer is a function call armed by Clock.AfterFunc.
+	Timer interface {
+		// Stop prevents the call, it returns false if the call has been made or stopped already.
+		Stop() bool
+	}
+
+	// Ticker delivers ticks of a Clock.
+	Ticker interface {
+		C() <-chan time.Time
+		Stop()
+	}
+
+	systemClock  struct{}
+	systemTicker struct{ t *time.Ticker }
+)
+
+// interface guard
+var _ Clock = systemClock{}
+
+// WithClock returns an Option that sets the clock of the pool, e.g. a fake one advanced by tests
+// of time-dependent behavior, so that they don't need real sleeps. See testutil.FakeClock.
+// A nil clock results in ErrNilClock on Pool initialization.
+func WithClock(c Clock) Option {
+	return func(o *config) {
+		o.clock = c
+	}
+}
+
+// Clock returns the clock of the pool, see WithClock.
+func (p *Pool) Clock() Clock {
+	return p.clock
+}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+func (systemClock) AfterFunc(d time.Duration, fn func()) Timer { return time.AfterFunc(d, fn) }
+
+func (systemClock) NewTicker(d time.Duration) Ticker { return systemTicker{time.NewTicker(d)} }
+
+func (t systemTicker) C() <-chan time.Time { return t.t.C }
+
+func (t systemTicker) Stop() { t.t.Stop() }
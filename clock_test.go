@@ -0,0 +1,60 @@
+package gowp
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testClock is a Clock whose time is set by tests, its timers are real.
+type testClock struct {
+	systemClock
+
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+func (c *testClock) advance(d time.Duration) {
+	c.mu.Lock()
+	c.now = c.now.Add(d)
+	c.mu.Unlock()
+}
+
+func TestWithClock(t *testing.T) {
+	if _, err := New(1, WithClock(nil)); !errors.Is(err, ErrNilClock) {
+		t.Errorf("New() error = %v, want %v", err, ErrNilClock)
+	}
+
+	p, _ := New(1)
+	if _, ok := p.Clock().(systemClock); !ok {
+		t.Errorf("Pool.Clock() = %T, want the system clock", p.Clock())
+	}
+	_ = p.Wait()
+
+	c := &testClock{now: time.Now()}
+	p, _ = New(2, WithNumWorkers(1), WithClock(c), WithSaturationLimit(0.5, time.Minute))
+	p.Pause()
+	_ = p.Submit(testNoOpFunc)
+
+	if err := p.Healthy(); err != nil {
+		t.Errorf("Pool.Healthy() = %v, want nil before the window elapsed", err)
+	}
+
+	c.advance(2 * time.Minute)
+	if err := p.Healthy(); !errors.Is(err, ErrSaturated) {
+		t.Errorf("Pool.Healthy() = %v, want %v once the clock went past the window", err, ErrSaturated)
+	}
+
+	p.Resume()
+	if err := p.Wait(); err != nil {
+		t.Errorf("Pool.Wait() = %v, want nil", err)
+	}
+}
@@ -0,0 +1,86 @@
+package gowp
+
+import (
+	"fmt"
+	"time"
+)
+
+// coalesced holds the latest submission for a key, it is the one executed once the window elapses.
+type coalesced struct {
+	t    Task
+	opts []TaskOption
+	f    *Future // shared by all the submissions of the window.
+}
+
+// WithCoalesceWindow returns an Option that sets the window of SubmitCoalesced.
+// Zero, the default, only merges the submissions racing each other.
+// A negative window results in ErrInvalidWindow on Pool initialization.
+func WithCoalesceWindow(window time.Duration) Option {
+	return func(o *config) {
+		o.window = window
+	}
+}
+
+// SubmitCoalesced submits t under key, merging it with the other submissions of the key that arrive within
+// the window set by WithCoalesceWindow. The first submission opens the window and the task is queued
+// once the window has elapsed. Later submissions within the window replace the task and its options,
+// so that only the latest one is executed, and share the returned Future. It suits "recompute on change"
+// workloads, where bursts of identical triggers need a single execution.
+//
+// The window starts with the first submission and is not extended by the following ones,
+// so that a steady stream of triggers doesn't postpone the execution forever.
+// A pending task keeps the pool open, like the ones of SubmitAfter. Cancelling the Future cancels it for all the callers.
+func (p *Pool) SubmitCoalesced(key string, t Task, opts ...TaskOption) (*Future, error) {
+	if t == nil {
+		return nil, fmt.Errorf("gowp.Pool.SubmitCoalesced(): %w", ErrNilTask)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if c, ok := p.coalesced[key]; ok {
+		c.t, c.opts = t, opts
+		return c.f, nil
+	}
+
+	c := &coalesced{t: t, opts: opts, f: newFuture()}
+	c.f.pool = p
+	c.f.id = p.nextID()
+
+	err := p.holdLocked(c.f, false, func() (stop func()) {
+		tm := p.clock.AfterFunc(p.window, func() { p.releaseCoalesced(key, c) })
+
+		return func() {
+			tm.Stop()
+			p.forgetCoalesced(key, c)
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("gowp.Pool.SubmitCoalesced(): %w", err)
+	}
+
+	if p.coalesced == nil {
+		p.coalesced = make(map[string]*coalesced)
+	}
+	p.coalesced[key] = c
+
+	return c.f, nil
+}
+
+// releaseCoalesced submits the latest task of key once its window has elapsed.
+func (p *Pool) releaseCoalesced(key string, c *coalesced) {
+	p.mu.Lock()
+	p.forgetCoalesced(key, c)
+	t, opts := c.t, c.opts
+	p.mu.Unlock()
+
+	// don't modify the backing array of the caller's options.
+	p.fire(t, c.f, append(opts[:len(opts):len(opts)], func(j *job) { j.delayed = true }), nil)
+}
+
+// forgetCoalesced closes the window of key, later submissions open a new one. p.mu must be held.
+func (p *Pool) forgetCoalesced(key string, c *coalesced) {
+	if p.coalesced[key] == c {
+		delete(p.coalesced, key)
+	}
+}
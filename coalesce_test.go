@@ -0,0 +1,97 @@
+package gowp
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestPool_SubmitCoalesced(t *testing.T) {
+	p, err := New(testDefaultNumTasks, WithNumWorkers(2), WithCoalesceWindow(20*time.Millisecond))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	var runs, last int32
+	recompute := func(n int32) Task {
+		return func() error {
+			atomic.AddInt32(&runs, 1)
+			atomic.StoreInt32(&last, n)
+			return nil
+		}
+	}
+
+	first, err := p.SubmitCoalesced("key", recompute(1))
+	if err != nil {
+		t.Fatalf("Pool.SubmitCoalesced() error = %v", err)
+	}
+
+	for n := int32(2); n <= 5; n++ {
+		if f, _ := p.SubmitCoalesced("key", recompute(n)); f != first {
+			t.Error("Pool.SubmitCoalesced() within the window returned a new Future")
+		}
+	}
+
+	other, _ := p.SubmitCoalesced("other", testFuncWithErr)
+
+	if err := first.Err(); err != nil {
+		t.Errorf("Future.Err() = %v, want nil", err)
+	}
+
+	if n := atomic.LoadInt32(&last); n != 5 {
+		t.Errorf("executed submission %d, want the latest one 5", n)
+	}
+
+	// the window is closed once the task has been released.
+	next, _ := p.SubmitCoalesced("key", recompute(6))
+	if next == first {
+		t.Error("Pool.SubmitCoalesced() after the window returned the old Future")
+	}
+
+	if err := p.Wait(); !errors.Is(err, testErr) {
+		t.Errorf("Pool.Wait() = %v, want %v", err, testErr)
+	}
+
+	if err := other.Err(); !errors.Is(err, testErr) {
+		t.Errorf("Future.Err() = %v, want %v", err, testErr)
+	}
+
+	if n := atomic.LoadInt32(&runs); n != 2 {
+		t.Errorf("task ran %d times, want 2", n)
+	}
+
+	if _, err := p.SubmitCoalesced("key", testNoOpFunc); !errors.Is(err, ErrPoolClosed) {
+		t.Errorf("Pool.SubmitCoalesced() after Wait = %v, want %v", err, ErrPoolClosed)
+	}
+}
+
+func TestPool_SubmitCoalesced_cancel(t *testing.T) {
+	p, _ := New(testDefaultNumTasks, WithCoalesceWindow(time.Hour))
+
+	f, _ := p.SubmitCoalesced("key", testNoOpFunc)
+	if !f.Cancel() {
+		t.Error("Future.Cancel() = false within the window")
+	}
+
+	if next, _ := p.SubmitCoalesced("key", testNoOpFunc); next == f {
+		t.Error("Pool.SubmitCoalesced() after Cancel returned the cancelled Future")
+	} else {
+		next.Cancel()
+	}
+
+	if err := p.Wait(); err != nil {
+		t.Errorf("Pool.Wait() = %v, want nil", err)
+	}
+
+	if r := p.report(); r.Canceled != 2 {
+		t.Errorf("Report.Canceled = %d, want 2", r.Canceled)
+	}
+}
+
+func TestWithCoalesceWindow(t *testing.T) {
+	if _, err := New(1, WithCoalesceWindow(-time.Second), WithContext(context.Background())); !errors.Is(err, ErrInvalidWindow) {
+		t.Errorf("New() error = %v, want %v", err, ErrInvalidWindow)
+	}
+}
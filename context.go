@@ -0,0 +1,55 @@
+package gowp
+
+import (
+	"context"
+	"fmt"
+)
+
+// valuesContext is a context with the values of one context and the deadline and cancellation of another.
+type valuesContext struct {
+	context.Context // cancellation.
+
+	values context.Context
+}
+
+// SubmitContext submits t like SubmitFuture, on behalf of a caller whose context is ctx. t receives a context
+// that carries the values of ctx, e.g. request-scoped metadata, trace IDs or baggage, and that is cancelled
+// along with the context of the pool, see Context, not along with ctx: t may outlive the request that
+// submitted it. ctx is also the context of the task for WithTracePropagation, see TaskContext.
+func (p *Pool) SubmitContext(ctx context.Context, t func(ctx context.Context) error, opts ...TaskOption) (*Future, error) {
+	if ctx == nil {
+		return nil, fmt.Errorf("gowp.Pool.SubmitContext(): %w", ErrNilContext)
+	}
+
+	if t == nil {
+		return nil, fmt.Errorf("gowp.Pool.SubmitContext(): %w", ErrNilTask)
+	}
+
+	tctx := withValuesOf(p.Context(), ctx)
+
+	// don't modify the backing array of the caller's options.
+	opts = append(opts[:len(opts):len(opts)], TaskContext(ctx))
+
+	f := newFuture()
+	f.requeueable = true
+
+	if err := p.submit(func() error { return t(tctx) }, f, opts); err != nil {
+		return nil, fmt.Errorf("gowp.Pool.SubmitContext(): %w", err)
+	}
+
+	return f, nil
+}
+
+// withValuesOf returns a context that is cancelled along with parent and carries the values of values
+// before the ones of parent.
+func withValuesOf(parent, values context.Context) context.Context {
+	return valuesContext{Context: parent, values: values}
+}
+
+func (c valuesContext) Value(key any) any {
+	if v := c.values.Value(key); v != nil {
+		return v
+	}
+
+	return c.Context.Value(key)
+}
@@ -0,0 +1,79 @@
+package gowp
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type testCtxKey string
+
+func TestPool_SubmitContext(t *testing.T) {
+	tests := []struct {
+		name       string
+		cancelCall bool // cancels the context of the caller while the task runs.
+		cancelPool bool // cancels the context of the pool while the task runs.
+		wantErr    error
+	}{
+		{name: "values"},
+		{name: "caller cancelled", cancelCall: true},
+		{name: "pool cancelled", cancelPool: true, wantErr: context.Canceled},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pctx, pcancel := context.WithCancel(context.WithValue(context.Background(), testCtxKey("pool"), "p"))
+			defer pcancel()
+
+			p, err := New(1, WithContext(pctx))
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+
+			ctx, cancel := context.WithCancel(context.WithValue(context.Background(), testCtxKey("request"), "r"))
+			defer cancel()
+
+			started, release := make(chan struct{}), make(chan struct{})
+			f, err := p.SubmitContext(ctx, func(ctx context.Context) error {
+				close(started)
+				<-release
+
+				if v := ctx.Value(testCtxKey("request")); v != "r" {
+					t.Errorf("request value = %v, want r", v)
+				}
+
+				if v := ctx.Value(testCtxKey("pool")); v != "p" {
+					t.Errorf("pool value = %v, want p", v)
+				}
+
+				return ctx.Err()
+			})
+			if err != nil {
+				t.Fatalf("SubmitContext() error = %v", err)
+			}
+
+			<-started
+			if tt.cancelCall {
+				cancel()
+			}
+
+			if tt.cancelPool {
+				pcancel()
+				<-p.Context().Done()
+			}
+			close(release)
+
+			if err := f.Err(); !errors.Is(err, tt.wantErr) || (tt.wantErr == nil && err != nil) {
+				t.Errorf("Future.Err() = %v, want %v", err, tt.wantErr)
+			}
+
+			_ = p.Wait()
+		})
+	}
+
+	p, _ := New(1)
+	if _, err := p.SubmitContext(nil, func(context.Context) error { return nil }); !errors.Is(err, ErrNilContext) {
+		t.Errorf("SubmitContext() error = %v, want %v", err, ErrNilContext)
+	}
+	_ = p.Wait()
+}
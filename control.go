@@ -0,0 +1,209 @@
+package gowp
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+// Stats is a snapshot of the state of a pool, see Pool.Stats.
+type Stats struct {
+	Workers  int  // workers running, including temporary ones, see WithWorkerBoost and WithBurst.
+	Idle     int  // workers waiting for a task. Workers of a paused pool are idle.
+	Running  int  // tasks being executed.
+	Queued   int  // tasks waiting for a worker.
+	Held     int  // tasks held back before being queued, see SubmitAfter and After.
+	Restarts int  // workers replaced after a task panicked, see WithPanicRecovery.
+	Limit    int  // tasks allowed to run at once, see WithAdaptiveConcurrency and WithGCPressureLimit. Zero, if not limited.
+	Paused   bool // see Pause.
+	Closed   bool // the pool doesn't accept tasks anymore.
+
+	Throttled bool // the workers don't pick new tasks while memory is under pressure, see WithMemoryThrottle.
+
+	Submitted int // tasks accepted by the pool.
+	Succeeded int // tasks that returned nil.
+	Failed    int // tasks that returned an error.
+	Ignored   int // tasks that returned an error classified as SeverityIgnore.
+	Canceled  int // tasks cancelled through their Future before they started.
+	Discarded int // tasks dropped without execution.
+	Expired   int // tasks dropped because their deadline passed while queued, see TaskDeadline.
+
+	QueueWait Histogram // time tasks waited between their submission and the start of their execution.
+	Duration  Histogram // time tasks took to execute, see Histogram.Quantile for percentiles.
+}
+
+// Stats returns a snapshot of the state of the pool. It is meant for monitoring,
+// the numbers may be stale as soon as it returns.
+func (p *Pool) Stats() Stats {
+	p.mu.Lock()
+	s := Stats{
+		Workers: p.workers + p.boosted,
+		Idle:    p.idle,
+		Queued:  p.queuedLocked(),
+		Held:    p.scheduled,
+		Paused:  p.paused,
+	}
+	s.Throttled = p.throttled
+	s.Limit = p.allowed()
+	p.mu.Unlock()
+
+	if p.shards != nil {
+		s.Queued += p.shards.len()
+	}
+
+	s.Running = p.inflight.len()
+	s.Restarts = int(atomic.LoadInt64(&p.counts.restarts))
+
+	r := p.tally()
+	s.Closed = p.IsClosed()
+	s.Submitted, s.Succeeded, s.Failed = r.Submitted, r.Succeeded, r.Failed
+	s.Ignored, s.Canceled, s.Discarded = r.Ignored, r.Canceled, r.Discarded
+	s.Expired = r.Expired
+	s.QueueWait = p.queueWait.snapshot()
+	s.Duration = p.duration.snapshot()
+
+	return s
+}
+
+// Pending returns the number of tasks waiting for a worker, like Stats().Queued.
+// Tasks held back, e.g. by SubmitAfter, are not counted.
+func (p *Pool) Pending() int {
+	p.mu.Lock()
+	n := p.queuedLocked()
+	p.mu.Unlock()
+
+	if p.shards != nil {
+		n += p.shards.len()
+	}
+
+	return n
+}
+
+// Running returns the number of tasks being executed.
+func (p *Pool) Running() int {
+	return p.inflight.len()
+}
+
+// Workers returns the number of workers running, including temporary ones, like Stats().Workers.
+func (p *Pool) Workers() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.workers + p.boosted
+}
+
+// Cap returns the number of tasks the pool can hold waiting for a worker, see New and WithShards.
+// Cap() - Pending() is the room left for submissions, as long as no other goroutine submits tasks.
+func (p *Pool) Cap() int {
+	if p.shards != nil {
+		return p.size + p.shards.size*len(p.shards.list)
+	}
+
+	return p.size
+}
+
+// Purge discards the tasks waiting for a worker and returns how many there were, e.g. once a change upstream
+// makes them obsolete. Their Futures report ErrTaskDiscarded. Running tasks are not interrupted, tasks held back,
+// e.g. by SubmitAfter, are left alone and the pool keeps accepting tasks.
+func (p *Pool) Purge() int {
+	p.mu.Lock()
+	left := p.queue.removeIf(func(*job) bool { return true })
+	p.ready.Broadcast() // workers of a closed pool may exit now.
+	p.room.Broadcast()
+	p.mu.Unlock()
+
+	if p.shards != nil {
+		left = append(left, p.shards.drain()...)
+	}
+
+	n := 0
+	for _, j := range left {
+		if p.drop(j) {
+			n++
+		}
+	}
+
+	return n
+}
+
+// queuedLocked returns the number of live jobs in the queue. p.mu must be held.
+func (p *Pool) queuedLocked() int {
+	n := p.queue.len() - int(atomic.LoadInt64(&p.counts.tombstones))
+	if n < 0 {
+		return 0 // the tombstone of a task is counted before the task is.
+	}
+
+	return n
+}
+
+// Pause stops the workers from picking new tasks, the running ones are not interrupted.
+// Tasks can still be submitted, they wait in the queue until Resume is called. Wait doesn't return
+// while a paused pool has queued tasks, unless the pool stops, e.g. because its context is cancelled.
+func (p *Pool) Pause() {
+	p.mu.Lock()
+	p.paused = true
+	p.mu.Unlock()
+
+	p.emit(Event{Kind: EventPaused})
+}
+
+// Resume lets the workers pick tasks again after Pause.
+func (p *Pool) Resume() {
+	p.mu.Lock()
+	p.paused = false
+	p.ready.Broadcast()
+	p.mu.Unlock()
+
+	p.emit(Event{Kind: EventResumed})
+
+	if p.inline {
+		p.runInline()
+	}
+}
+
+// Resize changes the number of regular workers of the pool to n. New workers start right away,
+// surplus workers exit once they are done with their current task. The limits of WithWorkerBoost,
+// WithBurst and WithAdaptiveConcurrency keep referring to the number of workers the pool was created with.
+// It fails with ErrInvalidWorkerCnt if n is not greater than zero and with ErrPoolClosed once the workers have exited.
+func (p *Pool) Resize(n int) error {
+	if n <= 0 {
+		return fmt.Errorf("gowp.Pool.Resize(): %w", ErrInvalidWorkerCnt)
+	}
+
+	if err := p.resize(n); err != nil {
+		return fmt.Errorf("gowp.Pool.Resize(): %w", err)
+	}
+
+	p.emit(Event{Kind: EventResized, Workers: n})
+
+	return nil
+}
+
+// resize is Resize without decorating the error.
+func (p *Pool) resize(n int) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	select {
+	case <-p.quit:
+		return ErrPoolClosed
+	default:
+	}
+
+	// while some regular workers are around, Wait is blocked on them and it is safe to add to the wait group.
+	if p.workers == 0 && !p.lazy {
+		return ErrPoolClosed
+	}
+
+	p.target = n
+	for ; p.workers < n && !p.lazy; p.workers++ {
+		p.spawn(false)
+	}
+
+	if p.lazy && p.workers > 0 {
+		p.grow() // for the jobs queued meanwhile.
+	}
+
+	p.ready.Broadcast() // surplus idle workers exit.
+
+	return nil
+}
@@ -0,0 +1,187 @@
+package gowp
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestPool_Pause(t *testing.T) {
+	p := testPool(context.Background(), 2, testDefaultNumTasks, false)
+	p.Pause()
+
+	var ran int32
+	for i := 0; i < 3; i++ {
+		_ = p.Submit(func() error {
+			atomic.AddInt32(&ran, 1)
+			return nil
+		})
+	}
+
+	time.Sleep(10 * time.Millisecond)
+
+	if s := p.Stats(); !s.Paused || s.Queued != 3 || atomic.LoadInt32(&ran) != 0 {
+		t.Errorf("Pool.Stats() = %+v with %d tasks run, want 3 queued and paused", s, ran)
+	}
+
+	p.Resume()
+
+	if err := p.Wait(); err != nil {
+		t.Errorf("Pool.Wait() = %v, want nil", err)
+	}
+
+	if s := p.Stats(); s.Succeeded != 3 || s.Workers != 0 || !s.Closed {
+		t.Errorf("Pool.Stats() = %+v, want 3 succeeded without workers", s)
+	}
+}
+
+func TestPool_Resize(t *testing.T) {
+	p := testPool(context.Background(), 1, testDefaultNumTasks, false)
+
+	tests := []struct {
+		name        string
+		n           int
+		wantErr     error
+		wantWorkers int
+	}{
+		{name: "grow", n: 4, wantWorkers: 4},
+		{name: "shrink", n: 2, wantWorkers: 2},
+		{name: "invalid", n: 0, wantErr: ErrInvalidWorkerCnt, wantWorkers: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := p.Resize(tt.n); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Pool.Resize() error = %v, want %v", err, tt.wantErr)
+			}
+
+			// surplus workers exit asynchronously.
+			deadline := time.Now().Add(time.Second)
+			for p.Stats().Workers != tt.wantWorkers && time.Now().Before(deadline) {
+				time.Sleep(time.Millisecond)
+			}
+
+			if s := p.Stats(); s.Workers != tt.wantWorkers {
+				t.Errorf("Pool.Stats().Workers = %d, want %d", s.Workers, tt.wantWorkers)
+			}
+		})
+	}
+
+	_ = p.Wait()
+
+	if err := p.Resize(3); !errors.Is(err, ErrPoolClosed) {
+		t.Errorf("Pool.Resize() after Wait = %v, want %v", err, ErrPoolClosed)
+	}
+}
+
+func TestPool_introspection(t *testing.T) {
+	p := testPool(context.Background(), 2, testDefaultNumTasks, false)
+
+	started := make(chan struct{}, 2)
+	release := make(chan struct{})
+	for i := 0; i < 5; i++ {
+		_ = p.Submit(func() error {
+			started <- struct{}{}
+			<-release
+			return nil
+		})
+	}
+
+	<-started
+	<-started
+
+	if got := p.Running(); got != 2 {
+		t.Errorf("Pool.Running() = %d, want 2", got)
+	}
+
+	if got := p.Pending(); got != 3 {
+		t.Errorf("Pool.Pending() = %d, want 3", got)
+	}
+
+	if got := p.Workers(); got != 2 {
+		t.Errorf("Pool.Workers() = %d, want 2", got)
+	}
+
+	if got := p.Cap(); got != testDefaultNumTasks {
+		t.Errorf("Pool.Cap() = %d, want %d", got, testDefaultNumTasks)
+	}
+
+	if s := p.Stats(); s.Running != 2 {
+		t.Errorf("Pool.Stats().Running = %d, want 2", s.Running)
+	}
+
+	go func() {
+		for range started {
+		}
+	}()
+	close(release)
+
+	_ = p.Wait()
+	close(started)
+
+	if p.Running() != 0 || p.Pending() != 0 || p.Workers() != 0 {
+		t.Errorf("Pool.Running(), Pending(), Workers() = %d, %d, %d after Wait, want zeros", p.Running(), p.Pending(), p.Workers())
+	}
+}
+
+func TestPool_Purge(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+	}{
+		{name: "queue"},
+		{name: "shards", opts: []Option{WithShards(2)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := New(8, append([]Option{WithNumWorkers(1)}, tt.opts...)...)
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+
+			started, release := make(chan struct{}), make(chan struct{})
+			running, _ := p.SubmitFuture(func() error { close(started); <-release; return nil })
+			<-started
+
+			var futures []*Future
+			for i := 0; i < 4; i++ {
+				f, err := p.SubmitFuture(testNoOpFunc)
+				if err != nil {
+					t.Fatalf("SubmitFuture() error = %v", err)
+				}
+				futures = append(futures, f)
+			}
+			futures[0].Cancel()
+
+			if got := p.Purge(); got != 3 {
+				t.Errorf("Purge() = %d, want 3", got)
+			}
+
+			for _, f := range futures[1:] {
+				if err := f.Err(); !errors.Is(err, ErrTaskDiscarded) {
+					t.Errorf("Future.Err() of a purged task = %v, want %v", err, ErrTaskDiscarded)
+				}
+			}
+
+			if err := p.Submit(testNoOpFunc); err != nil {
+				t.Errorf("Submit() after Purge error = %v", err)
+			}
+
+			close(release)
+			if err := running.Err(); err != nil {
+				t.Errorf("Future.Err() of the running task = %v", err)
+			}
+
+			if err := p.Wait(); err != nil {
+				t.Fatalf("Wait() error = %v", err)
+			}
+
+			if s := p.Stats(); s.Discarded != 3 || s.Canceled != 1 || s.Succeeded != 2 {
+				t.Errorf("Stats() = %+v, want 3 discarded, 1 canceled and 2 succeeded", s)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package gowp
+
+import "fmt"
+
+// After returns a TaskOption that holds the task back until the tasks of the given Futures have succeeded.
+// Since a task can only depend on tasks submitted before it, dependencies always form a DAG.
+//
+// If any of the prerequisites fails, is cancelled or discarded, the task is not executed: its Future
+// reports ErrDependencyFailed wrapping the error of the prerequisite, and tasks depending on it fail in turn.
+// Such tasks are counted as discarded. A held task keeps the pool open, like the ones of SubmitAfter.
+// Nil Futures are ignored.
+func After(fs ...*Future) TaskOption {
+	return func(j *job) {
+		for _, f := range fs {
+			if f != nil {
+				j.after = append(j.after, f)
+			}
+		}
+	}
+}
+
+// await holds j until its prerequisites are done, then submits it or rejects it if any of them failed.
+func (p *Pool) await(t Task, f *Future, opts []TaskOption, j *job) error {
+	if f == nil {
+		f = newFuture() // the task is followed internally, so that it can be rejected.
+		f.pool = p
+		f.id = j.id
+	}
+
+	deps := j.after
+
+	// don't modify the backing array of the caller's options.
+	opts = append(opts[:len(opts):len(opts)], func(j *job) {
+		j.after = nil
+		j.delayed = true
+	})
+
+	return p.hold(f, j.delayed, func() (stop func()) {
+		stopped := make(chan struct{})
+
+		go func() {
+			var cause error
+			for _, dep := range deps {
+				select {
+				case <-dep.Done():
+				case <-stopped:
+					return
+				}
+
+				if err := dep.Err(); err != nil {
+					cause = fmt.Errorf("%w: %w", ErrDependencyFailed, err)
+					break
+				}
+			}
+
+			p.fire(t, f, opts, cause)
+		}()
+
+		return func() { close(stopped) }
+	})
+}
@@ -0,0 +1,103 @@
+package gowp
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestAfter(t *testing.T) {
+	p := testPool(context.Background(), 4, testDefaultNumTasks, false)
+
+	var (
+		mu    sync.Mutex
+		order []string
+	)
+	record := func(name string) Task {
+		return func() error {
+			mu.Lock()
+			order = append(order, name)
+			mu.Unlock()
+			return nil
+		}
+	}
+
+	a, _ := p.SubmitFuture(func() error {
+		time.Sleep(10 * time.Millisecond)
+		return record("a")()
+	})
+	b, _ := p.SubmitFuture(record("b"))
+	c, _ := p.SubmitFuture(record("c"), After(a, b))
+	d, _ := p.SubmitFuture(record("d"), After(c))
+
+	failed, _ := p.SubmitFuture(testFuncWithErr)
+	skipped, _ := p.SubmitFuture(record("skipped"), After(a, failed))
+	transitive, _ := p.SubmitFuture(record("transitive"), After(skipped))
+
+	if err := p.Wait(); !errors.Is(err, testErr) {
+		t.Errorf("Pool.Wait() = %v, want %v", err, testErr)
+	}
+
+	if err := d.Err(); err != nil {
+		t.Errorf("Future.Err() = %v, want nil", err)
+	}
+
+	if len(order) != 4 || order[2] != "c" || order[3] != "d" {
+		t.Errorf("execution order = %v, want a and b before c before d", order)
+	}
+
+	for _, f := range []*Future{skipped, transitive} {
+		if err := f.Err(); !errors.Is(err, ErrDependencyFailed) || !errors.Is(err, testErr) {
+			t.Errorf("Future.Err() = %v, want %v wrapping %v", err, ErrDependencyFailed, testErr)
+		}
+	}
+
+	if r := p.report(); r.Discarded != 2 {
+		t.Errorf("Report.Discarded = %d, want 2", r.Discarded)
+	}
+}
+
+func TestAfter_cancel(t *testing.T) {
+	p := testPool(context.Background(), 1, testDefaultNumTasks, false)
+
+	slow, _ := p.SubmitAfter(time.Hour, testNoOpFunc)
+	dep, _ := p.SubmitFuture(testNoOpFunc, After(slow))
+	held, _ := p.SubmitFuture(testNoOpFunc, After(slow))
+
+	if !held.Cancel() {
+		t.Error("Future.Cancel() = false while the dependency is pending")
+	}
+
+	slow.Cancel()
+
+	if err := p.Wait(); err != nil {
+		t.Errorf("Pool.Wait() = %v, want nil", err)
+	}
+
+	if err := dep.Err(); !errors.Is(err, ErrDependencyFailed) || !errors.Is(err, ErrTaskCanceled) {
+		t.Errorf("Future.Err() = %v, want %v wrapping %v", err, ErrDependencyFailed, ErrTaskCanceled)
+	}
+
+	if err := held.Err(); !errors.Is(err, ErrTaskCanceled) {
+		t.Errorf("Future.Err() = %v, want %v", err, ErrTaskCanceled)
+	}
+}
+
+func TestAfter_stop(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	p := testPool(ctx, 1, testDefaultNumTasks, false)
+
+	slow, _ := p.SubmitAfter(time.Hour, testNoOpFunc)
+	dep, _ := p.SubmitFuture(testNoOpFunc, After(slow))
+	cancel()
+
+	if err := p.Wait(); !errors.Is(err, context.Canceled) {
+		t.Errorf("Pool.Wait() = %v, want %v", err, context.Canceled)
+	}
+
+	if err := dep.Err(); !errors.Is(err, ErrTaskDiscarded) {
+		t.Errorf("Future.Err() = %v, want %v", err, ErrTaskDiscarded)
+	}
+}
@@ -0,0 +1,29 @@
+package gowp
+
+import (
+	"fmt"
+	"time"
+)
+
+// TaskDeadline returns a TaskOption that sets the time by which the task should start. A task still queued
+// once its deadline has passed is not executed, its Future reports ErrDeadlineExceeded, it is counted as
+// expired, see Stats, and EventTaskExpired is emitted. A task that has started is not interrupted. Along with
+// the EarliestDeadline scheduling policy, tasks are run in the order of their deadlines.
+func TaskDeadline(deadline time.Time) TaskOption {
+	return func(j *job) {
+		j.deadline = deadline
+	}
+}
+
+// SubmitWithDeadline submits t like SubmitFuture, with the given deadline, see TaskDeadline.
+func (p *Pool) SubmitWithDeadline(deadline time.Time, t Task, opts ...TaskOption) (*Future, error) {
+	// don't modify the backing array of the caller's options.
+	opts = append(opts[:len(opts):len(opts)], TaskDeadline(deadline))
+
+	f := newFuture()
+	if err := p.submit(t, f, opts); err != nil {
+		return nil, fmt.Errorf("gowp.Pool.SubmitWithDeadline(): %w", err)
+	}
+
+	return f, nil
+}
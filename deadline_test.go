@@ -0,0 +1,117 @@
+package gowp
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestPool_SubmitWithDeadline(t *testing.T) {
+	c := &testClock{now: time.Now()}
+	start := c.Now()
+
+	var (
+		mu  sync.Mutex
+		ran []string
+	)
+	task := func(name string) Task {
+		return func() error {
+			mu.Lock()
+			ran = append(ran, name)
+			mu.Unlock()
+			return nil
+		}
+	}
+
+	var expired []Event
+	p, err := New(10, WithNumWorkers(1), WithClock(c), WithSchedulingPolicy(EarliestDeadline),
+		WithEventSink(func(e Event) {
+			if e.Kind == EventTaskExpired {
+				expired = append(expired, e) // the only worker emits it.
+			}
+		}))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	// hold the only worker, so that the tasks are queued before any of them is picked.
+	started, release := make(chan struct{}), make(chan struct{})
+	_ = p.Submit(func() error { close(started); <-release; return nil })
+	<-started
+
+	tests := []struct {
+		name     string
+		deadline time.Time
+		wantErr  error
+	}{
+		{name: "none", deadline: time.Time{}},
+		{name: "late", deadline: start.Add(3 * time.Minute)},
+		{name: "expired", deadline: start.Add(time.Second), wantErr: ErrDeadlineExceeded},
+		{name: "early", deadline: start.Add(2 * time.Minute)},
+	}
+
+	futures := make([]*Future, len(tests))
+	for i, tt := range tests {
+		f, err := p.SubmitWithDeadline(tt.deadline, task(tt.name), TaskName(tt.name))
+		if err != nil {
+			t.Fatalf("SubmitWithDeadline() error = %v", err)
+		}
+		futures[i] = f
+	}
+
+	c.advance(time.Minute)
+	close(release)
+
+	for i, tt := range tests {
+		if err := futures[i].Err(); !errors.Is(err, tt.wantErr) {
+			t.Errorf("task %s error = %v, want %v", tt.name, err, tt.wantErr)
+		}
+	}
+
+	if err := p.Wait(); err != nil {
+		t.Fatalf("Wait() error = %v", err)
+	}
+
+	if want := []string{"early", "late", "none"}; !reflect.DeepEqual(ran, want) {
+		t.Errorf("execution order = %v, want %v", ran, want)
+	}
+
+	if s := p.Stats(); s.Expired != 1 {
+		t.Errorf("Stats().Expired = %d, want 1", s.Expired)
+	}
+
+	if done, total := p.Progress(); done != total {
+		t.Errorf("Progress() = %d, %d, want all the tasks done", done, total)
+	}
+
+	if len(expired) != 1 || expired[0].Task.Name != "expired" {
+		t.Errorf("expired events = %v, want one for the expired task", expired)
+	}
+}
+
+func TestEarlierDeadline(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name string
+		a, b *job
+		want bool
+	}{
+		{name: "earlier", a: &job{id: 2, deadline: now}, b: &job{id: 1, deadline: now.Add(time.Second)}, want: true},
+		{name: "later", a: &job{id: 1, deadline: now.Add(time.Second)}, b: &job{id: 2, deadline: now}, want: false},
+		{name: "same deadline", a: &job{id: 1, deadline: now}, b: &job{id: 2, deadline: now}, want: true},
+		{name: "no deadline last", a: &job{id: 1}, b: &job{id: 2, deadline: now}, want: false},
+		{name: "deadline before none", a: &job{id: 2, deadline: now}, b: &job{id: 1}, want: true},
+		{name: "none in submission order", a: &job{id: 1}, b: &job{id: 2}, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := earlierDeadline(tt.a, tt.b); got != tt.want {
+				t.Errorf("earlierDeadline() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
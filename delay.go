@@ -0,0 +1,172 @@
+package gowp
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// SubmitAfter submits t to the pool once d has elapsed and returns a Future to follow it.
+// Cancelling the Future before the delay has elapsed prevents the submission.
+//
+// A pending submission keeps the pool open: Wait waits for it to be queued and executed.
+// If the pool stops before, e.g. because its context is cancelled, the task is discarded.
+// Errors that would be returned by SubmitFuture at that time, e.g. ErrInvalidWeight, are reported by the Future.
+func (p *Pool) SubmitAfter(d time.Duration, t Task, opts ...TaskOption) (*Future, error) {
+	f, err := p.schedule(d, t, opts)
+	if err != nil {
+		return nil, fmt.Errorf("gowp.Pool.SubmitAfter(): %w", err)
+	}
+
+	return f, nil
+}
+
+// SubmitAt submits t to the pool at the given time, see SubmitAfter.
+func (p *Pool) SubmitAt(at time.Time, t Task, opts ...TaskOption) (*Future, error) {
+	f, err := p.schedule(at.Sub(p.clock.Now()), t, opts)
+	if err != nil {
+		return nil, fmt.Errorf("gowp.Pool.SubmitAt(): %w", err)
+	}
+
+	return f, nil
+}
+
+func (p *Pool) schedule(d time.Duration, t Task, opts []TaskOption) (*Future, error) {
+	if t == nil {
+		return nil, ErrNilTask
+	}
+
+	f := newFuture()
+	f.pool = p
+	f.id = p.nextID()
+
+	// don't modify the backing array of the caller's options.
+	opts = append(opts[:len(opts):len(opts)], func(j *job) { j.delayed = true })
+
+	err := p.hold(f, false, func() (stop func()) {
+		tm := p.clock.AfterFunc(d, func() { p.fire(t, f, opts, nil) })
+		return func() { tm.Stop() }
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+// hold registers a task that will be submitted later by fire, e.g. once its delay has elapsed.
+// arm starts what eventually calls fire and returns a function that prevents that call.
+// A held task keeps the pool open. admitted is true if the task was accepted while the intake was open.
+func (p *Pool) hold(f *Future, admitted bool, arm func() (stop func())) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.holdLocked(f, admitted, arm)
+}
+
+// holdLocked is hold for callers that hold p.mu already.
+func (p *Pool) holdLocked(f *Future, admitted bool, arm func() (stop func())) error {
+	if p.intakeOff && !admitted {
+		return ErrPoolClosed
+	}
+
+	select {
+	case <-p.quit:
+		return ErrPoolClosed
+	default:
+	}
+
+	if p.held == nil {
+		p.held = make(map[*Future]func())
+	}
+
+	p.held[f] = arm()
+	p.scheduled++
+
+	if p.lazy {
+		p.grow()
+	}
+
+	return nil
+}
+
+// fire submits a held task whose time has come. If cause is not nil, the task is rejected with it instead.
+func (p *Pool) fire(t Task, f *Future, opts []TaskOption, cause error) {
+	p.mu.Lock()
+	if _, ok := p.held[f]; !ok {
+		p.mu.Unlock()
+		return // stopped in the meantime, but too late to prevent the call.
+	}
+
+	delete(p.held, f)
+	p.mu.Unlock()
+
+	// the held task still counts as pending, so workers stay around while it is being queued.
+	err := cause
+	if err == nil {
+		err = p.submitJob(t, f, opts, true)
+	}
+
+	if err != nil {
+		if f.reject(err) {
+			atomic.AddInt64(&p.counts.discarded, 1)
+		} else if cause == nil {
+			atomic.AddInt64(&p.counts.tombstones, -1) // cancelled after it was released, it never made it to the queue.
+		}
+	}
+
+	p.mu.Lock()
+	p.scheduled--
+	p.ready.Broadcast() // workers may be waiting for the held task before retiring.
+	p.mu.Unlock()
+}
+
+// unhold forgets a held task that is complete, without discarding it.
+// It returns false if the task was not held, e.g. because it has been cancelled.
+func (p *Pool) unhold(f *Future) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if _, ok := p.held[f]; !ok {
+		return false
+	}
+
+	delete(p.held, f)
+	p.scheduled--
+	p.ready.Broadcast()
+
+	return true
+}
+
+// unschedule prevents the submission of a held task that is cancelled.
+// It returns false if the task was not held, or already released.
+func (p *Pool) unschedule(f *Future) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	stop, ok := p.held[f]
+	if !ok {
+		return false
+	}
+
+	stop()
+	delete(p.held, f)
+	p.scheduled--
+	p.ready.Broadcast()
+	atomic.AddInt64(&p.counts.canceled, 1)
+
+	return true
+}
+
+// releaseHeld discards the held tasks. p.mu must be held.
+func (p *Pool) releaseHeld() {
+	for f, stop := range p.held {
+		stop()
+		delete(p.held, f)
+		p.scheduled--
+
+		if f.discard() {
+			atomic.AddInt64(&p.counts.discarded, 1)
+		}
+	}
+}
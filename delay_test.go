@@ -0,0 +1,66 @@
+package gowp
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestPool_SubmitAfter(t *testing.T) {
+	p := testPool(context.Background(), 1, testDefaultNumTasks, false)
+
+	start := time.Now()
+	var ranAt time.Time
+	f, err := p.SubmitAfter(10*time.Millisecond, func() error {
+		ranAt = time.Now()
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Pool.SubmitAfter() error = %v", err)
+	}
+
+	at, _ := p.SubmitAt(start.Add(time.Millisecond), testFuncWithErr)
+
+	canceled, _ := p.SubmitAfter(time.Hour, testNoOpFunc)
+	if !canceled.Cancel() {
+		t.Error("Future.Cancel() = false before the delay elapsed")
+	}
+
+	// Wait waits for the delayed tasks.
+	if err := p.Wait(); !errors.Is(err, testErr) {
+		t.Errorf("Pool.Wait() = %v, want %v", err, testErr)
+	}
+
+	if err := f.Err(); err != nil || ranAt.Sub(start) < 10*time.Millisecond {
+		t.Errorf("delayed task ran after %v with error %v, want after 10ms without error", ranAt.Sub(start), err)
+	}
+
+	if err := at.Err(); !errors.Is(err, testErr) {
+		t.Errorf("Future.Err() = %v, want %v", err, testErr)
+	}
+
+	if err := canceled.Err(); !errors.Is(err, ErrTaskCanceled) {
+		t.Errorf("Future.Err() = %v, want %v", err, ErrTaskCanceled)
+	}
+
+	if _, err := p.SubmitAfter(time.Millisecond, testNoOpFunc); !errors.Is(err, ErrPoolClosed) {
+		t.Errorf("Pool.SubmitAfter() after Wait = %v, want %v", err, ErrPoolClosed)
+	}
+}
+
+func TestPool_SubmitAfter_stop(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	p := testPool(ctx, 1, testDefaultNumTasks, false)
+
+	f, _ := p.SubmitAfter(time.Hour, testNoOpFunc)
+	cancel()
+
+	if err := p.Wait(); !errors.Is(err, context.Canceled) {
+		t.Errorf("Pool.Wait() = %v, want %v", err, context.Canceled)
+	}
+
+	if err := f.Err(); !errors.Is(err, ErrTaskDiscarded) {
+		t.Errorf("Future.Err() = %v, want %v", err, ErrTaskDiscarded)
+	}
+}
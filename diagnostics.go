@@ -0,0 +1,46 @@
+package gowp
+
+import (
+	"context"
+	"runtime/pprof"
+	"runtime/trace"
+)
+
+// traceRegion is the type of the runtime/trace region that wraps the execution of a task.
+const traceRegion = "gowp.task"
+
+// WithTracePropagation returns an Option that carries goroutine-local diagnostics over to the workers.
+// Before executing a task, the worker applies the pprof labels of the task's context, see TaskContext,
+// and executes the task within a runtime/trace region, so that the task is attributed to the
+// trace.Task of the context. Tasks without a context use the context of the pool.
+// Once the task is done, the worker goes back to the labels of the pool's context.
+func WithTracePropagation() Option {
+	return func(o *config) {
+		o.propagate = true
+	}
+}
+
+// TaskContext returns a TaskOption that associates ctx with the task, typically the context of the
+// submitting goroutine. See WithTracePropagation.
+func TaskContext(ctx context.Context) TaskOption {
+	return func(j *job) {
+		j.ctx = ctx
+	}
+}
+
+// runTraced runs the job with the diagnostics of its context applied to the current goroutine.
+func (p *Pool) runTraced(j *job) (err error) {
+	ctx := j.ctx
+	if ctx == nil {
+		ctx = p.ctx
+	}
+
+	pprof.SetGoroutineLabels(ctx)
+	defer pprof.SetGoroutineLabels(p.ctx)
+
+	trace.WithRegion(ctx, traceRegion, func() {
+		err = j.run(p.recovers, p.clock)
+	})
+
+	return err
+}
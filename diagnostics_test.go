@@ -0,0 +1,47 @@
+package gowp
+
+import (
+	"bytes"
+	"context"
+	"runtime/pprof"
+	"strings"
+	"testing"
+)
+
+func TestWithTracePropagation(t *testing.T) {
+	poolCtx := pprof.WithLabels(context.Background(), pprof.Labels("pool", "test"))
+	p := testPool(poolCtx, 1, testDefaultNumTasks, false, WithTracePropagation())
+
+	// goroutine labels can't be read directly, but the goroutine profile reports them.
+	profile := func() string {
+		var buf bytes.Buffer
+		_ = pprof.Lookup("goroutine").WriteTo(&buf, 1)
+		return buf.String()
+	}
+
+	taskCtx := pprof.WithLabels(context.Background(), pprof.Labels("request", "42"))
+
+	var during string
+	f, _ := p.SubmitFuture(func() error {
+		during = profile()
+		return nil
+	}, TaskContext(taskCtx))
+
+	_ = f.Err()
+
+	if !strings.Contains(during, `"request":"42"`) {
+		t.Error("task labels are not applied to the worker")
+	}
+
+	after := make(chan string)
+	_ = p.Submit(func() error {
+		after <- profile()
+		return nil
+	})
+
+	if s := <-after; !strings.Contains(s, `"pool":"test"`) {
+		t.Error("task without a context doesn't run with the labels of the pool")
+	}
+
+	_ = p.Wait()
+}
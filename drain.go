@@ -0,0 +1,57 @@
+package gowp
+
+import (
+	"os"
+	"os/signal"
+	"time"
+)
+
+// DrainOnSignal shuts p down gracefully once sig is received, e.g. syscall.SIGTERM sent by an orchestrator.
+// The pool stops accepting tasks and gets timeout to execute the queued ones. Then it is stopped,
+// the tasks that are still queued are discarded and Wait reports ErrDrainTimeout, unless a task failed before.
+// Running tasks are not interrupted, pass them a context to cut them short.
+//
+// The returned channel receives the error returned by Wait once the pool has completed, whether it was drained
+// or completed on its own, in which case sig is not watched anymore.
+//
+//	wp, _ := gowp.New(100)
+//	done := gowp.DrainOnSignal(wp, syscall.SIGTERM, 30*time.Second)
+//	// submit tasks...
+//	err := <-done
+func DrainOnSignal(p *Pool, sig os.Signal, timeout time.Duration) <-chan error {
+	out := make(chan error, 1)
+
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, sig)
+
+	go func() {
+		defer signal.Stop(c)
+
+		select {
+		case <-c:
+		case <-p.done:
+			out <- p.Wait()
+			return
+		}
+
+		p.Close()
+
+		t := p.clock.AfterFunc(timeout, func() { p.abort(ErrDrainTimeout) })
+		defer t.Stop()
+
+		out <- p.Wait()
+	}()
+
+	return out
+}
+
+// abort stops the pool with err, as if a task failed with it. It has no effect once the pool has stopped.
+func (p *Pool) abort(err error) {
+	p.watch()
+
+	select {
+	case p.aborts <- err:
+	default:
+		// an abort is pending already.
+	}
+}
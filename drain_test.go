@@ -0,0 +1,65 @@
+//go:build unix
+
+package gowp
+
+import (
+	"context"
+	"errors"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestDrainOnSignal(t *testing.T) {
+	tests := []struct {
+		name          string
+		timeout       time.Duration
+		wantErr       error
+		wantDiscarded int
+	}{
+		{name: "drained", timeout: time.Second},
+		{name: "timed out", timeout: 5 * time.Millisecond, wantErr: ErrDrainTimeout, wantDiscarded: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := testPool(context.Background(), 1, testDefaultNumTasks, false)
+			done := DrainOnSignal(p, syscall.SIGUSR1, tt.timeout)
+
+			for i := 0; i < 3; i++ {
+				_ = p.Submit(func() error {
+					time.Sleep(20 * time.Millisecond)
+					return nil
+				})
+			}
+
+			if err := syscall.Kill(syscall.Getpid(), syscall.SIGUSR1); err != nil {
+				t.Fatalf("syscall.Kill() error = %v", err)
+			}
+
+			if err := <-done; !errors.Is(err, tt.wantErr) {
+				t.Errorf("DrainOnSignal() = %v, want %v", err, tt.wantErr)
+			}
+
+			if r := p.report(); r.Discarded != tt.wantDiscarded {
+				t.Errorf("Report.Discarded = %d, want %d", r.Discarded, tt.wantDiscarded)
+			}
+
+			if err := p.Submit(testNoOpFunc); !errors.Is(err, ErrPoolClosed) {
+				t.Errorf("Pool.Submit() after drain = %v, want %v", err, ErrPoolClosed)
+			}
+		})
+	}
+}
+
+func TestDrainOnSignal_completed(t *testing.T) {
+	p := testPool(context.Background(), 1, testDefaultNumTasks, false)
+	done := DrainOnSignal(p, syscall.SIGUSR1, time.Second)
+
+	_ = p.Submit(testFuncWithErr)
+	_ = p.Wait()
+
+	if err := <-done; !errors.Is(err, testErr) {
+		t.Errorf("DrainOnSignal() = %v, want %v", err, testErr)
+	}
+}
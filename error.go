@@ -8,6 +8,40 @@ const (
 	ErrNoBuffer    = Error("insufficient buffer")
 	ErrInvalidSend = Error("work sent on closed pool")
 	ErrNilTask     = Error("task is nil")
+
+	ErrTaskDiscarded = Error("task discarded before execution")
+	ErrUnknownLabel  = Error("task label has no sub-queue")
+	ErrTaskCanceled  = Error("task canceled before execution")
+	ErrInvalidWeight = Error("task weight should be greater than zero and within the capacity")
+	ErrTenantQuota   = Error("tenant has reached its queue quota")
+
+	ErrDependencyFailed = Error("task dependency failed")
+	ErrCircuitOpen      = Error("circuit breaker is open")
+	ErrNotAcked         = Error("task was not acknowledged")
+	ErrDeadlineExceeded = Error("task deadline passed before execution")
+	ErrNotRequeueable   = Error("task cannot be requeued")
+
+	ErrRetryBudgetExhausted = Error("retry budget exhausted")
+)
+
+// health errors, see Pool.Healthy.
+const (
+	ErrPoolStopped = Error("pool stopped before completing its tasks")
+	ErrSaturated   = Error("pool queue is saturated")
+	ErrWorkersLost = Error("pool has fewer workers than configured")
+)
+
+// ErrDrainTimeout is reported by Wait if the pool was stopped because it didn't drain in time, see DrainOnSignal.
+const ErrDrainTimeout = Error("pool did not drain in time")
+
+// ErrWaitAbandoned is returned by WaitContext if its context is done before the pool has completed.
+const ErrWaitAbandoned = Error("wait abandoned before the pool completed")
+
+// registry errors, see Register.
+const (
+	ErrInvalidName = Error("pool name should not be empty")
+	ErrNameTaken   = Error("pool name is already registered")
+	ErrNilPool     = Error("pool is nil")
 )
 
 // validation errors
@@ -15,6 +49,48 @@ const (
 	ErrInvalidBuffer    = Error("buffer value should be greater than zero")
 	ErrInvalidWorkerCnt = Error("worker count should be greater than zero")
 	ErrNilContext       = Error("context is nil")
+	ErrNilClock         = Error("clock is nil")
+	ErrInvalidWeights   = Error("dispatch weights should be greater than zero")
+	ErrInvalidBoost     = Error("boost threshold should be greater than zero and ceiling at least the worker count")
+	ErrInvalidPolicy    = Error("unknown scheduling policy")
+	ErrInvalidMaxErrors = Error("max errors should not be negative")
+	ErrInvalidBurst     = Error("burst refill should be greater than zero and ceiling at least the worker count")
+	ErrInvalidAdaptive  = Error("adaptive minimum should be within the worker count and tolerance greater than one")
+	ErrInvalidCapacity  = Error("capacity should be greater than zero")
+	ErrInvalidQuota     = Error("tenant quotas should not be negative")
+	ErrInvalidWindow    = Error("coalesce window should not be negative")
+	ErrInvalidBreaker   = Error("circuit breaker threshold and cooldown should be greater than zero")
+	ErrInvalidShards    = Error("shard count should not be negative")
+	ErrInvalidPrestart  = Error("prestart count should be within the worker count")
+	ErrInvalidProfile   = Error("unknown workload profile")
+
+	ErrInvalidSaturation = Error("saturation threshold should be within (0, 1] and window greater than zero")
+	ErrInvalidStall      = Error("stall timeout should be greater than zero and report not nil")
+	ErrInvalidRedelivery = Error("redelivery timeout and attempts should not be negative")
+	ErrInvalidAging      = Error("aging threshold should not be negative")
+
+	ErrInvalidRetryBudget = Error("retry budget rate and ratio should not be negative")
+	ErrInvalidCache       = Error("cache should not be nil and its ttl should be greater than zero")
+	ErrInvalidClassLimit  = Error("class limits should be greater than zero")
+
+	ErrInvalidGCLimit        = Error("GC limit minimum should be within the worker count and threshold within (0, 1)")
+	ErrInvalidMemoryThrottle = Error("memory threshold and interval should be greater than zero, low threshold within the high one")
+
+	ErrInvalidAutoscaler = Error("autoscaler target should be greater than zero, percentile within (0, 1] and the worker count within its bounds")
+
+	ErrInvalidSlowTaskLog = Error("slow task threshold should be greater than zero and sample rate within (0, 1]")
+)
+
+// Severity tells the pool how to react to an error returned by a task, see WithErrorClassifier.
+type Severity int
+
+const (
+	// SeverityError is the default, the error is handled as per WithExitOnError and WithMaxErrors.
+	SeverityError Severity = iota
+	// SeverityIgnore drops the error, it is neither reported by Wait nor counted as a failure.
+	SeverityIgnore
+	// SeverityFatal closes the pool, even if WithExitOnError is not set. Wait reports the fatal error.
+	SeverityFatal
 )
 
 // interface guard to ensure Error implements error interface
@@ -0,0 +1,100 @@
+package gowp
+
+import (
+	"strconv"
+	"time"
+)
+
+// EventKind tells what an Event is about.
+type EventKind int
+
+// kinds of events, see WithEventSink.
+const (
+	// EventCreated is emitted once the pool is initialized, from New. Workers is the number of workers.
+	EventCreated EventKind = iota + 1
+	// EventPaused is emitted by Pause.
+	EventPaused
+	// EventResumed is emitted by Resume.
+	EventResumed
+	// EventResized is emitted by Resize and WithLatencyAutoscaler, Workers is the new number of workers.
+	EventResized
+	// EventTaskRejected is emitted when a submission fails, Err tells why, e.g. ErrNoBuffer.
+	EventTaskRejected
+	// EventTaskFailed is emitted when a task returns an error, Err is the TaskError.
+	EventTaskFailed
+	// EventWorkerExited is emitted when a worker exits, Err is the PanicError if it is replaced after a panic.
+	EventWorkerExited
+	// EventClosed is emitted once the pool stops accepting tasks, see Close.
+	EventClosed
+	// EventStopped is emitted when the pool stops early, Err is the error it stops with, if any.
+	EventStopped
+	// EventCompleted is emitted once the pool has completed, Err is the error Wait reports.
+	EventCompleted
+	// EventTaskExpired is emitted when a task is dropped because its deadline passed, see TaskDeadline.
+	EventTaskExpired
+)
+
+var eventKinds = [...]string{
+	EventCreated:      "created",
+	EventPaused:       "paused",
+	EventResumed:      "resumed",
+	EventResized:      "resized",
+	EventTaskRejected: "task rejected",
+	EventTaskFailed:   "task failed",
+	EventWorkerExited: "worker exited",
+	EventClosed:       "closed",
+	EventStopped:      "stopped",
+	EventCompleted:    "completed",
+	EventTaskExpired:  "task expired",
+}
+
+func (k EventKind) String() string {
+	if k > 0 && int(k) < len(eventKinds) {
+		return eventKinds[k]
+	}
+
+	return "EventKind(" + strconv.Itoa(int(k)) + ")"
+}
+
+// Event is a structured record of something that happened to a pool, see WithEventSink.
+type Event struct {
+	Kind    EventKind
+	Time    time.Time // as told by the clock of the pool.
+	Task    TaskInfo  // the task the event is about, zero for events about the pool or its workers.
+	Workers int       // the number of workers, for EventCreated and EventResized.
+	Err     error     // see the kinds of events.
+}
+
+// WithEventSink returns an Option that emits the events of the pool to sink, e.g. to feed a dashboard or
+// an audit log from a single place. The option can be repeated, a nil sink is ignored.
+//
+// Like hooks, sinks are called synchronously from the goroutine the event happens on, so they should be fast
+// and safe for concurrent use. A panicking sink is recovered, see WithHookErrorHandler. To consume events
+// from a channel, send them from the sink without blocking:
+//
+//	events := make(chan gowp.Event, 64)
+//	wp, _ := gowp.New(100, gowp.WithEventSink(func(e gowp.Event) {
+//		select {
+//		case events <- e:
+//		default: // dropped, the consumer is late.
+//		}
+//	}))
+func WithEventSink(sink func(Event)) Option {
+	return func(o *config) {
+		if sink != nil {
+			o.sinks = append(o.sinks, sink)
+		}
+	}
+}
+
+// emit sends e to the sinks of the pool. p.mu must not be held.
+func (p *Pool) emit(e Event) {
+	if len(p.sinks) == 0 {
+		return
+	}
+
+	e.Time = p.clock.Now()
+	for _, sink := range p.sinks {
+		p.guard("EventSink", e.Task, func() { sink(e) })
+	}
+}
@@ -0,0 +1,108 @@
+package gowp
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+)
+
+func TestWithEventSink(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		events []Event
+	)
+	sink := func(e Event) {
+		mu.Lock()
+		events = append(events, e)
+		mu.Unlock()
+	}
+
+	p := testPool(context.Background(), 1, 1, false, WithEventSink(sink), WithEventSink(nil), WithPanicRecovery())
+
+	p.Pause()
+	failed, _ := p.SubmitFuture(testFuncWithErr)
+	_ = p.Submit(testNoOpFunc) // rejected, the queue is full.
+	p.Resume()
+	_ = failed.Err()
+
+	if err := p.Resize(2); err != nil {
+		t.Fatalf("Pool.Resize() error = %v", err)
+	}
+
+	f, _ := p.SubmitFuture(func() error { panic("boom") })
+	_ = f.Err()
+
+	err := p.Wait()
+
+	count := map[EventKind]int{}
+	for _, e := range events {
+		count[e.Kind]++
+
+		if e.Time.IsZero() {
+			t.Errorf("Event %v has no time", e.Kind)
+		}
+
+		switch e.Kind {
+		case EventCreated:
+			if e.Workers != 1 {
+				t.Errorf("Event %v has %d workers, want 1", e.Kind, e.Workers)
+			}
+		case EventResized:
+			if e.Workers != 2 {
+				t.Errorf("Event %v has %d workers, want 2", e.Kind, e.Workers)
+			}
+		case EventTaskRejected:
+			if !errors.Is(e.Err, ErrNoBuffer) || e.Task.ID == 0 {
+				t.Errorf("Event %v = %+v, want %v for a task", e.Kind, e, ErrNoBuffer)
+			}
+		case EventTaskFailed:
+			var te *TaskError
+			if !errors.As(e.Err, &te) || te.ID != e.Task.ID {
+				t.Errorf("Event %v = %+v, want the TaskError of the task", e.Kind, e)
+			}
+		case EventCompleted:
+			if !errors.Is(e.Err, errors.Unwrap(err)) {
+				t.Errorf("Event %v error = %v, want %v", e.Kind, e.Err, err)
+			}
+		}
+	}
+
+	want := map[EventKind]int{
+		EventCreated:      1,
+		EventPaused:       1,
+		EventResumed:      1,
+		EventResized:      1,
+		EventTaskRejected: 1,
+		EventTaskFailed:   2, // the error and the panic.
+		EventWorkerExited: 3, // the one replaced after the panic, then both workers.
+		EventClosed:       1,
+		EventCompleted:    1,
+	}
+	for kind, n := range want {
+		if count[kind] != n {
+			t.Errorf("%d %v events, want %d: %v", count[kind], kind, n, events)
+		}
+	}
+
+	if events[0].Kind != EventCreated || events[len(events)-1].Kind != EventCompleted {
+		t.Errorf("events = %v, want created first and completed last", events)
+	}
+}
+
+func TestEventKind_String(t *testing.T) {
+	tests := []struct {
+		kind EventKind
+		want string
+	}{
+		{kind: EventTaskFailed, want: "task failed"},
+		{kind: EventCompleted, want: "completed"},
+		{kind: 0, want: "EventKind(0)"},
+		{kind: 42, want: "EventKind(42)"},
+	}
+	for _, tt := range tests {
+		if got := tt.kind.String(); got != tt.want {
+			t.Errorf("EventKind.String() = %q, want %q", got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package gowp
+
+import "sync/atomic"
+
+// states of a task tracked by its Future.
+const (
+	taskQueued uint32 = iota
+	taskRunning
+	taskCanceled
+	taskDiscarded
+)
+
+type (
+	// Future is a handle to a single task submitted to the pool.
+	// It lets callers react to that task finishing instead of waiting for the whole pool.
+	Future struct {
+		done chan struct{} // closed once the task has finished or has been discarded.
+		err  error         // the error returned by the task. Safe to read after done is closed.
+
+		timing Timing // see Timing. Safe to read after done is closed.
+
+		state uint32 // one of the task states. Should be manipulated by sync/atomic.
+		pool  *Pool  // the pool the task was submitted to, it is notified about cancellation.
+		id    uint64 // see ID. Read-only once the Future is handed out.
+
+		requeueable bool // set for the tasks submitted with SubmitFuture, see Requeue. Read-only once the Future is handed out.
+
+		job atomic.Pointer[job] // the last job submitted for the task, nil while it is held, see SetPriority.
+	}
+
+	// TypedFuture is a Future for a task that produces a value of type T.
+	TypedFuture[T any] struct {
+		*Future
+		val T
+	}
+)
+
+func newFuture() *Future {
+	return &Future{done: make(chan struct{})}
+}
+
+// ID returns the identifier of the task, unique within the pool and increasing in the order of submission.
+// It is the ID reported to hooks, see TaskInfo, and along with the errors of the task, see TaskError.
+func (f *Future) ID() uint64 {
+	return f.id
+}
+
+// Done returns a channel that is closed when the task has finished.
+// It is also closed if the pool discards the task without running it.
+func (f *Future) Done() <-chan struct{} {
+	return f.done
+}
+
+// Err blocks until the task has finished and returns the error reported by it.
+// If the pool discarded the task before running it, ErrTaskDiscarded is returned.
+// If the task was cancelled, ErrTaskCanceled is returned.
+func (f *Future) Err() error {
+	<-f.done
+	return f.err
+}
+
+// Cancel retracts the task from the pool if it has not started yet.
+// It reports whether the task was cancelled, a cancelled task is never executed.
+func (f *Future) Cancel() bool {
+	if !atomic.CompareAndSwapUint32(&f.state, taskQueued, taskCanceled) {
+		return false
+	}
+
+	f.complete(ErrTaskCanceled)
+
+	if !f.pool.unschedule(f) {
+		f.pool.noteCanceled()
+	}
+
+	return true
+}
+
+func (f *Future) canceled() bool {
+	return atomic.LoadUint32(&f.state) == taskCanceled
+}
+
+// start marks the task as running. It returns false if the task must not be executed.
+func (f *Future) start() bool {
+	return atomic.CompareAndSwapUint32(&f.state, taskQueued, taskRunning)
+}
+
+// reject completes a delayed task that the pool refused. It returns false if the task was cancelled.
+func (f *Future) reject(err error) bool {
+	if !atomic.CompareAndSwapUint32(&f.state, taskQueued, taskDiscarded) {
+		return false
+	}
+
+	f.complete(err)
+
+	return true
+}
+
+// discard completes a task that will never be executed. It returns false if the task was cancelled.
+func (f *Future) discard() bool {
+	if !atomic.CompareAndSwapUint32(&f.state, taskQueued, taskDiscarded) {
+		return false
+	}
+
+	f.complete(ErrTaskDiscarded)
+
+	return true
+}
+
+func (f *Future) complete(err error) {
+	f.err = err
+	close(f.done)
+}
+
+// Result blocks until the task has finished and returns the value and the error reported by it.
+func (f *TypedFuture[T]) Result() (T, error) {
+	err := f.Err()
+	return f.val, err
+}
@@ -0,0 +1,289 @@
+package gowp
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestPool_SubmitFuture(t *testing.T) {
+	p := testPool(context.Background(), testDefaultNumWorkers, testDefaultNumTasks, false)
+
+	ok, err := p.SubmitFuture(testNoOpFunc)
+	if err != nil {
+		t.Fatalf("Pool.SubmitFuture() error = %v", err)
+	}
+
+	failed, err := p.SubmitFuture(testFuncWithErr)
+	if err != nil {
+		t.Fatalf("Pool.SubmitFuture() error = %v", err)
+	}
+
+	<-ok.Done()
+	if err := ok.Err(); err != nil {
+		t.Errorf("Future.Err() = %v, want nil", err)
+	}
+
+	if err := failed.Err(); !errors.Is(err, testErr) {
+		t.Errorf("Future.Err() = %v, want %v", err, testErr)
+	}
+
+	_ = p.Wait()
+
+	if _, err := p.SubmitFuture(nil); !errors.Is(err, ErrNilTask) {
+		t.Errorf("Pool.SubmitFuture() = %v, want %v", err, ErrNilTask)
+	}
+}
+
+func TestPool_SubmitFuture_discarded(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	p := testPool(ctx, testDefaultNumWorkers, testDefaultNumTasks, true)
+	<-p.quit // wait for the pool to observe the cancellation.
+
+	f, err := p.SubmitFuture(testNoOpFunc)
+	if err != nil {
+		t.Fatalf("Pool.SubmitFuture() error = %v", err)
+	}
+
+	_ = p.Wait()
+
+	if err := f.Err(); !errors.Is(err, ErrTaskDiscarded) {
+		t.Errorf("Future.Err() = %v, want %v", err, ErrTaskDiscarded)
+	}
+}
+
+func TestFuture_ID(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		infos = map[uint64]bool{}
+	)
+	hooks := Hooks{OnStart: func(info TaskInfo) {
+		mu.Lock()
+		infos[info.ID] = true
+		mu.Unlock()
+	}}
+
+	p := testPool(context.Background(), 1, testDefaultNumTasks, false, WithHooks(hooks))
+
+	submit := []func() (*Future, error){
+		func() (*Future, error) { return p.SubmitFuture(testNoOpFunc) },
+		func() (*Future, error) { return p.SubmitAfter(time.Millisecond, testNoOpFunc) },
+		func() (*Future, error) { return p.SubmitFuture(testFuncWithErr) },
+	}
+
+	var futs []*Future
+	for i, fn := range submit {
+		f, err := fn()
+		if err != nil {
+			t.Fatalf("submission %d error = %v", i, err)
+		}
+
+		if i > 0 && f.ID() <= futs[i-1].ID() {
+			t.Errorf("Future.ID() = %d after %d, want increasing IDs", f.ID(), futs[i-1].ID())
+		}
+
+		futs = append(futs, f)
+	}
+
+	err := p.Wait()
+
+	for _, f := range futs {
+		if !infos[f.ID()] {
+			t.Errorf("Future.ID() = %d, not reported to hooks: %v", f.ID(), infos)
+		}
+	}
+
+	var te *TaskError
+	if !errors.As(err, &te) || te.ID != futs[2].ID() {
+		t.Errorf("Pool.Wait() = %v, want a TaskError with ID %d", err, futs[2].ID())
+	}
+}
+
+func TestTypedPool_Submit(t *testing.T) {
+	tp, err := NewTyped[int](testDefaultNumTasks, WithNumWorkers(testDefaultNumWorkers))
+	if err != nil {
+		t.Fatalf("NewTyped() error = %v", err)
+	}
+
+	f, err := tp.Submit(func() (int, error) { return 42, nil })
+	if err != nil {
+		t.Fatalf("TypedPool.Submit() error = %v", err)
+	}
+
+	if err := tp.Wait(); err != nil {
+		t.Fatalf("TypedPool.Wait() error = %v", err)
+	}
+
+	if v, err := f.Result(); v != 42 || err != nil {
+		t.Errorf("TypedFuture.Result() = (%v, %v), want (42, nil)", v, err)
+	}
+}
+
+func TestFuture_Cancel(t *testing.T) {
+	p := testPool(context.Background(), 1, testDefaultNumTasks, false)
+
+	release := make(chan struct{})
+	running, _ := p.SubmitFuture(func() error {
+		<-release
+		return nil
+	})
+
+	ran := false
+	queued, err := p.SubmitFuture(func() error {
+		ran = true
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Pool.SubmitFuture() error = %v", err)
+	}
+
+	if !queued.Cancel() {
+		t.Error("Future.Cancel() = false for a queued task, want true")
+	}
+
+	if queued.Cancel() {
+		t.Error("Future.Cancel() = true for a cancelled task, want false")
+	}
+
+	close(release)
+	<-running.Done()
+
+	if running.Cancel() {
+		t.Error("Future.Cancel() = true for a finished task, want false")
+	}
+
+	if err := p.Wait(); err != nil {
+		t.Fatalf("Pool.Wait() error = %v", err)
+	}
+
+	if ran {
+		t.Error("cancelled task was executed")
+	}
+
+	if err := queued.Err(); !errors.Is(err, ErrTaskCanceled) {
+		t.Errorf("Future.Err() = %v, want %v", err, ErrTaskCanceled)
+	}
+}
+
+func TestTypedPool_Results(t *testing.T) {
+	const numTasks = 20
+
+	tp, err := NewTyped[int](numTasks, WithNumWorkers(testDefaultNumWorkers))
+	if err != nil {
+		t.Fatalf("NewTyped() error = %v", err)
+	}
+
+	results := tp.Results()
+	for i := 0; i < numTasks; i++ {
+		i := i
+		_, _ = tp.Submit(func() (int, error) {
+			if i%2 == 1 {
+				return 0, testErr
+			}
+			return i, nil
+		})
+	}
+
+	done := make(chan error)
+	go func() { done <- tp.Wait() }()
+
+	sum, failed := 0, 0
+	for r := range results {
+		if r.Err != nil {
+			failed++
+			continue
+		}
+		sum += r.Value
+	}
+
+	if err := <-done; !errors.Is(err, testErr) {
+		t.Errorf("TypedPool.Wait() = %v, want %v", err, testErr)
+	}
+
+	if sum != 90 || failed != numTasks/2 {
+		t.Errorf("sum = %d, failed = %d, want 90 and %d", sum, failed, numTasks/2)
+	}
+}
+
+func TestTypedPool_Results_ordered(t *testing.T) {
+	const numTasks = 50
+
+	tp, err := NewTyped[int](numTasks, WithNumWorkers(4), WithOrderedResults())
+	if err != nil {
+		t.Fatalf("NewTyped() error = %v", err)
+	}
+
+	results := tp.Results()
+	for i := 0; i < numTasks; i++ {
+		i := i
+		f, _ := tp.Submit(func() (int, error) {
+			time.Sleep(time.Duration(numTasks-i) * 10 * time.Microsecond) // later tasks finish first.
+			return i, nil
+		})
+
+		if i%10 == 9 {
+			f.Cancel()
+		}
+	}
+
+	go func() { _ = tp.Wait() }()
+
+	want := 0
+	for r := range results {
+		if want%10 == 9 {
+			if !errors.Is(r.Err, ErrTaskCanceled) && r.Value != want {
+				t.Errorf("Result = %+v, want task %d or a cancellation", r, want)
+			}
+		} else if r.Value != want || r.Err != nil {
+			t.Errorf("Result = %+v, want %d", r, want)
+		}
+
+		want++
+	}
+
+	if want != numTasks {
+		t.Errorf("received %d results, want %d", want, numTasks)
+	}
+}
+
+func TestFuture_Cancel_compaction(t *testing.T) {
+	const numTasks = 4 * compactMin
+
+	p := testPool(context.Background(), 1, numTasks, false)
+
+	started, release := make(chan struct{}), make(chan struct{})
+	_ = p.Submit(func() error {
+		close(started)
+		<-release
+		return nil
+	})
+	<-started
+
+	futures := make([]*Future, numTasks)
+	for i := range futures {
+		futures[i], _ = p.SubmitFuture(testNoOpFunc)
+	}
+
+	for _, f := range futures[:3*compactMin] {
+		f.Cancel()
+	}
+
+	p.mu.Lock()
+	pending := p.queue.len()
+	p.mu.Unlock()
+
+	if pending != compactMin {
+		t.Errorf("pending jobs = %d, want %d", pending, compactMin)
+	}
+
+	close(release)
+	_ = p.Wait()
+
+	if r := p.report(); r.Canceled != 3*compactMin || r.Succeeded != compactMin+1 {
+		t.Errorf("Report = %+v, want %d cancelled and %d succeeded", r, 3*compactMin, compactMin+1)
+	}
+}
@@ -0,0 +1,101 @@
+package gowp
+
+import (
+	"runtime/metrics"
+	"time"
+)
+
+const (
+	gcSampleInterval = time.Second // period over which the CPU fraction spent in GC is measured.
+	gcBackoff        = 0.75        // factor applied to the limit when GC overhead is high.
+)
+
+// gcLimit adjusts the number of tasks allowed to run at once to the GC overhead, see WithGCPressureLimit.
+type gcLimit struct {
+	min, max  int
+	threshold float64 // fraction of the CPU spent in GC above which the limit is cut.
+	interval  time.Duration
+	read      func() (gc, total float64) // returns the CPU time spent in GC and overall, in seconds, since the process started.
+
+	limit int // current limit, between min and max. Guarded by the mutex of the pool.
+}
+
+// WithGCPressureLimit returns an Option that steps the number of tasks running at once down when the garbage
+// collector takes a large share of the CPU, typically because of what the tasks allocate, and back up once
+// it recovers. Every second, the share of the CPU time the GC used over the second is measured: above
+// threshold, the limit is cut by a quarter, below half of threshold, it grows by one task. In between,
+// it is kept, so that the limit doesn't flap. The limit stays between min and the number of workers,
+// which is also where it starts. Stats reports the limit. It has no effect along with WithInlineExecution.
+//
+// Along with WithAdaptiveConcurrency, the lower of both limits applies.
+// min should be between one and the number of workers and threshold within (0, 1), otherwise
+// ErrInvalidGCLimit will be returned on Pool initialization.
+func WithGCPressureLimit(min int, threshold float64) Option {
+	return func(o *config) {
+		o.gc = &gcLimit{min: min, threshold: threshold, interval: gcSampleInterval, read: gcCPU}
+	}
+}
+
+func newGCLimit(g gcLimit, numWorkers int) *gcLimit {
+	g.max, g.limit = numWorkers, numWorkers
+
+	return &g
+}
+
+// limitGC adapts the limit of p.gc to the GC overhead, until the pool stops.
+func (p *Pool) limitGC() {
+	g := p.gc
+
+	t := p.clock.NewTicker(g.interval)
+	defer t.Stop()
+
+	lastGC, lastTotal := g.read()
+
+	for {
+		select {
+		case <-p.quit:
+			return
+		case <-p.exitFromErrG:
+			return
+		case <-t.C():
+			gc, total := g.read()
+			if total <= lastTotal {
+				continue // no CPU time accounted for yet, keep the previous sample.
+			}
+
+			frac := (gc - lastGC) / (total - lastTotal)
+			lastGC, lastTotal = gc, total
+
+			p.mu.Lock()
+			g.adjust(frac)
+			p.ready.Broadcast() // workers held back by the limit may proceed.
+			p.mu.Unlock()
+		}
+	}
+}
+
+// adjust updates the limit with the fraction of the CPU spent in GC over the last interval.
+// The mutex of the pool must be held.
+func (g *gcLimit) adjust(frac float64) {
+	switch {
+	case frac > g.threshold:
+		g.limit = int(float64(g.limit) * gcBackoff)
+		if g.limit < g.min {
+			g.limit = g.min
+		}
+
+	case frac < g.threshold/2 && g.limit < g.max:
+		g.limit++
+	}
+}
+
+// gcCPU returns the CPU time spent in GC and overall since the process started, in seconds.
+func gcCPU() (gc, total float64) {
+	samples := []metrics.Sample{
+		{Name: "/cpu/classes/gc/total:cpu-seconds"},
+		{Name: "/cpu/classes/total:cpu-seconds"},
+	}
+	metrics.Read(samples)
+
+	return samples[0].Value.Float64(), samples[1].Value.Float64()
+}
@@ -0,0 +1,96 @@
+package gowp
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestGCLimit_adjust(t *testing.T) {
+	g := newGCLimit(gcLimit{min: 2, threshold: 0.2}, 8)
+
+	steps := []struct {
+		name      string
+		frac      float64
+		wantLimit int
+	}{
+		{name: "low overhead at max", frac: 0.01, wantLimit: 8},
+		{name: "spike", frac: 0.5, wantLimit: 6},
+		{name: "still high", frac: 0.3, wantLimit: 4},
+		{name: "between thresholds", frac: 0.15, wantLimit: 4},
+		{name: "recovered", frac: 0.05, wantLimit: 5},
+		{name: "spikes down to min", frac: 0.9, wantLimit: 3},
+		{name: "at min", frac: 0.9, wantLimit: 2},
+		{name: "floored", frac: 0.9, wantLimit: 2},
+	}
+
+	for _, s := range steps {
+		t.Run(s.name, func(t *testing.T) {
+			g.adjust(s.frac)
+			if g.limit != s.wantLimit {
+				t.Errorf("gcLimit.adjust(%v) limit = %d, want %d", s.frac, g.limit, s.wantLimit)
+			}
+		})
+	}
+}
+
+func TestWithGCPressureLimit(t *testing.T) {
+	var (
+		mu        sync.Mutex
+		frac      float64 // share of the CPU spent in GC between reads.
+		gc, total float64
+	)
+	fake := func(o *config) {
+		o.gc.interval = time.Millisecond
+		o.gc.read = func() (float64, float64) {
+			mu.Lock()
+			defer mu.Unlock()
+
+			gc, total = gc+frac, total+1
+			return gc, total
+		}
+	}
+
+	p := testPool(context.Background(), 4, testDefaultNumTasks, false, WithGCPressureLimit(1, 0.2), fake)
+
+	steps := []struct {
+		name      string
+		frac      float64
+		wantLimit int
+	}{
+		{name: "high overhead", frac: 0.9, wantLimit: 1},
+		{name: "recovered", frac: 0, wantLimit: 4},
+	}
+
+	for _, s := range steps {
+		t.Run(s.name, func(t *testing.T) {
+			mu.Lock()
+			frac = s.frac
+			mu.Unlock()
+
+			deadline := time.Now().Add(time.Second)
+			for p.Stats().Limit != s.wantLimit {
+				if time.Now().After(deadline) {
+					t.Fatalf("Stats().Limit = %d, want %d", p.Stats().Limit, s.wantLimit)
+				}
+				time.Sleep(time.Millisecond)
+			}
+		})
+	}
+
+	if err := p.Wait(); err != nil {
+		t.Fatalf("Pool.Wait() error = %v", err)
+	}
+
+	if _, err := New(1, WithNumWorkers(2), WithGCPressureLimit(3, 0.2)); !errors.Is(err, ErrInvalidGCLimit) {
+		t.Errorf("New() error = %v, want %v", err, ErrInvalidGCLimit)
+	}
+}
+
+func TestGCCPU(t *testing.T) {
+	if gc, total := gcCPU(); gc < 0 || total < gc {
+		t.Errorf("gcCPU() = %v, %v, want 0 <= gc <= total", gc, total)
+	}
+}
@@ -1,3 +1,3 @@
 module github.com/akshaybharambe14/gowp
 
-go 1.16
+go 1.20
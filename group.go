@@ -0,0 +1,133 @@
+package gowp
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// Group runs functions and collects the first error like golang.org/x/sync/errgroup.Group, with the same methods.
+// Once a limit is set with SetLimit, functions are executed by a pool of workers instead of a goroutine each,
+// and queued while all of them are busy. A zero Group is valid, has no limit and does not cancel on error.
+//
+// Unlike errgroup, a limit of zero means no limit rather than blocking every call to Go.
+type Group struct {
+	cancel context.CancelCauseFunc // set by GroupWithContext.
+
+	mu    sync.Mutex
+	p     *Pool // executes the functions when a limit is set, created on demand. Guarded by mu.
+	limit int   // number of workers, no limit if less than or equal to zero. Guarded by mu.
+	busy  bool  // set when Go is called, reset by Wait. Guarded by mu.
+
+	wg      sync.WaitGroup // tracks the goroutines started when there is no limit.
+	errOnce sync.Once
+	err     error
+}
+
+// GroupWithContext returns a new Group and an associated Context derived from ctx, like errgroup.WithContext.
+// The derived Context is canceled the first time a function passed to Go returns a non-nil error
+// or the first time Wait returns, whichever occurs first.
+func GroupWithContext(ctx context.Context) (*Group, context.Context) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	return &Group{cancel: cancel}, ctx
+}
+
+// SetLimit limits the number of functions executing concurrently in this group to n, by executing them
+// with a pool of n workers. Up to n more functions are queued, once the queue is full Go blocks until
+// there is room. A value less than or equal to zero indicates no limit.
+//
+// The limit must not be modified while functions submitted to the group are pending, SetLimit panics in that case.
+func (g *Group) SetLimit(n int) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.busy {
+		panic("gowp: modify limit while functions of the group are pending")
+	}
+
+	g.limit = n
+}
+
+// Go calls the given function in a new goroutine, or on a worker of the group's pool if a limit is set.
+// It blocks until the function can be queued. The first call to return a non-nil error cancels the
+// group's context, if the group was created by calling GroupWithContext. The error will be returned by Wait.
+func (g *Group) Go(f func() error) {
+	g.do(f, true)
+}
+
+// TryGo calls the given function like Go, only if it can be queued without blocking.
+// The return value reports whether the function was queued.
+func (g *Group) TryGo(f func() error) bool {
+	return g.do(f, false)
+}
+
+// Wait blocks until all function calls from the Go method have returned, then returns the first non-nil
+// error (if any) from them. The error sticks, a Group reports it from every later call to Wait.
+func (g *Group) Wait() error {
+	g.mu.Lock()
+	p := g.p
+	g.p, g.busy = nil, false
+	g.mu.Unlock()
+
+	if p != nil {
+		_ = p.wait()
+	}
+
+	g.wg.Wait()
+
+	if g.cancel != nil {
+		g.cancel(g.err)
+	}
+
+	return g.err
+}
+
+func (g *Group) do(f func() error, block bool) bool {
+	t := func() error {
+		if err := f(); err != nil {
+			g.errOnce.Do(func() {
+				g.err = err
+				if g.cancel != nil {
+					g.cancel(g.err)
+				}
+			})
+		}
+
+		return nil // the group keeps track of the error, the pool keeps going like errgroup does.
+	}
+
+	g.mu.Lock()
+	g.busy = true
+
+	if g.limit <= 0 {
+		g.wg.Add(1)
+		g.mu.Unlock()
+
+		go func() {
+			defer g.wg.Done()
+			_ = t()
+		}()
+
+		return true
+	}
+
+	for {
+		if g.p == nil {
+			g.p = newPool(config{ctx: context.Background(), numWorkers: g.limit, clock: systemClock{}}, g.limit)
+		}
+		p := g.p
+		g.mu.Unlock()
+
+		err := p.submitJob(t, nil, nil, block)
+		if !errors.Is(err, ErrInvalidSend) && !errors.Is(err, ErrPoolClosed) {
+			return err == nil
+		}
+
+		// a concurrent Wait took the pool and closed it, submit to a new one instead of dropping f.
+		g.mu.Lock()
+		g.busy = true
+		if g.p == p {
+			g.p = nil
+		}
+	}
+}
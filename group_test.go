@@ -0,0 +1,175 @@
+package gowp
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGroup(t *testing.T) {
+	const limit = 3
+
+	g, ctx := GroupWithContext(context.Background())
+	g.SetLimit(limit)
+
+	var running, peak int32
+	for i := 0; i < 20; i++ {
+		g.Go(func() error {
+			n := atomic.AddInt32(&running, 1)
+			for {
+				old := atomic.LoadInt32(&peak)
+				if n <= old || atomic.CompareAndSwapInt32(&peak, old, n) {
+					break
+				}
+			}
+
+			time.Sleep(time.Millisecond)
+			atomic.AddInt32(&running, -1)
+
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		t.Fatalf("Group.Wait() error = %v", err)
+	}
+
+	if peak > limit {
+		t.Errorf("peak concurrency = %d, want at most %d", peak, limit)
+	}
+
+	if ctx.Err() == nil {
+		t.Error("context is not cancelled after Wait")
+	}
+}
+
+func TestGroup_error(t *testing.T) {
+	for _, limit := range []int{-1, 2} {
+		g, ctx := GroupWithContext(context.Background())
+		g.SetLimit(limit)
+
+		g.Go(func() error { return testErr })
+		g.Go(func() error {
+			<-ctx.Done()
+			return nil
+		})
+
+		if err := g.Wait(); !errors.Is(err, testErr) {
+			t.Errorf("limit %d: Group.Wait() = %v, want %v", limit, err, testErr)
+		}
+
+		if cause := context.Cause(ctx); !errors.Is(cause, testErr) {
+			t.Errorf("limit %d: context.Cause() = %v, want %v", limit, cause, testErr)
+		}
+	}
+}
+
+func TestGroup_noLimit(t *testing.T) {
+	const n = 10
+
+	for _, limit := range []int{-1, 0} {
+		var g Group
+		g.SetLimit(limit)
+
+		// every function waits for all of them to start, which only happens without a limit.
+		var started sync.WaitGroup
+		started.Add(n)
+		for i := 0; i < n; i++ {
+			g.Go(func() error {
+				started.Done()
+				started.Wait()
+				return nil
+			})
+		}
+
+		if err := g.Wait(); err != nil {
+			t.Errorf("limit %d: Group.Wait() error = %v", limit, err)
+		}
+	}
+}
+
+func TestGroup_errorSticks(t *testing.T) {
+	var g Group
+	g.Go(func() error { return testErr })
+	_ = g.Wait()
+
+	g.Go(func() error { return nil })
+	if err := g.Wait(); !errors.Is(err, testErr) {
+		t.Errorf("Group.Wait() = %v, want %v", err, testErr)
+	}
+}
+
+func TestGroup_TryGo(t *testing.T) {
+	var g Group
+	g.SetLimit(1)
+
+	release := make(chan struct{})
+	block := func() error {
+		<-release
+		return nil
+	}
+
+	// one running and one queued, the pool is saturated.
+	for g.TryGo(block) {
+	}
+
+	close(release)
+
+	if err := g.Wait(); err != nil {
+		t.Fatalf("Group.Wait() error = %v", err)
+	}
+
+	if !g.TryGo(func() error { return nil }) {
+		t.Error("Group.TryGo() = false after Wait, want true")
+	}
+
+	_ = g.Wait()
+}
+
+func TestGroup_concurrentWait(t *testing.T) {
+	var g Group
+	g.SetLimit(2)
+
+	const goroutines, n = 4, 500
+
+	var ran int32
+	var wg sync.WaitGroup
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < n; i++ {
+				g.Go(func() error {
+					atomic.AddInt32(&ran, 1)
+					return nil
+				})
+			}
+		}()
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	for waiting := true; waiting; {
+		select {
+		case <-done:
+			waiting = false
+		default:
+			_ = g.Wait()
+		}
+	}
+
+	if err := g.Wait(); err != nil {
+		t.Fatalf("Group.Wait() error = %v", err)
+	}
+
+	if got := atomic.LoadInt32(&ran); got != goroutines*n {
+		t.Errorf("ran %d functions, want %d", got, goroutines*n)
+	}
+}
@@ -0,0 +1,21 @@
+module github.com/akshaybharambe14/gowp/grpcmw
+
+go 1.20
+
+require (
+	github.com/akshaybharambe14/gowp v0.0.0-00010101000000-000000000000
+	google.golang.org/grpc v1.60.1
+)
+
+require (
+	github.com/golang/protobuf v1.5.3 // indirect
+	golang.org/x/net v0.16.0 // indirect
+	golang.org/x/sys v0.13.0 // indirect
+	golang.org/x/text v0.13.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20231002182017-d307bd883b97 // indirect
+	google.golang.org/protobuf v1.31.0 // indirect
+)
+
+// the core module is built from this repository until a release of it with the APIs used here is tagged,
+// the required version is a placeholder.
+replace github.com/akshaybharambe14/gowp => ./..
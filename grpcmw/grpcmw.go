@@ -0,0 +1,132 @@
+// Package grpcmw bounds the concurrency of gRPC handlers with a gowp pool.
+//
+// It is a separate module, so that the core package doesn't depend on gRPC.
+//
+// The interceptors run each call as a task of the pool, so that at most as many calls as workers are handled
+// at once and the others wait in the queue. A streaming call holds its worker until the stream ends.
+// Calls are rejected with codes.ResourceExhausted once the queue is full, or saturated if the pool is
+// configured with gowp.WithSaturationLimit, and once their method has reached its own limit, see WithMethodLimit.
+//
+// Example:
+//	wp, _ := gowp.New(100, gowp.WithNumWorkers(32))
+//	opts := []grpcmw.Option{grpcmw.WithMethodLimit("/search.Search/Query", 8)}
+//
+//	srv := grpc.NewServer(
+//		grpc.UnaryInterceptor(grpcmw.UnaryServerInterceptor(wp, opts...)),
+//		grpc.StreamInterceptor(grpcmw.StreamServerInterceptor(wp, opts...)),
+//	)
+package grpcmw // import "github.com/akshaybharambe14/gowp/grpcmw"
+
+import (
+	"context"
+	"errors"
+
+	"github.com/akshaybharambe14/gowp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type (
+	// Option configures the interceptors.
+	Option func(l *limiter)
+
+	// limiter admits the calls of an interceptor.
+	limiter struct {
+		p       *gowp.Pool
+		methods map[string]chan struct{} // slots of the methods with a limit, see WithMethodLimit. Read-only after initialization.
+	}
+)
+
+// WithMethodLimit returns an Option that bounds the number of calls of the given method, e.g. "/pkg.Service/Method",
+// queued or running at once. It keeps an expensive method from taking the whole pool. Limits less than one are ignored.
+func WithMethodLimit(method string, n int) Option {
+	return func(l *limiter) {
+		if n > 0 {
+			l.methods[method] = make(chan struct{}, n)
+		}
+	}
+}
+
+// UnaryServerInterceptor returns an interceptor that handles unary calls through p.
+func UnaryServerInterceptor(p *gowp.Pool, opts ...Option) grpc.UnaryServerInterceptor {
+	l := newLimiter(p, opts)
+
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		var resp interface{}
+		err := l.do(ctx, info.FullMethod, func() (err error) {
+			resp, err = handler(ctx, req)
+			return err
+		})
+
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor returns an interceptor that handles streaming calls through p.
+func StreamServerInterceptor(p *gowp.Pool, opts ...Option) grpc.StreamServerInterceptor {
+	l := newLimiter(p, opts)
+
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return l.do(ss.Context(), info.FullMethod, func() error {
+			return handler(srv, ss)
+		})
+	}
+}
+
+func newLimiter(p *gowp.Pool, opts []Option) *limiter {
+	l := &limiter{p: p, methods: make(map[string]chan struct{})}
+	for _, opt := range opts {
+		opt(l)
+	}
+
+	return l
+}
+
+// do runs call as a task of the pool and returns its error. A call whose context is done while it is queued
+// is dropped. Panics of the call are propagated, as if it ran on the goroutine of the caller.
+func (l *limiter) do(ctx context.Context, method string, call func() error) error {
+	if slots, ok := l.methods[method]; ok {
+		select {
+		case slots <- struct{}{}:
+			defer func() { <-slots }()
+		default:
+			return status.Errorf(codes.ResourceExhausted, "%s has too many calls in flight", method)
+		}
+	}
+
+	if errors.Is(l.p.Healthy(), gowp.ErrSaturated) {
+		return status.Error(codes.ResourceExhausted, gowp.ErrSaturated.Error())
+	}
+
+	var (
+		err      error
+		panicked interface{}
+	)
+	f, subErr := l.p.SubmitFuture(func() error {
+		defer func() { panicked = recover() }()
+
+		err = call()
+
+		return nil // errors of the calls are for their clients, not for the pool.
+	})
+	if subErr != nil {
+		return status.Error(codes.ResourceExhausted, subErr.Error())
+	}
+
+	select {
+	case <-f.Done():
+	case <-ctx.Done():
+		if f.Cancel() {
+			return status.FromContextError(ctx.Err()).Err()
+		}
+
+		<-f.Done() // the call is running already.
+	}
+
+	if panicked != nil {
+		panic(panicked)
+	}
+
+	return err
+}
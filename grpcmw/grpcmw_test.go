@@ -0,0 +1,125 @@
+package grpcmw
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/akshaybharambe14/gowp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type testStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s testStream) Context() context.Context { return s.ctx }
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	p, _ := gowp.New(1, gowp.WithNumWorkers(1))
+	icp := UnaryServerInterceptor(p, WithMethodLimit("/svc/Limited", 1))
+
+	started, release := make(chan struct{}), make(chan struct{})
+	blocking := func(ctx context.Context, req interface{}) (interface{}, error) {
+		close(started)
+		<-release
+		return "slow", nil
+	}
+
+	call := func(ctx context.Context, method string, h grpc.UnaryHandler) <-chan error {
+		out := make(chan error, 1)
+		go func() {
+			_, err := icp(ctx, nil, &grpc.UnaryServerInfo{FullMethod: method}, h)
+			out <- err
+		}()
+		return out
+	}
+
+	// the worker is busy with a call of the limited method.
+	running := call(context.Background(), "/svc/Limited", blocking)
+	<-started
+
+	ok := func(ctx context.Context, req interface{}) (interface{}, error) { return req, nil }
+
+	gone, leave := context.WithCancel(context.Background())
+	abandoned := call(gone, "/svc/Other", ok)
+	for p.Stats().Queued != 1 {
+		time.Sleep(time.Millisecond)
+	}
+	leave()
+
+	tests := []struct {
+		name     string
+		err      func() error
+		wantCode codes.Code
+	}{
+		{name: "abandoned while queued", err: func() error { return <-abandoned }, wantCode: codes.Canceled},
+		{name: "method limit", err: func() error { return <-call(context.Background(), "/svc/Limited", ok) }, wantCode: codes.ResourceExhausted},
+		{name: "queue full", err: func() error {
+			queued := call(context.Background(), "/svc/Other", ok)
+			for p.Stats().Queued != 1 {
+				time.Sleep(time.Millisecond)
+			}
+			err := <-call(context.Background(), "/svc/Other", ok)
+			close(release)
+			if err := <-queued; err != nil {
+				t.Errorf("queued call error = %v, want nil", err)
+			}
+			return err
+		}, wantCode: codes.ResourceExhausted},
+		{name: "running", err: func() error { return <-running }, wantCode: codes.OK},
+		{name: "handler error", err: func() error {
+			return <-call(context.Background(), "/svc/Other", func(context.Context, interface{}) (interface{}, error) {
+				return nil, status.Error(codes.NotFound, "not found")
+			})
+		}, wantCode: codes.NotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := status.Code(tt.err()); got != tt.wantCode {
+				t.Errorf("code = %v, want %v", got, tt.wantCode)
+			}
+		})
+	}
+
+	resp, err := icp(context.Background(), "req", &grpc.UnaryServerInfo{FullMethod: "/svc/Other"}, ok)
+	if resp != "req" || err != nil {
+		t.Errorf("interceptor = %v, %v, want %q, nil", resp, err, "req")
+	}
+
+	if err := p.Wait(); err != nil {
+		t.Errorf("Pool.Wait() = %v, want nil", err)
+	}
+}
+
+func TestStreamServerInterceptor(t *testing.T) {
+	p, _ := gowp.New(1, gowp.WithNumWorkers(1))
+	icp := StreamServerInterceptor(p)
+	info := &grpc.StreamServerInfo{FullMethod: "/svc/Stream"}
+	ss := testStream{ctx: context.Background()}
+
+	errStream := errors.New("stream failed")
+	if err := icp(nil, ss, info, func(interface{}, grpc.ServerStream) error { return errStream }); !errors.Is(err, errStream) {
+		t.Errorf("interceptor = %v, want %v", err, errStream)
+	}
+
+	func() {
+		defer func() {
+			if r := recover(); r != "boom" {
+				t.Errorf("recover() = %v, want the panic of the handler", r)
+			}
+		}()
+
+		_ = icp(nil, ss, info, func(interface{}, grpc.ServerStream) error { panic("boom") })
+	}()
+
+	// errors of the calls are not errors of the pool.
+	if err := p.Wait(); err != nil {
+		t.Errorf("Pool.Wait() = %v, want nil", err)
+	}
+}
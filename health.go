@@ -0,0 +1,74 @@
+package gowp
+
+import (
+	"fmt"
+	"time"
+)
+
+// saturationLimit tells how full the queue may be and for how long before the pool reports itself unhealthy.
+type saturationLimit struct {
+	threshold float64       // fraction of the queue size.
+	window    time.Duration // how long the queue may stay over the threshold.
+}
+
+// WithSaturationLimit returns an Option that makes Healthy report ErrSaturated once the queue has stayed
+// at least threshold full, e.g. 0.9 for 90% of the task count of the pool, for longer than window.
+// A queue that keeps filling up means the workers can't keep up with the load.
+//
+// threshold should be within (0, 1] and window greater than zero,
+// otherwise ErrInvalidSaturation will be returned on Pool initialization.
+func WithSaturationLimit(threshold float64, window time.Duration) Option {
+	return func(o *config) {
+		o.saturation = &saturationLimit{threshold: threshold, window: window}
+	}
+}
+
+// Healthy reports whether the pool is able to process its tasks, so that services can expose it
+// to liveness or readiness probes. It returns
+//   - ErrPoolStopped if the pool stopped early, e.g. because of an error or because its context was cancelled,
+//   - ErrWorkersLost if workers exited while the pool was still accepting tasks,
+//   - ErrSaturated if the queue has been saturated for too long, see WithSaturationLimit.
+//
+// A pool that was closed and completes its tasks normally stays healthy.
+func (p *Pool) Healthy() error {
+	select {
+	case <-p.quit:
+		if p.success != nil {
+			select {
+			case <-p.success:
+				return nil // stopped on purpose, see WithFirstSuccess.
+			default:
+			}
+		}
+
+		return ErrPoolStopped
+	default:
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !p.intakeOff && !p.lazy && p.workers < p.target {
+		return fmt.Errorf("%w: %d of %d running", ErrWorkersLost, p.workers, p.target)
+	}
+
+	if p.saturation != nil && !p.saturatedSince.IsZero() {
+		if d := p.clock.Now().Sub(p.saturatedSince); d > p.saturation.window {
+			return fmt.Errorf("%w: for %v", ErrSaturated, d.Round(time.Millisecond))
+		}
+	}
+
+	return nil
+}
+
+// noteLoad keeps track of the time the queue went over the saturation threshold. p.mu must be held.
+func (p *Pool) noteLoad() {
+	full := float64(p.queue.len()) >= p.saturation.threshold*float64(p.size)
+
+	switch {
+	case full && p.saturatedSince.IsZero():
+		p.saturatedSince = p.clock.Now()
+	case !full:
+		p.saturatedSince = time.Time{}
+	}
+}
@@ -0,0 +1,56 @@
+package gowp
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestPool_Healthy(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	p, err := New(4, WithContext(ctx), WithNumWorkers(1), WithSaturationLimit(0.5, 10*time.Millisecond))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	if err := p.Healthy(); err != nil {
+		t.Errorf("Pool.Healthy() = %v, want nil", err)
+	}
+
+	p.Pause()
+	_ = p.Submit(testNoOpFunc)
+	_ = p.Submit(testNoOpFunc)
+
+	if err := p.Healthy(); err != nil {
+		t.Errorf("Pool.Healthy() right after saturation = %v, want nil", err)
+	}
+
+	time.Sleep(20 * time.Millisecond)
+
+	if err := p.Healthy(); !errors.Is(err, ErrSaturated) {
+		t.Errorf("Pool.Healthy() = %v, want %v", err, ErrSaturated)
+	}
+
+	p.Resume()
+	for p.Stats().Queued > 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	if err := p.Healthy(); err != nil {
+		t.Errorf("Pool.Healthy() once drained = %v, want nil", err)
+	}
+
+	cancel()
+	_ = p.Wait()
+
+	if err := p.Healthy(); !errors.Is(err, ErrPoolStopped) {
+		t.Errorf("Pool.Healthy() = %v, want %v", err, ErrPoolStopped)
+	}
+
+	if _, err := New(1, WithSaturationLimit(1.5, time.Second)); !errors.Is(err, ErrInvalidSaturation) {
+		t.Errorf("New() error = %v, want %v", err, ErrInvalidSaturation)
+	}
+}
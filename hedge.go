@@ -0,0 +1,121 @@
+package gowp
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// hedged tracks the attempts of a task submitted with SubmitHedged.
+type hedged struct {
+	p      *Pool
+	f      *Future // reports the first completed attempt.
+	t      TaskCtx
+	opts   []TaskOption
+	ctx    context.Context
+	cancel context.CancelFunc
+	timer  Timer // launches the second attempt.
+
+	mu       sync.Mutex
+	attempts []*Future
+}
+
+// SubmitHedged submits t and, if it hasn't completed after delay, submits a duplicate of it. The first attempt
+// to complete, successfully or not, is reported by the returned Future and the other one is abandoned:
+// it is retracted if it is still queued, otherwise its context is cancelled and its outcome ignored.
+// This trims the tail latency of tasks that are occasionally slow, e.g. requests to a replicated service,
+// at the cost of running some of them twice. t should therefore be idempotent.
+//
+// The attempts are regular tasks of the pool, they are accounted for and their hooks are called.
+// Their context is derived from the context of the pool. Cancelling the Future abandons both attempts.
+func (p *Pool) SubmitHedged(t TaskCtx, delay time.Duration, opts ...TaskOption) (*Future, error) {
+	if t == nil {
+		return nil, fmt.Errorf("gowp.Pool.SubmitHedged(): %w", ErrNilTask)
+	}
+
+	h := &hedged{p: p, f: newFuture(), t: t}
+	h.f.pool = p
+	h.f.id = p.nextID() // attempts get their own.
+	h.ctx, h.cancel = context.WithCancel(p.ctx)
+
+	// don't modify the backing array of the caller's options.
+	h.opts = append(opts[:len(opts):len(opts)], func(j *job) { j.delayed = true })
+
+	err := p.hold(h.f, false, func() (stop func()) {
+		h.timer = p.clock.AfterFunc(delay, func() { _ = h.launch() })
+
+		return func() {
+			h.timer.Stop()
+			h.cancel()
+			go h.abandon() // p.mu is held, retracting the attempts needs it.
+		}
+	})
+	if err != nil {
+		h.cancel()
+		return nil, fmt.Errorf("gowp.Pool.SubmitHedged(): %w", err)
+	}
+
+	if err := h.launch(); err != nil {
+		h.timer.Stop()
+		h.cancel()
+		p.unhold(h.f)
+
+		return nil, fmt.Errorf("gowp.Pool.SubmitHedged(): %w", err)
+	}
+
+	return h.f, nil
+}
+
+// launch submits an attempt, unless the outcome is known already.
+func (h *hedged) launch() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.ctx.Err() != nil {
+		return nil
+	}
+
+	f := newFuture()
+	if err := h.p.submitJob(h.run, f, h.opts, false); err != nil {
+		return err
+	}
+
+	h.attempts = append(h.attempts, f)
+
+	return nil
+}
+
+// run executes an attempt and reports its outcome, if it is the first one to complete.
+func (h *hedged) run() error {
+	err := h.t(h.ctx)
+	if !h.p.unhold(h.f) {
+		return nil // lost the race, or abandoned.
+	}
+
+	if h.f.start() {
+		h.f.complete(err)
+	} else {
+		// cancelled while the attempt was completing, the pool was told about a queued task.
+		atomic.AddInt64(&h.p.counts.tombstones, -1)
+		atomic.AddInt64(&h.p.counts.canceled, 1)
+	}
+
+	h.timer.Stop()
+	h.cancel()
+	h.abandon()
+
+	return err
+}
+
+// abandon retracts the attempts that haven't started.
+func (h *hedged) abandon() {
+	h.mu.Lock()
+	attempts := h.attempts
+	h.mu.Unlock()
+
+	for _, f := range attempts {
+		f.Cancel()
+	}
+}
@@ -0,0 +1,87 @@
+package gowp
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestPool_SubmitHedged(t *testing.T) {
+	tests := []struct {
+		name         string
+		delay        time.Duration
+		wantErr      error
+		wantAttempts int32
+		wantCanceled bool // the slow attempt observed the cancellation.
+	}{
+		{name: "fast first attempt", delay: time.Hour, wantAttempts: 1},
+		{name: "hedged slow attempt", delay: 5 * time.Millisecond, wantAttempts: 2, wantCanceled: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := testPool(context.Background(), 2, testDefaultNumTasks, false)
+
+			var attempts int32
+			canceled := make(chan struct{})
+			f, err := p.SubmitHedged(func(ctx context.Context) error {
+				if atomic.AddInt32(&attempts, 1) == 1 && tt.wantCanceled {
+					<-ctx.Done() // the first attempt is stuck, the duplicate has to win.
+					close(canceled)
+				}
+
+				return nil
+			}, tt.delay)
+			if err != nil {
+				t.Fatalf("Pool.SubmitHedged() error = %v", err)
+			}
+
+			if err := f.Err(); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Future.Err() = %v, want %v", err, tt.wantErr)
+			}
+
+			if tt.wantCanceled {
+				<-canceled
+			}
+
+			if err := p.Wait(); err != nil {
+				t.Errorf("Pool.Wait() = %v, want nil", err)
+			}
+
+			if n := atomic.LoadInt32(&attempts); n != tt.wantAttempts {
+				t.Errorf("attempts = %d, want %d", n, tt.wantAttempts)
+			}
+		})
+	}
+}
+
+func TestPool_SubmitHedged_error(t *testing.T) {
+	p := testPool(context.Background(), 1, testDefaultNumTasks, false)
+
+	f, _ := p.SubmitHedged(func(ctx context.Context) error { return testErr }, time.Hour)
+	if err := f.Err(); !errors.Is(err, testErr) {
+		t.Errorf("Future.Err() = %v, want %v", err, testErr)
+	}
+
+	pending, _ := p.SubmitHedged(func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}, time.Hour)
+	if !pending.Cancel() {
+		t.Error("Future.Cancel() = false before an attempt completed")
+	}
+
+	if err := p.Wait(); !errors.Is(err, testErr) {
+		t.Errorf("Pool.Wait() = %v, want %v", err, testErr)
+	}
+
+	if _, err := p.SubmitHedged(nil, time.Second); !errors.Is(err, ErrNilTask) {
+		t.Errorf("Pool.SubmitHedged(nil) = %v, want %v", err, ErrNilTask)
+	}
+
+	if _, err := p.SubmitHedged(func(context.Context) error { return nil }, time.Second); !errors.Is(err, ErrPoolClosed) {
+		t.Errorf("Pool.SubmitHedged() after Wait = %v, want %v", err, ErrPoolClosed)
+	}
+}
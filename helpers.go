@@ -0,0 +1,211 @@
+package gowp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// Map applies fn to every input concurrently, using a pool created with opts, and returns the outputs
+// in the order of inputs. By default the pool exits on the first error, which is then returned along with
+// the outputs produced so far. Outputs of inputs that failed or were never processed hold the zero value.
+// The pool is bound to ctx, any WithContext option is overridden.
+func Map[In, Out any](ctx context.Context, inputs []In, fn func(In) (Out, error), opts ...Option) ([]Out, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("gowp.Map(): %w", err)
+	}
+
+	outs := make([]Out, len(inputs))
+	if len(inputs) == 0 {
+		return outs, nil
+	}
+
+	opts = append([]Option{WithExitOnError(true)}, opts...)
+	opts = append(opts, WithContext(ctx))
+
+	cfg, err := newConfig(len(inputs), opts)
+	if err != nil {
+		return nil, fmt.Errorf("gowp.Map(): %w", err)
+	}
+
+	p := newPool(cfg, len(inputs))
+	for i := range inputs {
+		i := i
+		err := p.submit(func() error {
+			out, err := fn(inputs[i])
+			outs[i] = out
+			return err
+		}, nil, nil)
+		if err != nil {
+			_ = p.wait()
+			return outs, fmt.Errorf("gowp.Map(): %w", err)
+		}
+	}
+
+	if err := p.wait(); err != nil {
+		return outs, fmt.Errorf("gowp.Map(): %w", err)
+	}
+
+	return outs, nil
+}
+
+// ForEach calls fn for every item concurrently, using a pool created with opts. By default the pool exits on
+// the first error, which is returned. If WithExitOnError(false) is passed, all the items are processed and the
+// errors are joined in the order of items. The pool is bound to ctx, any WithContext option is overridden.
+func ForEach[T any](ctx context.Context, items []T, fn func(T) error, opts ...Option) error {
+	err := forEach(ctx, items, func(_ int, item T) error { return fn(item) }, opts)
+	if err != nil {
+		return fmt.Errorf("gowp.ForEach(): %w", err)
+	}
+
+	return nil
+}
+
+// ForEachIdx is like ForEach, but fn also receives the index of the item.
+func ForEachIdx[T any](ctx context.Context, items []T, fn func(int, T) error, opts ...Option) error {
+	if err := forEach(ctx, items, fn, opts); err != nil {
+		return fmt.Errorf("gowp.ForEachIdx(): %w", err)
+	}
+
+	return nil
+}
+
+// Reduce maps every input concurrently through mapFn, using a pool created with opts, and folds the results
+// into an accumulator starting from init with reduceFn. Results are folded as they come, one at a time, so
+// reduceFn needs no synchronization but should not depend on the order of inputs, e.g. a sum or a merge.
+// Like ForEach, the pool exits on the first error by default, which is returned along with the accumulator
+// folded so far. The pool is bound to ctx, any WithContext option is overridden.
+//
+//	total, err := gowp.Reduce(ctx, files, 0, countLines, func(acc, n int) int { return acc + n })
+func Reduce[T, R any](ctx context.Context, inputs []T, init R, mapFn func(T) (R, error), reduceFn func(acc, r R) R, opts ...Option) (R, error) {
+	var mu sync.Mutex
+	acc := init
+
+	err := forEach(ctx, inputs, func(_ int, in T) error {
+		r, err := mapFn(in)
+		if err != nil {
+			return err
+		}
+
+		mu.Lock()
+		acc = reduceFn(acc, r)
+		mu.Unlock()
+
+		return nil
+	}, opts)
+	if err != nil {
+		return acc, fmt.Errorf("gowp.Reduce(): %w", err)
+	}
+
+	return acc, nil
+}
+
+// Consume calls handler for every value received from ch until it is closed, using a pool created with opts.
+// Values are received only as fast as the workers handle them, one value is queued per worker at most, so
+// the producer is slowed down by a slow handler. Like ForEach, the pool exits on the first error by default,
+// which is returned, the remaining values are left in ch. If WithExitOnError(false) is passed, all the values
+// are handled and the errors are joined in the order they occurred. The pool is bound to ctx, any WithContext
+// option is overridden.
+func Consume[T any](ctx context.Context, ch <-chan T, handler func(T) error, opts ...Option) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("gowp.Consume(): %w", err)
+	}
+
+	opts = append([]Option{WithExitOnError(true)}, opts...)
+	opts = append(opts, WithContext(ctx))
+
+	cfg, err := newConfig(1, opts)
+	if err != nil {
+		return fmt.Errorf("gowp.Consume(): %w", err)
+	}
+
+	var (
+		mu   sync.Mutex
+		errs []error
+	)
+
+	p := newPool(cfg, cfg.numWorkers)
+	halt := p.Context()
+
+	var rejected error
+loop:
+	for {
+		select {
+		case <-halt.Done():
+			break loop
+		case v, ok := <-ch:
+			if !ok {
+				break loop
+			}
+
+			rejected = p.submitJob(func() error {
+				err := handler(v)
+				if err != nil {
+					mu.Lock()
+					errs = append(errs, err)
+					mu.Unlock()
+				}
+
+				return err
+			}, nil, nil, true)
+			if rejected != nil {
+				break loop // the pool stopped.
+			}
+		}
+	}
+
+	err = p.wait()
+	if !cfg.exitOnErr && len(errs) > 0 {
+		err = errors.Join(errs...) // the pool reports only the first one.
+	}
+
+	if err == nil {
+		err = rejected
+	}
+
+	if err != nil {
+		return fmt.Errorf("gowp.Consume(): %w", err)
+	}
+
+	return nil
+}
+
+func forEach[T any](ctx context.Context, items []T, fn func(int, T) error, opts []Option) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if len(items) == 0 {
+		return nil
+	}
+
+	opts = append([]Option{WithExitOnError(true)}, opts...)
+	opts = append(opts, WithContext(ctx))
+
+	cfg, err := newConfig(len(items), opts)
+	if err != nil {
+		return err
+	}
+
+	p := newPool(cfg, len(items))
+	errs := make([]error, len(items)) // each task writes its own slot.
+	for i := range items {
+		i := i
+		err := p.submit(func() error {
+			errs[i] = fn(i, items[i])
+			return errs[i]
+		}, nil, nil)
+		if err != nil {
+			_ = p.wait()
+			return err
+		}
+	}
+
+	err = p.wait()
+	if cfg.exitOnErr || err == nil {
+		return err
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,200 @@
+package gowp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestMap(t *testing.T) {
+	inputs := []int{1, 2, 3, 4, 5, 6, 7, 8}
+
+	got, err := Map(context.Background(), inputs, func(i int) (string, error) {
+		return strconv.Itoa(i * i), nil
+	}, WithNumWorkers(3))
+	if err != nil {
+		t.Fatalf("Map() error = %v", err)
+	}
+
+	want := []string{"1", "4", "9", "16", "25", "36", "49", "64"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Map() = %v, want %v", got, want)
+	}
+
+	_, err = Map(context.Background(), inputs, func(i int) (string, error) {
+		if i == 3 {
+			return "", testErr
+		}
+		return "", nil
+	})
+	if !errors.Is(err, testErr) {
+		t.Errorf("Map() error = %v, want %v", err, testErr)
+	}
+
+	if got, err := Map(context.Background(), nil, func(i int) (int, error) { return i, nil }); len(got) != 0 || err != nil {
+		t.Errorf("Map(nil) = (%v, %v), want empty output and no error", got, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := Map(ctx, inputs, func(i int) (int, error) { return i, nil }); !errors.Is(err, context.Canceled) {
+		t.Errorf("Map() error = %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestForEach(t *testing.T) {
+	items := []string{"a", "b", "c", "d"}
+
+	var got [4]string
+	err := ForEachIdx(context.Background(), items, func(i int, s string) error {
+		got[i] = s
+		return nil
+	}, WithNumWorkers(2))
+	if err != nil {
+		t.Fatalf("ForEachIdx() error = %v", err)
+	}
+
+	if !reflect.DeepEqual(got[:], items) {
+		t.Errorf("ForEachIdx() visited %v, want %v", got, items)
+	}
+
+	errB, errD := errors.New("b"), errors.New("d")
+	fail := func(s string) error {
+		switch s {
+		case "b":
+			return errB
+		case "d":
+			return errD
+		}
+		return nil
+	}
+
+	if err := ForEach(context.Background(), items, fail); !errors.Is(err, errB) && !errors.Is(err, errD) {
+		t.Errorf("ForEach() error = %v, want %v or %v", err, errB, errD)
+	}
+
+	err = ForEach(context.Background(), items, fail, WithExitOnError(false))
+	if !errors.Is(err, errB) || !errors.Is(err, errD) {
+		t.Errorf("ForEach() error = %v, want both %v and %v", err, errB, errD)
+	}
+}
+
+func TestReduce(t *testing.T) {
+	inputs := []int{1, 2, 3, 4, 5, 6, 7, 8}
+	square := func(i int) (int, error) { return i * i, nil }
+	sum := func(acc, n int) int { return acc + n }
+
+	tests := []struct {
+		name    string
+		inputs  []int
+		init    int
+		mapFn   func(int) (int, error)
+		opts    []Option
+		want    int
+		wantErr error
+	}{
+		{name: "sum of squares", inputs: inputs, mapFn: square, opts: []Option{WithNumWorkers(3)}, want: 204},
+		{name: "init", inputs: inputs, init: 100, mapFn: square, want: 304},
+		{name: "no inputs", init: 7, mapFn: square, want: 7},
+		{
+			name:   "error",
+			inputs: inputs,
+			mapFn: func(i int) (int, error) {
+				if i == 3 {
+					return 0, testErr
+				}
+				return i, nil
+			},
+			want:    -1, // depends on the tasks that ran before the pool stopped.
+			wantErr: testErr,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Reduce(context.Background(), tt.inputs, tt.init, tt.mapFn, sum, tt.opts...)
+			if !errors.Is(err, tt.wantErr) || (err == nil) != (tt.wantErr == nil) {
+				t.Fatalf("Reduce() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if tt.want >= 0 && got != tt.want {
+				t.Errorf("Reduce() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConsume(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		values   int
+		fail     []int // values the handler fails on.
+		opts     []Option
+		wantSum  int64
+		wantErr  error
+		wantErrs int // errors of the failing values reported.
+		checkSum bool
+	}{
+		{name: "handles every value", ctx: context.Background(), values: 10, wantSum: 45, checkSum: true},
+		{name: "closed channel", ctx: context.Background(), values: 0, wantSum: 0, checkSum: true},
+		{name: "exits on error", ctx: context.Background(), values: 10, fail: []int{3}, wantErrs: 1},
+		{name: "keeps going on error", ctx: context.Background(), values: 10, fail: []int{3, 5, 7}, opts: []Option{WithExitOnError(false)}, wantSum: 30, wantErrs: 3, checkSum: true},
+		{name: "cancelled context", ctx: ctx, values: 0, wantErr: context.Canceled},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			failures := make(map[int]error, len(tt.fail))
+			for _, v := range tt.fail {
+				failures[v] = fmt.Errorf("value %d failed", v)
+			}
+
+			ch, n := make(chan int), tt.values
+			go func() {
+				defer close(ch)
+				for i := 0; i < n; i++ {
+					select {
+					case ch <- i:
+					case <-time.After(time.Second): // the consumer gave up.
+						return
+					}
+				}
+			}()
+
+			var sum int64
+			err := Consume(tt.ctx, ch, func(v int) error {
+				if err := failures[v]; err != nil {
+					return err
+				}
+				atomic.AddInt64(&sum, int64(v))
+				return nil
+			}, append([]Option{WithNumWorkers(2)}, tt.opts...)...)
+			if (tt.wantErr != nil && !errors.Is(err, tt.wantErr)) || (tt.wantErr == nil && tt.wantErrs == 0 && err != nil) {
+				t.Fatalf("Consume() error = %v, want %v", err, tt.wantErr)
+			}
+
+			reported := 0
+			for _, ferr := range failures {
+				if errors.Is(err, ferr) {
+					reported++
+				}
+			}
+			if reported != tt.wantErrs {
+				t.Errorf("Consume() error = %v, reports %d of the failures, want %d", err, reported, tt.wantErrs)
+			}
+
+			if tt.checkSum && sum != tt.wantSum {
+				t.Errorf("Consume() handled a sum of %d, want %d", sum, tt.wantSum)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package gowp
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+const (
+	histogramBuckets = 10                     // bounded buckets of a histogram, an extra one counts the durations above.
+	histogramBase    = 100 * time.Microsecond // bound of the first bucket, each next bound is histogramFactor times larger.
+	histogramFactor  = 4
+)
+
+// histogramBounds are the upper bounds of the buckets, from 100µs to about 26s.
+var histogramBounds = func() []time.Duration {
+	bounds := make([]time.Duration, histogramBuckets)
+	for i, b := 0, histogramBase; i < histogramBuckets; i, b = i+1, b*histogramFactor {
+		bounds[i] = b
+	}
+
+	return bounds
+}()
+
+// Histogram is the distribution of durations observed by a pool, see Stats.QueueWait and Stats.Duration.
+type Histogram struct {
+	Bounds []time.Duration // upper bounds of the buckets, inclusive, in increasing order.
+	Counts []int           // durations per bucket, the last one counts the durations above the last bound.
+	Count  int             // durations observed.
+	Sum    time.Duration   // sum of the durations observed.
+}
+
+// Quantile returns an estimate of the q-quantile of the durations, e.g. 0.95 for the 95th percentile:
+// the upper bound of the bucket it falls in, or the last bound if it is above. Zero, if there are none.
+func (h Histogram) Quantile(q float64) time.Duration {
+	if h.Count == 0 || len(h.Bounds) == 0 {
+		return 0
+	}
+
+	rank := int(q * float64(h.Count))
+	if rank >= h.Count {
+		rank = h.Count - 1
+	}
+
+	for i, n := range h.Counts {
+		if rank -= n; rank < 0 && i < len(h.Bounds) {
+			return h.Bounds[i]
+		}
+	}
+
+	return h.Bounds[len(h.Bounds)-1]
+}
+
+// histogram records durations concurrently.
+type histogram struct {
+	sum    int64 // kept first to guarantee 64-bit alignment on 32-bit platforms, along with counts.
+	counts [histogramBuckets + 1]int64
+}
+
+// observe records d.
+func (h *histogram) observe(d time.Duration) {
+	i := 0
+	for i < histogramBuckets && d > histogramBounds[i] {
+		i++
+	}
+
+	atomic.AddInt64(&h.counts[i], 1)
+	atomic.AddInt64(&h.sum, int64(d))
+}
+
+// snapshot returns the durations recorded so far.
+func (h *histogram) snapshot() Histogram {
+	s := Histogram{
+		Bounds: append([]time.Duration(nil), histogramBounds...),
+		Counts: make([]int, len(h.counts)),
+		Sum:    time.Duration(atomic.LoadInt64(&h.sum)),
+	}
+
+	for i := range h.counts {
+		s.Counts[i] = int(atomic.LoadInt64(&h.counts[i]))
+		s.Count += s.Counts[i]
+	}
+
+	return s
+}
@@ -0,0 +1,127 @@
+package gowp
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestHistogram(t *testing.T) {
+	tests := []struct {
+		name       string
+		durations  []time.Duration
+		wantCounts map[int]int // bucket index to count, other buckets are empty.
+		wantP50    time.Duration
+		wantP99    time.Duration
+	}{
+		{name: "empty"},
+		{
+			name:       "on the bounds",
+			durations:  []time.Duration{0, 100 * time.Microsecond, 400 * time.Microsecond},
+			wantCounts: map[int]int{0: 2, 1: 1},
+			wantP50:    100 * time.Microsecond,
+			wantP99:    400 * time.Microsecond,
+		},
+		{
+			name:       "spread",
+			durations:  []time.Duration{time.Millisecond, 2 * time.Millisecond, 50 * time.Millisecond, time.Second},
+			wantCounts: map[int]int{2: 1, 3: 1, 5: 1, 7: 1},
+			wantP50:    102400 * time.Microsecond,
+			wantP99:    1638400 * time.Microsecond,
+		},
+		{
+			name:       "above the last bound",
+			durations:  []time.Duration{time.Minute, time.Minute},
+			wantCounts: map[int]int{histogramBuckets: 2},
+			wantP50:    histogramBounds[histogramBuckets-1],
+			wantP99:    histogramBounds[histogramBuckets-1],
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var h histogram
+			var sum time.Duration
+			for _, d := range tt.durations {
+				h.observe(d)
+				sum += d
+			}
+
+			s := h.snapshot()
+			if s.Count != len(tt.durations) || s.Sum != sum {
+				t.Errorf("snapshot() Count, Sum = %d, %v, want %d, %v", s.Count, s.Sum, len(tt.durations), sum)
+			}
+
+			for i, n := range s.Counts {
+				if n != tt.wantCounts[i] {
+					t.Errorf("snapshot() Counts[%d] = %d, want %d", i, n, tt.wantCounts[i])
+				}
+			}
+
+			if got := s.Quantile(0.5); got != tt.wantP50 {
+				t.Errorf("Histogram.Quantile(0.5) = %v, want %v", got, tt.wantP50)
+			}
+
+			if got := s.Quantile(0.99); got != tt.wantP99 {
+				t.Errorf("Histogram.Quantile(0.99) = %v, want %v", got, tt.wantP99)
+			}
+		})
+	}
+}
+
+func TestPool_Stats_queueWait(t *testing.T) {
+	p := testPool(context.Background(), 1, testDefaultNumTasks, false)
+
+	for i := 0; i < testDefaultNumTasks; i++ {
+		if err := p.Submit(func() error {
+			time.Sleep(time.Millisecond)
+			return nil
+		}); err != nil {
+			t.Fatalf("Pool.Submit() error = %v", err)
+		}
+	}
+
+	if err := p.Wait(); err != nil {
+		t.Fatalf("Pool.Wait() error = %v", err)
+	}
+
+	s := p.Stats().QueueWait
+	if s.Count != testDefaultNumTasks {
+		t.Errorf("Stats().QueueWait.Count = %d, want %d", s.Count, testDefaultNumTasks)
+	}
+
+	// a single worker runs the tasks one after the other, so the last one waited for the others.
+	if s.Quantile(1) < time.Millisecond {
+		t.Errorf("Stats().QueueWait.Quantile(1) = %v, want at least %v", s.Quantile(1), time.Millisecond)
+	}
+}
+
+func TestPool_Stats_duration(t *testing.T) {
+	p := testPool(context.Background(), 2, testDefaultNumTasks, false)
+
+	for i := 0; i < testDefaultNumTasks; i++ {
+		if err := p.Submit(func() error {
+			time.Sleep(2 * time.Millisecond)
+			return nil
+		}); err != nil {
+			t.Fatalf("Pool.Submit() error = %v", err)
+		}
+	}
+
+	if err := p.Wait(); err != nil {
+		t.Fatalf("Pool.Wait() error = %v", err)
+	}
+
+	s := p.Stats().Duration
+	if s.Count != testDefaultNumTasks {
+		t.Errorf("Stats().Duration.Count = %d, want %d", s.Count, testDefaultNumTasks)
+	}
+
+	if s.Sum < testDefaultNumTasks*2*time.Millisecond {
+		t.Errorf("Stats().Duration.Sum = %v, want at least %v", s.Sum, testDefaultNumTasks*2*time.Millisecond)
+	}
+
+	if got := s.Quantile(0.5); got < 2*time.Millisecond {
+		t.Errorf("Stats().Duration.Quantile(0.5) = %v, want at least %v", got, 2*time.Millisecond)
+	}
+}
@@ -0,0 +1,138 @@
+package gowp
+
+import (
+	"fmt"
+	"time"
+)
+
+type (
+	// TaskInfo describes a task to the hooks.
+	TaskInfo struct {
+		ID          uint64    // unique within the pool, assigned in the order of submission.
+		Label       string    // label of the task, see TaskLabel.
+		Name        string    // name of the task, see TaskName.
+		Tenant      string    // tenant of the task, see TaskTenant.
+		SubmittedAt time.Time // time at which the task was submitted.
+		StartedAt   time.Time // time at which a worker started the task, zero if it never started.
+	}
+
+	// Hooks are callbacks invoked by the pool during the life cycle of every task.
+	// They are the extension point for integrations like metrics and tracing,
+	// which live in their own modules to keep this package free of dependencies.
+	//
+	// Every OnSubmit call is matched by exactly one OnFinish call, whether the task was executed or not.
+	// OnStart is called only for tasks that are executed. A nil field is ignored.
+	// Hooks are called synchronously from submitting and worker goroutines, so they should be fast
+	// and safe for concurrent use. A panicking hook doesn't affect the task or the pool,
+	// the panic is recovered and reported to the handler set with WithHookErrorHandler.
+	Hooks struct {
+		// OnSubmit is called before the task is queued.
+		OnSubmit func(TaskInfo)
+		// OnStart is called by the worker right before executing the task.
+		OnStart func(TaskInfo)
+		// OnFinish is called with the error returned by the task, or with the reason it wasn't executed,
+		// e.g. ErrNoBuffer, ErrTaskCanceled or ErrTaskDiscarded.
+		OnFinish func(TaskInfo, error)
+		// OnWorkerStart is called by every worker, regular or temporary, when it starts, before it picks a task.
+		// It lets workers initialize, see WithPrestart.
+		OnWorkerStart func()
+	}
+
+	// HookError reports a panic recovered from a hook or from an AfterFunc callback.
+	HookError struct {
+		Hook  string      // OnSubmit, OnStart, OnFinish, OnWorkerStart, AfterFunc, StallDetector, EventSink or OnProgress.
+		Task  TaskInfo    // the task the hook was called for, zero for the callbacks about the pool.
+		Value interface{} // the value passed to panic.
+	}
+)
+
+// interface guard to ensure HookError implements error interface
+var _ error = (*HookError)(nil)
+
+// WithHooks returns an Option that registers hooks with the pool.
+// It can be used multiple times, hooks are called in the order they were registered.
+func WithHooks(h Hooks) Option {
+	return func(o *config) {
+		o.hooks = append(o.hooks, h)
+	}
+}
+
+// WithHookErrorHandler returns an Option that sets the handler of panics recovered from hooks
+// and AfterFunc callbacks. The handler receives a *HookError, it is called from the goroutine
+// that ran the callback and must not panic itself. Without a handler, such panics are dropped.
+func WithHookErrorHandler(handler func(error)) Option {
+	return func(o *config) {
+		o.hookErrs = handler
+	}
+}
+
+func (e *HookError) Error() string {
+	return fmt.Sprintf("hook %s panicked: %v", e.Hook, e.Value)
+}
+
+// Unwrap returns the value passed to panic if it is an error, nil otherwise.
+func (e *HookError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// guard calls fn and reports a panic, if any, as a HookError.
+func (p *Pool) guard(hook string, info TaskInfo, fn func()) {
+	defer func() {
+		if r := recover(); r != nil && p.hookErrs != nil {
+			p.hookErrs(&HookError{Hook: hook, Task: info, Value: r})
+		}
+	}()
+
+	fn()
+}
+
+func (j *job) info() TaskInfo {
+	return TaskInfo{
+		ID:          j.id,
+		Label:       j.label,
+		Name:        j.name,
+		Tenant:      j.tenant,
+		SubmittedAt: j.submittedAt,
+		StartedAt:   j.startedAt,
+	}
+}
+
+func (p *Pool) onSubmit(j *job) {
+	for _, h := range p.hooks {
+		if h.OnSubmit != nil {
+			info := j.info()
+			p.guard("OnSubmit", info, func() { h.OnSubmit(info) })
+		}
+	}
+}
+
+func (p *Pool) onStart(j *job) {
+	if len(p.hooks) == 0 {
+		return
+	}
+
+	for _, h := range p.hooks {
+		if h.OnStart != nil {
+			info := j.info()
+			p.guard("OnStart", info, func() { h.OnStart(info) })
+		}
+	}
+}
+
+func (p *Pool) onFinish(j *job, err error) {
+	for _, h := range p.hooks {
+		if h.OnFinish != nil {
+			info := j.info()
+			p.guard("OnFinish", info, func() { h.OnFinish(info, err) })
+		}
+	}
+}
+
+func (p *Pool) onWorkerStart() {
+	for _, h := range p.hooks {
+		if h.OnWorkerStart != nil {
+			p.guard("OnWorkerStart", TaskInfo{}, h.OnWorkerStart)
+		}
+	}
+}
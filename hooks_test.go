@@ -0,0 +1,149 @@
+package gowp
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWithHooks(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		submits  int
+		starts   int
+		finishes = map[uint64]error{}
+	)
+
+	h := Hooks{
+		OnSubmit: func(TaskInfo) {
+			mu.Lock()
+			submits++
+			mu.Unlock()
+		},
+		OnStart: func(info TaskInfo) {
+			mu.Lock()
+			starts++
+			mu.Unlock()
+
+			if info.StartedAt.Before(info.SubmittedAt) {
+				t.Errorf("TaskInfo.StartedAt = %v, before SubmittedAt %v", info.StartedAt, info.SubmittedAt)
+			}
+		},
+		OnFinish: func(info TaskInfo, err error) {
+			mu.Lock()
+			finishes[info.ID] = err
+			mu.Unlock()
+		},
+	}
+
+	p := testPool(context.Background(), 1, 2, false, WithHooks(h))
+
+	started, release := make(chan struct{}), make(chan struct{})
+	_ = p.Submit(func() error {
+		close(started)
+		<-release
+		return nil
+	})
+	<-started
+
+	f, _ := p.SubmitFuture(testFuncWithErr)
+	f.Cancel()
+	_ = p.Submit(testFuncWithErr)
+
+	// the queue is full, the cancelled task makes room for this one.
+	if err := p.Submit(testNoOpFunc); err != nil {
+		t.Fatalf("Pool.Submit() = %v, want nil", err)
+	}
+
+	if err := p.Submit(testNoOpFunc); !errors.Is(err, ErrNoBuffer) {
+		t.Fatalf("Pool.Submit() = %v, want %v", err, ErrNoBuffer)
+	}
+
+	close(release)
+	_ = p.Wait()
+
+	want := map[uint64]error{1: nil, 2: ErrTaskCanceled, 3: testErr, 4: nil, 5: ErrNoBuffer}
+	if submits != 5 || starts != 3 || len(finishes) != len(want) {
+		t.Fatalf("submits = %d, starts = %d, finishes = %v", submits, starts, finishes)
+	}
+
+	for id, err := range want {
+		if !errors.Is(finishes[id], err) {
+			t.Errorf("OnFinish(%d) error = %v, want %v", id, finishes[id], err)
+		}
+	}
+}
+
+func TestWithHookErrorHandler(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		hooks []string
+	)
+
+	h := Hooks{
+		OnSubmit: func(TaskInfo) { panic("submit") },
+		OnStart:  func(TaskInfo) { panic(testErr) },
+		OnFinish: func(TaskInfo, error) { panic("finish") },
+	}
+
+	handler := func(err error) {
+		var he *HookError
+		if !errors.As(err, &he) {
+			t.Errorf("handler got %T, want *HookError", err)
+			return
+		}
+
+		mu.Lock()
+		hooks = append(hooks, he.Hook)
+		mu.Unlock()
+	}
+
+	p := testPool(context.Background(), 1, 2, false, WithHooks(h), WithHookErrorHandler(handler))
+
+	f, _ := p.SubmitFuture(testNoOpFunc)
+	if err := f.Err(); err != nil {
+		t.Errorf("Future.Err() = %v, want nil", err)
+	}
+
+	reports := make(chan Report, 1)
+	p.AfterFunc(func(Report) { panic("after") })
+	p.AfterFunc(func(r Report) { reports <- r })
+
+	if err := p.Wait(); err != nil {
+		t.Errorf("Pool.Wait() = %v, want nil", err)
+	}
+
+	if r := <-reports; r.Succeeded != 1 {
+		t.Errorf("Report.Succeeded = %d, want 1", r.Succeeded)
+	}
+
+	// the AfterFunc panic is reported from its own goroutine, give it a moment.
+	deadline := time.Now().Add(time.Second)
+	for {
+		mu.Lock()
+		n := len(hooks)
+		mu.Unlock()
+
+		if n == 4 || time.Now().After(deadline) {
+			break
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	sort.Strings(hooks)
+	if want := []string{"AfterFunc", "OnFinish", "OnStart", "OnSubmit"}; !reflect.DeepEqual(hooks, want) {
+		t.Errorf("reported hooks = %v, want %v", hooks, want)
+	}
+
+	if err := (&HookError{Hook: "OnStart", Value: testErr}); !errors.Is(err, testErr) {
+		t.Errorf("HookError does not unwrap to the panic value")
+	}
+}
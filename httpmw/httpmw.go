@@ -0,0 +1,102 @@
+// Package httpmw bounds the concurrency of HTTP handlers with a gowp pool.
+//
+// The middleware runs the handler of each request as a task of the pool, so that at most as many requests
+// as workers are served at once and the others wait in the queue. Requests are shed with
+// 503 Service Unavailable and a Retry-After header once the queue is full, or saturated if the pool
+// is configured with gowp.WithSaturationLimit, rather than piling up.
+//
+// Example:
+//	wp, _ := gowp.New(100, gowp.WithNumWorkers(16))
+//
+//	http.Handle("/search", httpmw.Middleware(wp)(searchHandler))
+package httpmw // import "github.com/akshaybharambe14/gowp/httpmw"
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/akshaybharambe14/gowp"
+)
+
+type (
+	// Option configures the middleware.
+	Option func(cfg *config)
+
+	config struct {
+		retryAfter time.Duration // see WithRetryAfter.
+	}
+)
+
+// WithRetryAfter returns an Option that sets the delay advertised to the clients of shed requests,
+// rounded up to the second. It defaults to one second.
+func WithRetryAfter(d time.Duration) Option {
+	return func(cfg *config) {
+		if d > 0 {
+			cfg.retryAfter = d
+		}
+	}
+}
+
+// Middleware returns a middleware that serves requests through p. A request whose client goes away
+// while it is queued is dropped, one still queued when the pool stops is shed. Panics of the handler are propagated to the server, as if it ran
+// on the goroutine of the request.
+func Middleware(p *gowp.Pool, opts ...Option) func(http.Handler) http.Handler {
+	cfg := config{retryAfter: time.Second}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	retryAfter := strconv.Itoa(int((cfg.retryAfter + time.Second - 1) / time.Second))
+	stopped := p.Context().Done()
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if errors.Is(p.Healthy(), gowp.ErrSaturated) {
+				shed(w, retryAfter)
+				return
+			}
+
+			var panicked interface{}
+			f, err := p.SubmitFuture(func() error {
+				defer func() { panicked = recover() }()
+
+				next.ServeHTTP(w, r)
+
+				return nil
+			})
+			if err != nil {
+				shed(w, retryAfter)
+				return
+			}
+
+			select {
+			case <-f.Done():
+			case <-r.Context().Done():
+				if f.Cancel() {
+					return // the client is gone, nobody reads the response.
+				}
+
+				<-f.Done() // the handler is running already, it owns w until it returns.
+			case <-stopped:
+				f.Cancel() // the request is not served, unless the handler is running already.
+				<-f.Done()
+			}
+
+			if panicked != nil {
+				panic(panicked)
+			}
+
+			if err := f.Err(); errors.Is(err, gowp.ErrTaskDiscarded) || errors.Is(err, gowp.ErrTaskCanceled) {
+				shed(w, retryAfter) // the pool stopped before serving the request.
+			}
+		})
+	}
+}
+
+// shed responds to a request the pool can't take.
+func shed(w http.ResponseWriter, retryAfter string) {
+	w.Header().Set("Retry-After", retryAfter)
+	http.Error(w, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+}
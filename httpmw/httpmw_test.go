@@ -0,0 +1,160 @@
+package httpmw
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/akshaybharambe14/gowp"
+)
+
+func TestMiddleware(t *testing.T) {
+	p, _ := gowp.New(1, gowp.WithNumWorkers(1))
+
+	started, release := make(chan struct{}, 2), make(chan struct{})
+	var (
+		mu     sync.Mutex
+		served []string
+	)
+	h := Middleware(p, WithRetryAfter(1500*time.Millisecond))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/panic" {
+			panic("boom")
+		}
+
+		started <- struct{}{}
+		<-release
+
+		mu.Lock()
+		served = append(served, r.URL.Path)
+		mu.Unlock()
+
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	serve := func(ctx context.Context, path string) <-chan *httptest.ResponseRecorder {
+		out := make(chan *httptest.ResponseRecorder, 1)
+		go func() {
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil).WithContext(ctx))
+			out <- rec
+		}()
+		return out
+	}
+
+	waitQueued := func(n int) {
+		t.Helper()
+
+		deadline := time.Now().Add(time.Second)
+		for p.Stats().Queued != n {
+			if time.Now().After(deadline) {
+				t.Fatalf("%d requests queued, want %d", p.Stats().Queued, n)
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	ctx := context.Background()
+	running := serve(ctx, "/running")
+	<-started
+
+	gone, leave := context.WithCancel(ctx)
+	abandoned := serve(gone, "/abandoned")
+	waitQueued(1)
+	leave()
+	<-abandoned
+	waitQueued(0)
+
+	queued := serve(ctx, "/queued")
+	waitQueued(1)
+
+	// the worker is busy and the queue is full.
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/shed", nil))
+	if rec.Code != http.StatusServiceUnavailable || rec.Header().Get("Retry-After") != "2" {
+		t.Errorf("shed request got %d, Retry-After %q, want %d, %q", rec.Code, rec.Header().Get("Retry-After"), http.StatusServiceUnavailable, "2")
+	}
+
+	close(release)
+
+	for _, out := range []<-chan *httptest.ResponseRecorder{running, queued} {
+		if rec := <-out; rec.Code != http.StatusNoContent {
+			t.Errorf("served request got %d, want %d", rec.Code, http.StatusNoContent)
+		}
+	}
+
+	if len(served) != 2 || served[0] != "/running" || served[1] != "/queued" {
+		t.Errorf("served %v, want [/running /queued]", served)
+	}
+
+	func() {
+		defer func() {
+			if r := recover(); r != "boom" {
+				t.Errorf("recover() = %v, want the panic of the handler", r)
+			}
+		}()
+
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/panic", nil))
+	}()
+
+	if err := p.Wait(); err != nil {
+		t.Errorf("Pool.Wait() = %v, want nil", err)
+	}
+}
+
+func TestMiddleware_stopped(t *testing.T) {
+	p, _ := gowp.New(2, gowp.WithNumWorkers(1))
+
+	started, release := make(chan struct{}), make(chan struct{})
+	h := Middleware(p)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/running" {
+			close(started)
+			<-release
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	serve := func(path string) <-chan *httptest.ResponseRecorder {
+		out := make(chan *httptest.ResponseRecorder, 1)
+		go func() {
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+			out <- rec
+		}()
+		return out
+	}
+
+	running := serve("/running")
+	<-started
+
+	queued := serve("/queued")
+	deadline := time.Now().Add(time.Second)
+	for p.Stats().Queued != 1 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+
+	p.CloseWithError(nil)
+
+	select {
+	case rec := <-queued:
+		if rec.Code != http.StatusServiceUnavailable {
+			t.Errorf("queued request got %d, want %d", rec.Code, http.StatusServiceUnavailable)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("queued request not answered once the pool stopped")
+	}
+
+	close(release)
+
+	if rec := <-running; rec.Code != http.StatusNoContent {
+		t.Errorf("running request got %d, want %d", rec.Code, http.StatusNoContent)
+	}
+
+	if err := p.Wait(); !errors.Is(err, gowp.ErrPoolStopped) {
+		t.Errorf("Pool.Wait() = %v, want %v", err, gowp.ErrPoolStopped)
+	}
+}
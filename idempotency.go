@@ -0,0 +1,51 @@
+package gowp
+
+import "context"
+
+// IdempotencyStore records the tasks that have completed, by key, so that they are not executed twice,
+// see WithIdempotencyStore. It is called concurrently by the workers. A store that outlives the process,
+// e.g. a database table, lets replayed tasks be skipped after a restart.
+type IdempotencyStore interface {
+	// Done reports whether the task with the given key has completed already.
+	Done(ctx context.Context, key string) (bool, error)
+
+	// MarkDone records that the task with the given key has completed.
+	MarkDone(ctx context.Context, key string) error
+}
+
+// WithIdempotencyStore returns an Option that checks s before executing a task that has a key, see
+// TaskIdempotencyKey. A task that is done already is skipped, it is reported as successful without running.
+// Otherwise the task runs and, if it succeeds, is recorded as done. An error of s is reported as the error
+// of the task, it doesn't run if the check fails. The check and the record are not atomic, two tasks with
+// the same key running at the same time both run. Tasks submitted with SubmitAcked are not checked.
+func WithIdempotencyStore(s IdempotencyStore) Option {
+	return func(o *config) {
+		o.idempotency = s
+	}
+}
+
+// TaskIdempotencyKey returns a TaskOption that sets the key identifying the work done by the task, see
+// WithIdempotencyStore. Tasks without a key are always executed.
+func TaskIdempotencyKey(key string) TaskOption {
+	return func(j *job) {
+		j.key = key
+	}
+}
+
+// idempotent returns the task of j, skipped if the store of the pool reports it done and recorded once it succeeds.
+func (p *Pool) idempotent(j *job) Task {
+	t, key, ctx := j.fn, j.key, p.ctx
+
+	return func() error {
+		done, err := p.idempotency.Done(ctx, key)
+		if err != nil || done {
+			return err
+		}
+
+		if err := t(); err != nil {
+			return err
+		}
+
+		return p.idempotency.MarkDone(ctx, key)
+	}
+}
@@ -0,0 +1,90 @@
+package gowp
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// testStore is an IdempotencyStore in memory.
+type testStore struct {
+	mu   sync.Mutex
+	done map[string]bool
+	err  error // returned by Done, if set.
+}
+
+func (s *testStore) Done(_ context.Context, key string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.done[key], s.err
+}
+
+func (s *testStore) MarkDone(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.done[key] = true
+	return nil
+}
+
+func TestWithIdempotencyStore(t *testing.T) {
+	errBoom := errors.New("boom")
+	errStore := errors.New("store unavailable")
+
+	tests := []struct {
+		name     string
+		done     map[string]bool
+		storeErr error
+		taskErr  error
+		opts     []TaskOption
+		wantRan  bool
+		wantErr  error
+		wantDone bool // whether the key is recorded afterwards.
+	}{
+		{name: "new key", opts: []TaskOption{TaskIdempotencyKey("a")}, wantRan: true, wantDone: true},
+		{name: "done key", done: map[string]bool{"a": true}, opts: []TaskOption{TaskIdempotencyKey("a")}, wantDone: true},
+		{name: "failed task", taskErr: errBoom, opts: []TaskOption{TaskIdempotencyKey("a")}, wantRan: true, wantErr: errBoom},
+		{name: "store error", storeErr: errStore, opts: []TaskOption{TaskIdempotencyKey("a")}, wantErr: errStore},
+		{name: "no key", done: map[string]bool{"": true}, wantRan: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &testStore{done: map[string]bool{}, err: tt.storeErr}
+			for k, v := range tt.done {
+				s.done[k] = v
+			}
+
+			p, err := New(1, WithIdempotencyStore(s))
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+
+			var ran int32
+			f, err := p.SubmitFuture(func() error {
+				atomic.StoreInt32(&ran, 1)
+				return tt.taskErr
+			}, tt.opts...)
+			if err != nil {
+				t.Fatalf("SubmitFuture() error = %v", err)
+			}
+
+			if err := f.Err(); !errors.Is(err, tt.wantErr) || (tt.wantErr == nil && err != nil) {
+				t.Errorf("Future.Err() = %v, want %v", err, tt.wantErr)
+			}
+
+			_ = p.Wait()
+
+			if got := atomic.LoadInt32(&ran) == 1; got != tt.wantRan {
+				t.Errorf("task ran = %v, want %v", got, tt.wantRan)
+			}
+
+			if got := s.done["a"]; got != tt.wantDone {
+				t.Errorf("key recorded = %v, want %v", got, tt.wantDone)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package gowp
+
+import (
+	"sort"
+	"sync"
+)
+
+// inflight tracks the jobs being executed.
+type inflight struct {
+	mu   sync.Mutex
+	jobs map[*job]struct{} // guarded by mu.
+}
+
+// Snapshot returns the tasks being executed, in the order of submission, e.g. to find out what a pool
+// that seems stuck is busy with. StartedAt tells how long each of them has been running for.
+func (p *Pool) Snapshot() []TaskInfo {
+	p.inflight.mu.Lock()
+	infos := make([]TaskInfo, 0, len(p.inflight.jobs))
+	for j := range p.inflight.jobs {
+		infos = append(infos, j.info())
+	}
+	p.inflight.mu.Unlock()
+
+	sort.Slice(infos, func(a, b int) bool { return infos[a].ID < infos[b].ID })
+
+	return infos
+}
+
+func (in *inflight) add(j *job) {
+	in.mu.Lock()
+	if in.jobs == nil {
+		in.jobs = make(map[*job]struct{})
+	}
+	in.jobs[j] = struct{}{}
+	in.mu.Unlock()
+}
+
+func (in *inflight) remove(j *job) {
+	in.mu.Lock()
+	delete(in.jobs, j)
+	in.mu.Unlock()
+}
+
+func (in *inflight) len() int {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+
+	return len(in.jobs)
+}
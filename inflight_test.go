@@ -0,0 +1,48 @@
+package gowp
+
+import (
+	"context"
+	"testing"
+)
+
+func TestPool_Snapshot(t *testing.T) {
+	p := testPool(context.Background(), 2, testDefaultNumTasks, false)
+
+	if got := p.Snapshot(); len(got) != 0 {
+		t.Errorf("Pool.Snapshot() = %+v before any task, want none", got)
+	}
+
+	started := make(chan struct{}, 3)
+	release := make(chan struct{})
+	task := func() error {
+		started <- struct{}{}
+		<-release
+		return nil
+	}
+
+	names := []string{"first", "second", "queued"}
+	for _, name := range names {
+		_, _ = p.SubmitFuture(task, TaskName(name))
+	}
+
+	<-started
+	<-started
+
+	got := p.Snapshot()
+	if len(got) != 2 {
+		t.Fatalf("Pool.Snapshot() = %+v, want the 2 running tasks", got)
+	}
+
+	for i, info := range got {
+		if info.Name != names[i] || info.ID != uint64(i+1) || info.StartedAt.IsZero() {
+			t.Errorf("Pool.Snapshot()[%d] = %+v, want task %d %q with its start time", i, info, i+1, names[i])
+		}
+	}
+
+	close(release)
+	_ = p.Wait()
+
+	if got := p.Snapshot(); len(got) != 0 {
+		t.Errorf("Pool.Snapshot() = %+v after Wait, want none", got)
+	}
+}
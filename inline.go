@@ -0,0 +1,72 @@
+package gowp
+
+// WithInlineExecution returns an Option that makes the pool execute tasks on the goroutine submitting them,
+// before Submit returns, instead of on workers. Tasks submitted while a goroutine is executing tasks of the
+// pool, e.g. by a task, are queued and executed by that goroutine in turn, so that the order of execution
+// is the order of the queue. Delayed tasks are executed by the goroutine of their timer and Wait waits for them.
+//
+// It is meant for tests of code using a pool, which become deterministic, see testutil.NewSyncPool.
+// The pool has no worker: WithNumWorkers, WithWorkerBoost, WithBurst and WithAdaptiveConcurrency have no effect
+// and Resize fails.
+func WithInlineExecution() Option {
+	return func(o *config) {
+		o.inline = true
+	}
+}
+
+// runInline executes the queued jobs on the calling goroutine, unless another goroutine is at it already.
+func (p *Pool) runInline() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.inlining {
+		return // the job is picked up by the goroutine executing the queue.
+	}
+
+	p.inlining = true
+	defer func() {
+		p.inlining = false
+		p.ready.Broadcast() // Wait may be waiting for the queue to drain.
+	}()
+
+	for {
+		select {
+		case <-p.quit:
+			return // jobs left in the queue are dropped by Wait.
+		default:
+		}
+
+		if p.paused {
+			return // Resume picks up from here.
+		}
+
+		j := p.take("")
+		if j == nil {
+			return
+		}
+
+		p.mu.Unlock()
+		p.handle(j)
+		p.mu.Lock()
+	}
+}
+
+// awaitInline waits until the queued and held jobs of an inline pool have been executed, as workers do on Wait.
+func (p *Pool) awaitInline() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for {
+		select {
+		case <-p.quit:
+			return
+		default:
+		}
+
+		if p.scheduled == 0 && !p.inlining && p.queue.len() == 0 {
+			return
+		}
+
+		p.ready.Wait()
+	}
+}
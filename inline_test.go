@@ -0,0 +1,72 @@
+package gowp
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestWithInlineExecution(t *testing.T) {
+	p := testPool(context.Background(), 4, testDefaultNumTasks, false, WithInlineExecution())
+
+	var order []int
+	record := func(i int) Task {
+		return func() error {
+			order = append(order, i)
+			return nil
+		}
+	}
+
+	// tasks submitted by a task run after it, in turn.
+	_ = p.Submit(func() error {
+		_ = p.Submit(record(2))
+		_ = p.Submit(record(3))
+		order = append(order, 1)
+		return nil
+	})
+
+	if want := []int{1, 2, 3}; !reflect.DeepEqual(order, want) {
+		t.Errorf("executed %v once Submit returned, want %v", order, want)
+	}
+
+	p.Pause()
+	_ = p.Submit(record(4))
+	if len(order) != 3 {
+		t.Errorf("executed %v while paused, want no more tasks", order)
+	}
+	p.Resume()
+
+	f, err := p.SubmitFuture(testFuncWithErr)
+	if err != nil || !isDone(f) || !errors.Is(f.Err(), testErr) {
+		t.Errorf("SubmitFuture() = %v, done %v, want a completed Future", err, err == nil && isDone(f))
+	}
+
+	_, _ = p.SubmitAfter(5*time.Millisecond, record(5))
+
+	if err := p.Wait(); !errors.Is(err, testErr) {
+		t.Errorf("Pool.Wait() = %v, want %v", err, testErr)
+	}
+
+	if want := []int{1, 2, 3, 4, 5}; !reflect.DeepEqual(order, want) {
+		t.Errorf("executed %v, want %v", order, want)
+	}
+
+	if s := p.Stats(); s.Workers != 0 || s.Succeeded != 5 || s.Failed != 1 {
+		t.Errorf("Stats() = %+v, want no workers, 5 succeeded and 1 failed tasks", s)
+	}
+
+	if err := p.Resize(2); !errors.Is(err, ErrPoolClosed) {
+		t.Errorf("Pool.Resize() = %v, want %v", err, ErrPoolClosed)
+	}
+}
+
+func isDone(f *Future) bool {
+	select {
+	case <-f.Done():
+		return true
+	default:
+		return false
+	}
+}
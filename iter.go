@@ -0,0 +1,53 @@
+//go:build go1.23
+
+package gowp
+
+import (
+	"fmt"
+	"iter"
+)
+
+// SubmitSeq submits the tasks produced by seq, in order, until it is exhausted. Unlike Submit, it waits for
+// room in a full queue, so seq is consumed at the pace of the pool. It stops at the first task that can't be
+// submitted, e.g. once the pool is closed, and returns the number of tasks submitted along with the error.
+//
+//	n, err := p.SubmitSeq(func(yield func(gowp.Task) bool) {
+//		for _, f := range files {
+//			if !yield(func() error { return process(f) }) {
+//				return
+//			}
+//		}
+//	})
+func (p *Pool) SubmitSeq(seq iter.Seq[Task]) (int, error) {
+	n := 0
+	for t := range seq {
+		if err := p.submitJob(t, nil, nil, true); err != nil {
+			return n, fmt.Errorf("gowp.Pool.SubmitSeq(): %w", err)
+		}
+
+		n++
+	}
+
+	return n, nil
+}
+
+// All returns an iterator over the value and error of every task as soon as it has finished, see Results.
+// The iteration ends once the pool completes. If the loop breaks early, the remaining results are received
+// and dropped in the background, so that workers are not blocked. Like Results, it should be called before
+// submitting tasks, typically ranged over in its own goroutine while Wait is called.
+func (tp *TypedPool[T]) All() iter.Seq2[T, error] {
+	results := tp.Results()
+
+	return func(yield func(T, error) bool) {
+		for r := range results {
+			if !yield(r.Value, r.Err) {
+				go func() {
+					for range results {
+					}
+				}()
+
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,137 @@
+//go:build go1.23
+
+package gowp
+
+import (
+	"errors"
+	"sort"
+	"sync/atomic"
+	"testing"
+)
+
+func TestPool_SubmitSeq(t *testing.T) {
+	tests := []struct {
+		name    string
+		tasks   int
+		closed  bool
+		wantN   int
+		wantErr error
+	}{
+		{name: "more tasks than buffer", tasks: 20, wantN: 20},
+		{name: "empty sequence", tasks: 0, wantN: 0},
+		{name: "closed pool", tasks: 3, closed: true, wantN: 0, wantErr: ErrPoolClosed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := New(2, WithNumWorkers(2))
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+
+			if tt.closed {
+				p.Close()
+			}
+
+			var ran int64
+			seq := func(yield func(Task) bool) {
+				for i := 0; i < tt.tasks; i++ {
+					if !yield(func() error { atomic.AddInt64(&ran, 1); return nil }) {
+						return
+					}
+				}
+			}
+
+			n, err := p.SubmitSeq(seq)
+			if n != tt.wantN || !errors.Is(err, tt.wantErr) || (tt.wantErr == nil && err != nil) {
+				t.Fatalf("SubmitSeq() = %d, %v, want %d, %v", n, err, tt.wantN, tt.wantErr)
+			}
+
+			p.Close()
+			if err := p.Wait(); err != nil {
+				t.Fatalf("Wait() error = %v", err)
+			}
+
+			if got := atomic.LoadInt64(&ran); got != int64(tt.wantN) {
+				t.Errorf("ran %d tasks, want %d", got, tt.wantN)
+			}
+		})
+	}
+}
+
+func TestTypedPool_All(t *testing.T) {
+	errOdd := errors.New("odd")
+
+	tests := []struct {
+		name     string
+		tasks    int
+		breakAt  int // stop ranging after that many results, 0 to range over all of them.
+		wantVals []int
+		wantErrs int
+	}{
+		{name: "all results", tasks: 6, wantVals: []int{0, 2, 4}, wantErrs: 3},
+		{name: "break early", tasks: 6, breakAt: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tp, err := NewTyped[int](tt.tasks, WithNumWorkers(2), WithExitOnError(false))
+			if err != nil {
+				t.Fatalf("NewTyped() error = %v", err)
+			}
+
+			all := tp.All()
+			seen := make(chan struct{})
+			var vals []int
+			errs, got := 0, 0
+			go func() {
+				defer close(seen)
+				for v, err := range all {
+					got++
+					if err != nil {
+						errs++
+					} else {
+						vals = append(vals, v)
+					}
+
+					if got == tt.breakAt {
+						break
+					}
+				}
+			}()
+
+			for i := 0; i < tt.tasks; i++ {
+				i := i
+				if _, err := tp.Submit(func() (int, error) {
+					if i%2 == 1 {
+						return 0, errOdd
+					}
+					return i, nil
+				}); err != nil {
+					t.Fatalf("Submit() error = %v", err)
+				}
+			}
+
+			tp.p.Close()
+			_ = tp.Wait()
+			<-seen
+
+			if tt.breakAt > 0 {
+				if got != tt.breakAt {
+					t.Errorf("All() yielded %d results after break, want %d", got, tt.breakAt)
+				}
+				return
+			}
+
+			sort.Ints(vals)
+			if len(vals) != len(tt.wantVals) || errs != tt.wantErrs {
+				t.Fatalf("All() = %v with %d errors, want %v with %d errors", vals, errs, tt.wantVals, tt.wantErrs)
+			}
+			for i := range vals {
+				if vals[i] != tt.wantVals[i] {
+					t.Fatalf("All() = %v, want %v", vals, tt.wantVals)
+				}
+			}
+		})
+	}
+}
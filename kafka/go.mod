@@ -0,0 +1,20 @@
+module github.com/akshaybharambe14/gowp/kafka
+
+go 1.20
+
+require (
+	github.com/akshaybharambe14/gowp v0.0.0-00010101000000-000000000000
+	github.com/twmb/franz-go v1.15.4
+	github.com/twmb/franz-go/pkg/kfake v0.0.0-20231206062516-c09dc92d2db1
+)
+
+require (
+	github.com/klauspost/compress v1.16.7 // indirect
+	github.com/pierrec/lz4/v4 v4.1.19 // indirect
+	github.com/twmb/franz-go/pkg/kmsg v1.7.0 // indirect
+	golang.org/x/crypto v0.17.0 // indirect
+)
+
+// the core module is built from this repository until a release of it with the APIs used here is tagged,
+// the required version is a placeholder.
+replace github.com/akshaybharambe14/gowp => ./..
@@ -0,0 +1,335 @@
+// Package kafka processes the records of a Kafka consumer group with a gowp pool.
+//
+// It is a separate module, so that the core package doesn't depend on a Kafka client.
+//
+// Records are polled in batches. The records of a partition are handled in order, one after the other,
+// while partitions are handled concurrently. With WithKeyedDispatch, ordering is kept per record key
+// instead, so that a partition with many keys is handled concurrently too.
+//
+// Offsets are committed once the batch has been handled, and only up to the first record that failed:
+// the consumer rewinds its partition to that record, so that it is handled again by the next poll.
+// Delivery is at least once, handlers should be idempotent.
+//
+// Example:
+//	cl, _ := kgo.NewClient(append(kafka.ClientOpts(),
+//		kgo.SeedBrokers("localhost:9092"),
+//		kgo.ConsumerGroup("billing"),
+//		kgo.ConsumeTopics("orders"),
+//	)...)
+//	defer cl.Close()
+//
+//	wp, _ := gowp.New(100)
+//
+//	c := kafka.New(cl, wp, func(r *kgo.Record) error {
+//		return process(r.Value)
+//	})
+//	c.Consume(ctx)
+//
+//	err := c.Wait() // stops consuming, commits and waits for the pool.
+package kafka // import "github.com/akshaybharambe14/gowp/kafka"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/akshaybharambe14/gowp"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+type (
+	// Handler handles a record. The record and the ones after it in its partition are not committed
+	// if it returns an error.
+	Handler func(r *kgo.Record) error
+
+	// Option configures a Consumer.
+	Option func(c *Consumer)
+
+	// Consumer hands the records of a consumer group over to a pool.
+	//
+	// Zero value is not usable. Use New() to create a Consumer.
+	Consumer struct {
+		cl *kgo.Client
+		p  *gowp.Pool
+		h  Handler
+
+		keyed   bool        // see WithKeyedDispatch.
+		onError func(error) // see WithErrorHandler.
+
+		mu     sync.Mutex
+		cancel context.CancelFunc // stops the consumer. nil, if not consuming. Guarded by mu.
+		done   chan struct{}      // closed once the consumer has returned.
+	}
+
+	// lane is a sequence of records to handle in order.
+	lane struct {
+		recs   []*kgo.Record
+		failed *kgo.Record // the first record not to commit, if any.
+
+		f     *gowp.Future // follows the task handling the lane, nil if it couldn't be submitted.
+		fault *kgo.Record  // the record the handler failed on, set by the task. Read once f is done.
+	}
+
+	// partition identifies a partition of a topic.
+	partition struct {
+		topic string
+		num   int32
+	}
+)
+
+// ClientOpts returns the options a Consumer requires its client to be created with: offsets are committed
+// by the consumer and rebalances wait for the records polled to be handled. The client also needs
+// kgo.ConsumerGroup and the topics to consume.
+func ClientOpts() []kgo.Opt {
+	return []kgo.Opt{kgo.DisableAutoCommit(), kgo.BlockRebalanceOnPoll()}
+}
+
+// WithKeyedDispatch returns an Option that keeps the records in order per key rather than per partition.
+func WithKeyedDispatch() Option {
+	return func(c *Consumer) {
+		c.keyed = true
+	}
+}
+
+// WithErrorHandler returns an Option that sets the function receiving the errors of the consumer,
+// e.g. failed fetches or commits. Errors returned by the handler are not included, the pool reports them.
+// Errors are dropped by default.
+func WithErrorHandler(onError func(error)) Option {
+	return func(c *Consumer) {
+		c.onError = onError
+	}
+}
+
+// New creates a Consumer handling the records polled by cl with h on p. cl should be created with ClientOpts.
+func New(cl *kgo.Client, p *gowp.Pool, h Handler, opts ...Option) *Consumer {
+	c := &Consumer{cl: cl, p: p, h: h, onError: func(error) {}}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// Consume starts handling records with the pool, until ctx is done or Wait is called.
+// Calling Consume while consuming has no effect.
+func (c *Consumer) Consume(ctx context.Context) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.cancel != nil {
+		return
+	}
+
+	ctx, c.cancel = context.WithCancel(ctx)
+	c.done = make(chan struct{})
+
+	go c.consume(ctx)
+}
+
+// Wait stops consuming, then waits for the pool, see gowp.Pool.Wait. The records polled are handled
+// and committed first. The client is left open.
+func (c *Consumer) Wait() error {
+	c.mu.Lock()
+	cancel, done := c.cancel, c.done
+	c.mu.Unlock()
+
+	if cancel != nil {
+		cancel()
+		<-done
+	}
+
+	return c.p.Wait()
+}
+
+func (c *Consumer) consume(ctx context.Context) {
+	defer close(c.done)
+
+	for {
+		fetches := c.cl.PollFetches(ctx)
+		if fetches.IsClientClosed() {
+			return
+		}
+
+		fetches.EachError(func(topic string, num int32, err error) {
+			if !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded) {
+				c.onError(fmt.Errorf("kafka: fetching %s[%d]: %w", topic, num, err))
+			}
+		})
+
+		c.handle(fetches)
+		c.cl.AllowRebalance()
+
+		if ctx.Err() != nil {
+			return
+		}
+	}
+}
+
+// handle handles a batch of records with the pool, then commits it.
+func (c *Consumer) handle(fetches kgo.Fetches) {
+	var (
+		lanes []*lane
+		index = make(map[string]*lane) // lanes by key, reset for each partition.
+	)
+
+	fetches.EachPartition(func(ftp kgo.FetchTopicPartition) {
+		for k := range index {
+			delete(index, k)
+		}
+
+		for _, r := range ftp.Records {
+			key := ""
+			if c.keyed {
+				key = string(r.Key)
+			}
+
+			l, ok := index[key]
+			if !ok {
+				l = &lane{}
+				index[key] = l
+				lanes = append(lanes, l)
+			}
+
+			l.recs = append(l.recs, r)
+		}
+	})
+
+	if len(lanes) == 0 {
+		return
+	}
+
+	var submitted []*lane // lanes submitted, oldest first, that may still be running.
+	for _, l := range lanes {
+		l := l
+		task := func() error {
+			for _, r := range l.recs {
+				if err := c.h(r); err != nil {
+					l.fault = r
+					return err
+				}
+			}
+
+			return nil
+		}
+
+		f, err := c.p.SubmitFuture(task)
+		for errors.Is(err, gowp.ErrNoBuffer) && len(submitted) > 0 && c.await(submitted[0]) {
+			submitted = submitted[1:] // the queue was full, a lane has finished.
+			f, err = c.p.SubmitFuture(task)
+		}
+
+		if err != nil {
+			l.failed = l.recs[0]
+			c.onError(fmt.Errorf("kafka: submitting records: %w", err))
+			continue
+		}
+
+		l.f = f
+		submitted = append(submitted, l)
+	}
+
+	for _, l := range lanes {
+		if l.f != nil && !c.await(l) {
+			// the pool stopped and may never run the lane, or still be running it.
+			l.failed = l.recs[0]
+		}
+	}
+
+	c.commit(lanes)
+}
+
+// await waits for the task of l, unless the pool stops first, and reports whether it is done.
+// Once done, the records of l are committed up to the one the handler failed on, if any,
+// and none of them if the pool discarded the task before running it.
+func (c *Consumer) await(l *lane) bool {
+	select {
+	case <-l.f.Done():
+	case <-c.p.Context().Done():
+		select {
+		case <-l.f.Done():
+		default:
+			return false
+		}
+	}
+
+	switch err := l.f.Err(); {
+	case errors.Is(err, gowp.ErrTaskDiscarded), errors.Is(err, gowp.ErrTaskCanceled):
+		l.failed = l.recs[0]
+	case l.fault != nil:
+		l.failed = l.fault
+	}
+
+	return true
+}
+
+// commit commits the records of the lanes up to the first failed one of each partition,
+// and rewinds the partitions to that record.
+func (c *Consumer) commit(lanes []*lane) {
+	var (
+		last   = make(map[partition]*kgo.Record) // the last record of each partition.
+		failed = make(map[partition]*kgo.Record) // the first failed record of each partition.
+		recs   = make(map[partition][]*kgo.Record)
+	)
+
+	for _, l := range lanes {
+		for _, r := range l.recs {
+			pt := partition{r.Topic, r.Partition}
+			recs[pt] = append(recs[pt], r)
+
+			if lr, ok := last[pt]; !ok || r.Offset > lr.Offset {
+				last[pt] = r
+			}
+		}
+
+		if l.failed != nil {
+			pt := partition{l.failed.Topic, l.failed.Partition}
+			if fr, ok := failed[pt]; !ok || l.failed.Offset < fr.Offset {
+				failed[pt] = l.failed
+			}
+		}
+	}
+
+	var (
+		commit []*kgo.Record
+		rewind = make(map[string]map[int32]kgo.EpochOffset)
+	)
+
+	for pt, lr := range last {
+		fr, ok := failed[pt]
+		if !ok {
+			commit = append(commit, lr)
+			continue
+		}
+
+		// commit the record right before the failed one, if it is part of the batch.
+		var prev *kgo.Record
+		for _, r := range recs[pt] {
+			if r.Offset < fr.Offset && (prev == nil || r.Offset > prev.Offset) {
+				prev = r
+			}
+		}
+
+		if prev != nil {
+			commit = append(commit, prev)
+		}
+
+		if rewind[pt.topic] == nil {
+			rewind[pt.topic] = make(map[int32]kgo.EpochOffset)
+		}
+		rewind[pt.topic][pt.num] = kgo.EpochOffset{Epoch: fr.LeaderEpoch, Offset: fr.Offset}
+	}
+
+	if len(rewind) > 0 {
+		c.cl.SetOffsets(rewind)
+	}
+
+	if len(commit) == 0 {
+		return
+	}
+
+	// the consumer may have stopped, the records are handled anyway.
+	if err := c.cl.CommitRecords(context.Background(), commit...); err != nil {
+		c.onError(fmt.Errorf("kafka: committing offsets: %w", err))
+	}
+}
@@ -0,0 +1,220 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/akshaybharambe14/gowp"
+	"github.com/twmb/franz-go/pkg/kfake"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+func TestConsumer(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+	}{
+		{name: "per partition"},
+		{name: "per key", opts: []Option{WithKeyedDispatch()}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cluster, err := kfake.NewCluster(kfake.NumBrokers(1), kfake.SeedTopics(2, "orders"))
+			if err != nil {
+				t.Fatalf("kfake.NewCluster() error = %v", err)
+			}
+			defer cluster.Close()
+
+			producer, _ := kgo.NewClient(kgo.SeedBrokers(cluster.ListenAddrs()...))
+			defer producer.Close()
+
+			const perKey = 5
+			keys := []string{"a", "b", "c"}
+			for i := 0; i < perKey; i++ {
+				for _, k := range keys {
+					r := &kgo.Record{Topic: "orders", Key: []byte(k), Value: []byte(fmt.Sprint(i))}
+					if err := producer.ProduceSync(context.Background(), r).FirstErr(); err != nil {
+						t.Fatalf("Client.ProduceSync() error = %v", err)
+					}
+				}
+			}
+
+			cl, err := kgo.NewClient(append(ClientOpts(),
+				kgo.SeedBrokers(cluster.ListenAddrs()...),
+				kgo.ConsumerGroup("billing"),
+				kgo.ConsumeTopics("orders"),
+			)...)
+			if err != nil {
+				t.Fatalf("kgo.NewClient() error = %v", err)
+			}
+			defer cl.Close()
+
+			var (
+				mu       sync.Mutex
+				failed   bool
+				handled  = make(map[string]map[int]bool)
+				maxSeen  = make(map[string]int)
+				reorders int
+			)
+			errFailed := errors.New("failed")
+			h := func(r *kgo.Record) error {
+				k := string(r.Key)
+				var v int
+				fmt.Sscan(string(r.Value), &v)
+
+				mu.Lock()
+				defer mu.Unlock()
+
+				if m, ok := maxSeen[k]; (ok && v > m+1) || (!ok && v != 0) {
+					reorders++ // a record was skipped.
+				}
+
+				if !failed && k == "a" && v == 2 {
+					failed = true
+					return errFailed // redelivered.
+				}
+
+				if handled[k] == nil {
+					handled[k] = make(map[int]bool)
+				}
+				handled[k][v] = true
+
+				if m, ok := maxSeen[k]; !ok || v > m {
+					maxSeen[k] = v
+				}
+
+				return nil
+			}
+
+			p, _ := gowp.New(10, gowp.WithNumWorkers(4))
+			c := New(cl, p, h, tt.opts...)
+			c.Consume(context.Background())
+
+			deadline := time.Now().Add(5 * time.Second)
+			for time.Now().Before(deadline) {
+				mu.Lock()
+				n := 0
+				for _, vs := range handled {
+					n += len(vs)
+				}
+				mu.Unlock()
+
+				if n == perKey*len(keys) {
+					break
+				}
+				time.Sleep(10 * time.Millisecond)
+			}
+
+			if err := c.Wait(); !errors.Is(err, errFailed) {
+				t.Errorf("Consumer.Wait() = %v, want %v", err, errFailed)
+			}
+
+			for _, k := range keys {
+				if len(handled[k]) != perKey {
+					t.Errorf("handled %v for key %q, want %d records", handled[k], k, perKey)
+				}
+			}
+
+			if reorders > 0 {
+				t.Errorf("%d records handled out of order", reorders)
+			}
+
+			var committed int64
+			for _, ps := range cl.CommittedOffsets() {
+				for _, eo := range ps {
+					committed += eo.Offset
+				}
+			}
+
+			if want := int64(perKey * len(keys)); committed != want {
+				t.Errorf("committed %d records, want %d", committed, want)
+			}
+		})
+	}
+}
+
+func TestConsumer_poolStopped(t *testing.T) {
+	cluster, err := kfake.NewCluster(kfake.NumBrokers(1), kfake.SeedTopics(1, "orders"))
+	if err != nil {
+		t.Fatalf("kfake.NewCluster() error = %v", err)
+	}
+	defer cluster.Close()
+
+	producer, _ := kgo.NewClient(kgo.SeedBrokers(cluster.ListenAddrs()...))
+	defer producer.Close()
+
+	// offsets: a0 b0 c0 a1 b1 c1 a2 b2 c2.
+	for i := 0; i < 3; i++ {
+		for _, k := range []string{"a", "b", "c"} {
+			r := &kgo.Record{Topic: "orders", Key: []byte(k), Value: []byte(fmt.Sprint(i))}
+			if err := producer.ProduceSync(context.Background(), r).FirstErr(); err != nil {
+				t.Fatalf("Client.ProduceSync() error = %v", err)
+			}
+		}
+	}
+
+	cl, err := kgo.NewClient(append(ClientOpts(),
+		kgo.SeedBrokers(cluster.ListenAddrs()...),
+		kgo.ConsumerGroup("billing"),
+		kgo.ConsumeTopics("orders"),
+	)...)
+	if err != nil {
+		t.Fatalf("kgo.NewClient() error = %v", err)
+	}
+	defer cl.Close()
+
+	var (
+		started = make(chan struct{})
+		release = make(chan struct{})
+	)
+	h := func(r *kgo.Record) error {
+		if string(r.Key) == "a" && string(r.Value) == "0" {
+			close(started)
+			<-release
+		}
+
+		return nil
+	}
+
+	p, _ := gowp.New(10, gowp.WithNumWorkers(1))
+	c := New(cl, p, h, WithKeyedDispatch())
+	c.Consume(context.Background())
+
+	select {
+	case <-started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the records were not handled")
+	}
+
+	// stop the pool while the lanes of b and c are queued.
+	errStop := errors.New("stop")
+	p.CloseWithError(errStop)
+	<-p.Context().Done()
+	close(release)
+
+	waited := make(chan error, 1)
+	go func() { waited <- c.Wait() }()
+
+	select {
+	case err := <-waited:
+		if !errors.Is(err, errStop) {
+			t.Errorf("Consumer.Wait() = %v, want %v", err, errStop)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Consumer.Wait() did not return")
+	}
+
+	// the lanes of b and c were discarded, nothing from b0 on is committed.
+	for _, ps := range cl.CommittedOffsets() {
+		for _, eo := range ps {
+			if eo.Offset > 1 {
+				t.Errorf("committed offset %d, want at most 1", eo.Offset)
+			}
+		}
+	}
+}
@@ -0,0 +1,26 @@
+package gowp
+
+// WithLazyWorkers returns an Option that starts workers as tasks are queued, instead of all of them in New.
+// A worker is started when a task is queued while no idle worker is left to pick it, until the number of
+// workers set by WithNumWorkers, or Resize, is reached. Workers then stay until the pool completes.
+// It suits services creating many pools that are idle most of the time, as idle pools run no goroutine.
+//
+// WithShards has no effect along with it and Resize only changes the number of workers the pool may start.
+func WithLazyWorkers() Option {
+	return func(o *config) {
+		o.lazy = true
+	}
+}
+
+// grow starts a worker if queued or held jobs have no idle worker to pick them, within the target. p.mu must be held.
+// A held job needs a worker as well, Wait returns once the workers exit and they don't exit while jobs are held.
+func (p *Pool) grow() {
+	if p.workers >= p.target {
+		return
+	}
+
+	if p.queue.len() > p.idle || (p.workers == 0 && p.scheduled > 0) {
+		p.workers++
+		p.spawn(false)
+	}
+}
@@ -0,0 +1,67 @@
+package gowp
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWithLazyWorkers(t *testing.T) {
+	tests := []struct {
+		name        string
+		run         func(t *testing.T, p *Pool)
+		wantWorkers int // once the tasks of run are submitted.
+	}{
+		{name: "idle", run: func(t *testing.T, p *Pool) {
+			if err := p.Healthy(); err != nil {
+				t.Errorf("Pool.Healthy() = %v, want nil", err)
+			}
+
+			if err := p.Resize(2); err != nil {
+				t.Errorf("Pool.Resize() = %v, want nil", err)
+			}
+		}},
+		{name: "busy", wantWorkers: 3, run: func(t *testing.T, p *Pool) {
+			release := make(chan struct{})
+			t.Cleanup(func() { close(release) })
+
+			for i := 0; i < 5; i++ {
+				_ = p.Submit(func() error { <-release; return nil })
+			}
+		}},
+		{name: "held", wantWorkers: 1, run: func(t *testing.T, p *Pool) {
+			_, _ = p.SubmitAfter(time.Millisecond, func() error { return nil })
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, _ := New(10, WithNumWorkers(3), WithLazyWorkers())
+			if w := p.Stats().Workers; w != 0 {
+				t.Errorf("Stats().Workers = %d before any task, want 0", w)
+			}
+
+			tt.run(t, p)
+
+			if w := p.Stats().Workers; w != tt.wantWorkers {
+				t.Errorf("Stats().Workers = %d, want %d", w, tt.wantWorkers)
+			}
+
+			done := make(chan error, 1)
+			go func() { done <- p.Wait() }()
+
+			select {
+			case <-done:
+				if tt.wantWorkers == 3 {
+					t.Error("Pool.Wait() returned while tasks were running")
+				}
+			case <-time.After(50 * time.Millisecond):
+				if tt.wantWorkers != 3 {
+					t.Error("Pool.Wait() didn't return")
+				}
+			}
+
+			if s := p.Stats(); tt.name == "held" && s.Succeeded != 1 {
+				t.Errorf("Stats().Succeeded = %d, want the held task to run", s.Succeeded)
+			}
+		})
+	}
+}
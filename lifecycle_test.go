@@ -0,0 +1,152 @@
+package gowp
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"sync"
+	"testing"
+	"time"
+)
+
+// lifecycle runs the operations encoded by ops on a pool from several goroutines at once, then waits for
+// the pool and checks that it has settled: Wait returns, every Future is done and every queued task is accounted for.
+// Run it with -race, interleavings of submissions with Close, Wait and context cancellation are the target.
+func lifecycle(t *testing.T, ops []byte) {
+	const goroutines = 4
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	opts := []Option{WithNumWorkers(2), WithContext(ctx)}
+	if len(ops) > 0 {
+		opts = append(opts, WithExitOnError(ops[0]&1 == 1), WithShards(int(ops[0]>>1&3)))
+		if ops[0]&8 != 0 {
+			opts = append(opts, WithLazyWorkers())
+		}
+	}
+
+	p, err := New(4, opts...)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	errTask := errors.New("task failed")
+
+	var (
+		mu   sync.Mutex
+		futs []*Future
+	)
+	keep := func(f *Future, err error) {
+		if err == nil {
+			mu.Lock()
+			futs = append(futs, f)
+			mu.Unlock()
+		}
+	}
+
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+
+			for i := g; i < len(ops); i += goroutines {
+				switch ops[i] % 10 {
+				case 0:
+					_ = p.Submit(func() error { return nil })
+				case 1:
+					keep(p.SubmitFuture(func() error { return errTask }))
+				case 2:
+					f, err := p.SubmitFuture(func() error { time.Sleep(time.Millisecond); return nil })
+					if err == nil {
+						f.Cancel()
+					}
+					keep(f, err)
+				case 3:
+					keep(p.SubmitAfter(time.Millisecond, func() error { return nil }))
+				case 4:
+					p.Close()
+				case 5:
+					cancel()
+				case 6:
+					_ = p.Wait()
+				case 7:
+					p.Pause()
+					p.Resume()
+				case 8:
+					_ = p.Resize(int(ops[i]%3) + 1)
+				case 9:
+					_ = p.Stats()
+				}
+			}
+		}(g)
+	}
+
+	wg.Wait()
+
+	waited := make(chan error, 1)
+	go func() { waited <- p.Wait() }()
+
+	select {
+	case err = <-waited:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Pool.Wait() didn't return for ops %v", ops)
+	}
+
+	if again := p.Wait(); !errors.Is(again, errors.Unwrap(err)) {
+		t.Errorf("Pool.Wait() = %v after %v", again, err)
+	}
+
+	if err != nil && !errors.Is(err, errTask) && !errors.Is(err, context.Canceled) {
+		t.Errorf("Pool.Wait() = %v, want nil, %v or %v", err, errTask, context.Canceled)
+	}
+
+	if err := p.Submit(func() error { return nil }); !errors.Is(err, ErrPoolClosed) {
+		t.Errorf("Pool.Submit() after Wait = %v, want %v", err, ErrPoolClosed)
+	}
+
+	for _, f := range futs {
+		select {
+		case <-f.Done():
+		default:
+			t.Fatalf("a Future isn't done after Wait for ops %v", ops)
+		}
+	}
+
+	// held tasks are counted as submitted once queued, but as cancelled or discarded even if they never were.
+	s := p.Stats()
+	if settled := s.Succeeded + s.Failed + s.Ignored + s.Canceled + s.Discarded; settled < s.Submitted {
+		t.Errorf("%d tasks settled out of %d submitted for ops %v: %+v", settled, s.Submitted, ops, s)
+	}
+}
+
+func FuzzLifecycle(f *testing.F) {
+	f.Add([]byte{0, 1, 2, 3, 4, 6})
+	f.Add([]byte{1, 1, 5, 0, 0, 0, 3, 3})
+	f.Add([]byte{7, 8, 0, 2, 9, 4, 0, 6, 1})
+	f.Add([]byte{3, 3, 3, 3, 6, 0, 0, 5})
+
+	f.Fuzz(func(t *testing.T, ops []byte) {
+		if len(ops) > 256 {
+			ops = ops[:256]
+		}
+
+		lifecycle(t, ops)
+	})
+}
+
+func TestLifecycleStress(t *testing.T) {
+	runs := 500
+	if testing.Short() {
+		runs = 50
+	}
+
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < runs; i++ {
+		ops := make([]byte, rnd.Intn(64))
+		rnd.Read(ops)
+
+		lifecycle(t, ops)
+	}
+}
@@ -0,0 +1,67 @@
+package gowp
+
+import (
+	"runtime/metrics"
+	"time"
+)
+
+// memoryThrottle pauses the dispatch of tasks under memory pressure, see WithMemoryThrottle.
+type memoryThrottle struct {
+	high, low uint64
+	interval  time.Duration
+	read      func() uint64 // returns the memory used by the process.
+}
+
+// WithMemoryThrottle returns an Option that protects the process from running out of memory under a large
+// backlog: once the memory used by the Go runtime reaches high bytes, the workers stop picking new tasks, like
+// with Pause, until it goes back below low bytes. The memory is counted like GOMEMLIMIT does, i.e. all the memory
+// mapped by the runtime minus what it has returned to the system, and sampled every interval. Tasks can still be
+// submitted meanwhile, see Purge to shed them. Stats reports whether the pool is throttled. A high threshold
+// close to the limit of the process, e.g. 90% of debug.SetMemoryLimit(-1), leaves room for the running tasks.
+// It has no effect along with WithInlineExecution.
+//
+// high should be greater than zero, low should not exceed high and interval should be greater than zero,
+// otherwise ErrInvalidMemoryThrottle will be returned on Pool initialization.
+func WithMemoryThrottle(high, low uint64, interval time.Duration) Option {
+	return func(o *config) {
+		o.memory = &memoryThrottle{high: high, low: low, interval: interval, read: memoryInUse}
+	}
+}
+
+// throttleMemory pauses and resumes the dispatch of tasks as per mt, until the pool stops.
+func (p *Pool) throttleMemory(mt memoryThrottle) {
+	t := p.clock.NewTicker(mt.interval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-p.quit:
+			return
+		case <-p.exitFromErrG:
+			return
+		case <-t.C():
+			used := mt.read()
+
+			p.mu.Lock()
+			switch {
+			case !p.throttled && used >= mt.high:
+				p.throttled = true
+			case p.throttled && used < mt.low:
+				p.throttled = false
+				p.ready.Broadcast()
+			}
+			p.mu.Unlock()
+		}
+	}
+}
+
+// memoryInUse returns the memory used by the Go runtime, as accounted for by GOMEMLIMIT.
+func memoryInUse() uint64 {
+	samples := []metrics.Sample{
+		{Name: "/memory/classes/total:bytes"},
+		{Name: "/memory/classes/heap/released:bytes"},
+	}
+	metrics.Read(samples)
+
+	return samples[0].Value.Uint64() - samples[1].Value.Uint64()
+}
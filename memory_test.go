@@ -0,0 +1,71 @@
+package gowp
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWithMemoryThrottle(t *testing.T) {
+	var used uint64
+	fake := func(o *config) {
+		o.memory.read = func() uint64 { return atomic.LoadUint64(&used) }
+	}
+
+	p := testPool(context.Background(), 1, testDefaultNumTasks, false, WithMemoryThrottle(100, 50, time.Millisecond), fake)
+
+	steps := []struct {
+		name          string
+		used          uint64
+		wantThrottled bool
+	}{
+		{name: "under the threshold", used: 10},
+		{name: "high reached", used: 100, wantThrottled: true},
+		{name: "above low", used: 60, wantThrottled: true},
+		{name: "below low", used: 40},
+	}
+
+	var ran int32
+	for _, s := range steps {
+		t.Run(s.name, func(t *testing.T) {
+			atomic.StoreUint64(&used, s.used)
+			time.Sleep(20 * time.Millisecond) // a few samples.
+
+			if got := p.Stats().Throttled; got != s.wantThrottled {
+				t.Fatalf("Stats().Throttled = %v, want %v", got, s.wantThrottled)
+			}
+
+			before := atomic.LoadInt32(&ran)
+			f, _ := p.SubmitFuture(func() error { atomic.AddInt32(&ran, 1); return nil })
+			if s.wantThrottled {
+				time.Sleep(10 * time.Millisecond)
+				if atomic.LoadInt32(&ran) != before {
+					t.Fatal("a task ran while the pool was throttled")
+				}
+				return
+			}
+
+			_ = f.Err()
+		})
+	}
+
+	if err := p.Wait(); err != nil {
+		t.Fatalf("Pool.Wait() error = %v", err)
+	}
+
+	if got := atomic.LoadInt32(&ran); got != int32(len(steps)) {
+		t.Errorf("%d tasks ran, want %d", got, len(steps))
+	}
+
+	if _, err := New(1, WithMemoryThrottle(10, 20, time.Second)); !errors.Is(err, ErrInvalidMemoryThrottle) {
+		t.Errorf("New() error = %v, want %v", err, ErrInvalidMemoryThrottle)
+	}
+}
+
+func TestMemoryInUse(t *testing.T) {
+	if memoryInUse() == 0 {
+		t.Error("memoryInUse() = 0, want the memory of the test binary")
+	}
+}
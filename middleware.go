@@ -0,0 +1,22 @@
+package gowp
+
+// WithTaskMiddleware returns an Option that wraps every task submitted to the pool with mw, e.g. to recover
+// panics, log or inject faults, see chaos.WithChaos. Tasks are wrapped when they are queued, delayed tasks once
+// their delay has elapsed. The option can be repeated, the first middleware is the outermost one.
+// A nil mw is ignored.
+func WithTaskMiddleware(mw func(Task) Task) Option {
+	return func(o *config) {
+		if mw != nil {
+			o.middleware = append(o.middleware, mw)
+		}
+	}
+}
+
+// wrap applies the middlewares of the pool to t.
+func (p *Pool) wrap(t Task) Task {
+	for i := len(p.middleware) - 1; i >= 0; i-- {
+		t = p.middleware[i](t)
+	}
+
+	return t
+}
@@ -0,0 +1,60 @@
+package gowp
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWithTaskMiddleware(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		trace []string
+	)
+	record := func(s string) {
+		mu.Lock()
+		defer mu.Unlock()
+		trace = append(trace, s)
+	}
+	named := func(name string) func(Task) Task {
+		return func(t Task) Task {
+			return func() error {
+				record(name)
+				return t()
+			}
+		}
+	}
+
+	tests := []struct {
+		name   string
+		submit func(p *Pool) error
+		want   []string
+	}{
+		{name: "queued", submit: func(p *Pool) error {
+			return p.Submit(func() error { record("task"); return nil })
+		}, want: []string{"outer", "inner", "task"}},
+		{name: "delayed", submit: func(p *Pool) error {
+			_, err := p.SubmitAfter(time.Millisecond, func() error { record("task"); return nil })
+			return err
+		}, want: []string{"outer", "inner", "task"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trace = nil
+			p, _ := New(1, WithNumWorkers(1), WithTaskMiddleware(named("outer")), WithTaskMiddleware(nil), WithTaskMiddleware(named("inner")))
+
+			if err := tt.submit(p); err != nil {
+				t.Fatalf("submit error = %v", err)
+			}
+
+			if err := p.Wait(); err != nil {
+				t.Errorf("Pool.Wait() = %v, want nil", err)
+			}
+
+			if !reflect.DeepEqual(trace, tt.want) {
+				t.Errorf("trace = %v, want %v", trace, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,24 @@
+module github.com/akshaybharambe14/gowp/natsworker
+
+go 1.20
+
+require (
+	github.com/akshaybharambe14/gowp v0.0.0-00010101000000-000000000000
+	github.com/nats-io/nats-server/v2 v2.10.7
+	github.com/nats-io/nats.go v1.31.0
+)
+
+require (
+	github.com/klauspost/compress v1.17.4 // indirect
+	github.com/minio/highwayhash v1.0.2 // indirect
+	github.com/nats-io/jwt/v2 v2.5.3 // indirect
+	github.com/nats-io/nkeys v0.4.6 // indirect
+	github.com/nats-io/nuid v1.0.1 // indirect
+	golang.org/x/crypto v0.16.0 // indirect
+	golang.org/x/sys v0.15.0 // indirect
+	golang.org/x/time v0.5.0 // indirect
+)
+
+// the core module is built from this repository until a release of it with the APIs used here is tagged,
+// the required version is a placeholder.
+replace github.com/akshaybharambe14/gowp => ./..
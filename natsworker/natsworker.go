@@ -0,0 +1,189 @@
+// Package natsworker feeds the messages of a NATS subject into a gowp pool.
+//
+// It is a separate module, so that the core package doesn't depend on the NATS client.
+//
+// The number of messages handed to the pool and not handled yet is bounded, see WithMaxInFlight. Once
+// the bound is reached, the subscription stops delivering messages and NATS buffers them, as per
+// its pending limits. JetStream messages are acknowledged once handled: Ack if the handler returned nil,
+// Nak otherwise, so that they are redelivered. Messages of core NATS subscriptions are not acknowledged.
+//
+// Example:
+//	nc, _ := nats.Connect(nats.DefaultURL)
+//	wp, _ := gowp.New(100)
+//
+//	w, err := natsworker.Subscribe(nc, "orders.created", wp, func(msg *nats.Msg) error {
+//		return process(msg.Data)
+//	}, natsworker.WithQueueGroup("billing"))
+//	if err != nil {
+//		// handle the error.
+//	}
+//
+//	err = w.Wait() // drains the subscription and waits for the pool.
+package natsworker // import "github.com/akshaybharambe14/gowp/natsworker"
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/akshaybharambe14/gowp"
+	"github.com/nats-io/nats.go"
+)
+
+type (
+	// Handler handles a message. The message is Nak'ed if it returns an error, for JetStream to redeliver it.
+	Handler func(msg *nats.Msg) error
+
+	// Option configures a Worker.
+	Option func(w *Worker)
+
+	// Worker hands messages over to a pool.
+	//
+	// Zero value is not usable. Use New() or Subscribe() to create a Worker.
+	Worker struct {
+		p       *gowp.Pool
+		h       Handler
+		queue   string      // see WithQueueGroup.
+		onError func(error) // see WithErrorHandler.
+		slots   chan struct{}
+
+		sub    *nats.Subscription // nil, if created by New.
+		closed chan struct{}      // closed once sub is drained or unsubscribed.
+	}
+)
+
+// WithQueueGroup returns an Option that makes Subscribe join the given queue group,
+// so that each message is handled by a single member of the group.
+func WithQueueGroup(queue string) Option {
+	return func(w *Worker) {
+		w.queue = queue
+	}
+}
+
+// WithMaxInFlight returns an Option that bounds the number of messages handed to the pool and not handled yet.
+// It defaults to the number of workers of the pool.
+func WithMaxInFlight(n int) Option {
+	return func(w *Worker) {
+		if n > 0 {
+			w.slots = make(chan struct{}, n)
+		}
+	}
+}
+
+// WithErrorHandler returns an Option that sets the function receiving the errors of the worker, e.g. messages
+// the pool refused or failed acknowledgements. Errors returned by the handler are not included, the pool reports
+// them. Errors are dropped by default.
+func WithErrorHandler(onError func(error)) Option {
+	return func(w *Worker) {
+		w.onError = onError
+	}
+}
+
+// New creates a Worker handling messages with h on p. Pass Worker.Handle to a subscription of your own,
+// e.g. a JetStream one with nats.ManualAck.
+func New(p *gowp.Pool, h Handler, opts ...Option) *Worker {
+	w := &Worker{p: p, h: h, onError: func(error) {}}
+	for _, opt := range opts {
+		opt(w)
+	}
+
+	if w.slots == nil {
+		n := p.Stats().Workers
+		if n <= 0 {
+			n = 1
+		}
+
+		w.slots = make(chan struct{}, n)
+	}
+
+	return w
+}
+
+// Subscribe subscribes to subject on nc and hands the messages over to p.
+func Subscribe(nc *nats.Conn, subject string, p *gowp.Pool, h Handler, opts ...Option) (*Worker, error) {
+	w := New(p, h, opts...)
+	w.closed = make(chan struct{})
+
+	var err error
+	if w.queue != "" {
+		w.sub, err = nc.QueueSubscribe(subject, w.queue, w.Handle)
+	} else {
+		w.sub, err = nc.Subscribe(subject, w.Handle)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("natsworker.Subscribe(): %w", err)
+	}
+
+	w.sub.SetClosedHandler(func(string) { close(w.closed) })
+
+	return w, nil
+}
+
+// Handle submits msg to the pool, it is a nats.MsgHandler. It blocks while the maximum number of messages
+// is in flight, which holds back the subscription.
+func (w *Worker) Handle(msg *nats.Msg) {
+	w.slots <- struct{}{}
+
+	f, err := w.p.SubmitFuture(func() error {
+		err := w.h(msg)
+		w.settle(msg, err)
+
+		return err
+	})
+	if err != nil {
+		<-w.slots
+		w.onError(fmt.Errorf("natsworker: submitting a message: %w", err))
+		w.settle(msg, err) // redelivered to another member of the group, if any.
+
+		return
+	}
+
+	go w.release(msg, f)
+}
+
+// release frees the slot of msg once its task is done, or as soon as the pool stops since the task may then be
+// discarded without running. A discarded message is Nak'ed, for JetStream to redeliver it.
+func (w *Worker) release(msg *nats.Msg, f *gowp.Future) {
+	select {
+	case <-f.Done():
+		<-w.slots
+	case <-w.p.Context().Done():
+		<-w.slots
+		<-f.Done() // completes by the time the pool is waited for.
+	}
+
+	if err := f.Err(); errors.Is(err, gowp.ErrTaskDiscarded) || errors.Is(err, gowp.ErrTaskCanceled) {
+		w.settle(msg, err)
+	}
+}
+
+// Wait drains the subscription created by Subscribe, so that the messages delivered already are handled,
+// then waits for the pool, see gowp.Pool.Wait.
+func (w *Worker) Wait() error {
+	if w.sub != nil {
+		// Drain returns right away, the closed handler is called once the subscription is drained.
+		if err := w.sub.Drain(); err == nil {
+			<-w.closed
+		} else if !errors.Is(err, nats.ErrConnectionClosed) && !errors.Is(err, nats.ErrBadSubscription) {
+			w.onError(fmt.Errorf("natsworker: draining the subscription: %w", err))
+		}
+	}
+
+	return w.p.Wait()
+}
+
+// settle acknowledges a JetStream message as per the error of its handling.
+func (w *Worker) settle(msg *nats.Msg, err error) {
+	if _, mdErr := msg.Metadata(); mdErr != nil {
+		return // not a JetStream message.
+	}
+
+	ack := msg.Ack
+	if err != nil {
+		ack = msg.Nak
+	}
+
+	if err := ack(); err != nil {
+		w.onError(fmt.Errorf("natsworker: acknowledging a message: %w", err))
+	}
+}
@@ -0,0 +1,202 @@
+package natsworker
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/akshaybharambe14/gowp"
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+)
+
+func testServer(t *testing.T) *nats.Conn {
+	t.Helper()
+
+	srv, err := server.NewServer(&server.Options{Host: "127.0.0.1", Port: -1, NoSigs: true, JetStream: true, StoreDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("server.NewServer() error = %v", err)
+	}
+
+	go srv.Start()
+	if !srv.ReadyForConnections(5 * time.Second) {
+		t.Fatal("NATS server not ready")
+	}
+	t.Cleanup(srv.Shutdown)
+
+	nc, err := nats.Connect(srv.ClientURL())
+	if err != nil {
+		t.Fatalf("nats.Connect() error = %v", err)
+	}
+	t.Cleanup(nc.Close)
+
+	return nc
+}
+
+func TestSubscribe(t *testing.T) {
+	nc := testServer(t)
+
+	var (
+		mu       sync.Mutex
+		got      []string
+		inFlight int32
+		maxSeen  int32
+	)
+	h := func(msg *nats.Msg) error {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+
+		for {
+			m := atomic.LoadInt32(&maxSeen)
+			if n <= m || atomic.CompareAndSwapInt32(&maxSeen, m, n) {
+				break
+			}
+		}
+
+		time.Sleep(time.Millisecond)
+
+		mu.Lock()
+		got = append(got, string(msg.Data))
+		mu.Unlock()
+
+		return nil
+	}
+
+	p, _ := gowp.New(100, gowp.WithNumWorkers(4))
+	w, err := Subscribe(nc, "tasks", p, h, WithQueueGroup("workers"), WithMaxInFlight(2))
+	if err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+
+	for _, s := range []string{"a", "b", "c", "d", "e"} {
+		_ = nc.Publish("tasks", []byte(s))
+	}
+	_ = nc.Flush()
+
+	if err := w.Wait(); err != nil {
+		t.Errorf("Worker.Wait() = %v, want nil", err)
+	}
+
+	sort.Strings(got)
+	if want := "[a b c d e]"; fmt.Sprint(got) != want {
+		t.Errorf("handled %v, want %v", got, want)
+	}
+
+	if maxSeen > 2 {
+		t.Errorf("%d messages in flight, want at most 2", maxSeen)
+	}
+
+	if _, err := Subscribe(nc, "", p, h); err == nil {
+		t.Error("Subscribe() with an invalid subject error = nil, want an error")
+	}
+}
+
+func TestWorker_Handle_jetStream(t *testing.T) {
+	nc := testServer(t)
+
+	js, _ := nc.JetStream()
+	if _, err := js.AddStream(&nats.StreamConfig{Name: "TASKS", Subjects: []string{"tasks"}}); err != nil {
+		t.Fatalf("JetStream.AddStream() error = %v", err)
+	}
+
+	var attempts int32
+	errFailed := errors.New("failed")
+	h := func(msg *nats.Msg) error {
+		if atomic.AddInt32(&attempts, 1) == 1 {
+			return errFailed // Nak'ed, then redelivered.
+		}
+		return nil
+	}
+
+	p, _ := gowp.New(10, gowp.WithNumWorkers(2))
+	w := New(p, h)
+
+	sub, err := js.Subscribe("tasks", w.Handle, nats.ManualAck(), nats.AckWait(time.Minute))
+	if err != nil {
+		t.Fatalf("JetStream.Subscribe() error = %v", err)
+	}
+
+	if _, err := js.Publish("tasks", []byte("a")); err != nil {
+		t.Fatalf("JetStream.Publish() error = %v", err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if info, err := sub.ConsumerInfo(); err == nil && info.AckFloor.Consumer == 1 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	_ = sub.Unsubscribe()
+
+	if err := w.Wait(); !errors.Is(err, errFailed) {
+		t.Errorf("Worker.Wait() = %v, want %v", err, errFailed)
+	}
+
+	if n := atomic.LoadInt32(&attempts); n != 2 {
+		t.Errorf("handled %d times, want 2", n)
+	}
+}
+
+func TestWorker_Handle_closed(t *testing.T) {
+	p, _ := gowp.New(1)
+	_ = p.Wait()
+
+	var errs []error
+	w := New(p, func(*nats.Msg) error { return nil }, WithErrorHandler(func(err error) { errs = append(errs, err) }))
+
+	w.Handle(&nats.Msg{Subject: "tasks"})
+
+	if len(errs) != 1 || !errors.Is(errs[0], gowp.ErrPoolClosed) {
+		t.Errorf("errors = %v, want [%v]", errs, gowp.ErrPoolClosed)
+	}
+}
+
+func TestWorker_Handle_stopped(t *testing.T) {
+	p, _ := gowp.New(3, gowp.WithNumWorkers(1))
+
+	var (
+		started = make(chan struct{})
+		release = make(chan struct{})
+		once    sync.Once
+		errs    = make(chan error, 1)
+	)
+	w := New(p, func(*nats.Msg) error {
+		once.Do(func() { close(started) })
+		<-release
+		return nil
+	}, WithMaxInFlight(2), WithErrorHandler(func(err error) { errs <- err }))
+
+	w.Handle(&nats.Msg{Subject: "tasks"})
+	<-started
+	w.Handle(&nats.Msg{Subject: "tasks"}) // queued, then discarded.
+
+	errStop := errors.New("stop")
+	p.CloseWithError(errStop)
+
+	handled := make(chan struct{})
+	go func() {
+		w.Handle(&nats.Msg{Subject: "tasks"})
+		close(handled)
+	}()
+
+	select {
+	case <-handled:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Worker.Handle() blocked after the pool stopped")
+	}
+	close(release)
+
+	if err := <-errs; !errors.Is(err, gowp.ErrPoolClosed) {
+		t.Errorf("error = %v, want %v", err, gowp.ErrPoolClosed)
+	}
+
+	if err := p.Wait(); !errors.Is(err, errStop) {
+		t.Errorf("Pool.Wait() = %v, want %v", err, errStop)
+	}
+}
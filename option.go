@@ -1,15 +1,67 @@
 package gowp
 
-import "context"
+import (
+	"context"
+	"errors"
+	"time"
+)
 
 type config struct {
 	ctx        context.Context
 	numWorkers int
 	exitOnErr  bool
+	weights    map[string]int
+	fair       bool
+	hooks      []Hooks
+	hookErrs   func(error)
+	classify   func(error) Severity
+	limiter    Limiter
+	ordered    bool
+	boost      *boostPolicy
+	burst      *burstBucket
+	adaptive   *adaptiveLimit
+	gc         *gcLimit
+	autoscale  *autoscaler
+	slowLog    *slowLog
+	capacity   *capacity
+	classes    map[string]int
+	tenants    *tenantQueue
+	breaker    *circuitBreaker
+	saturation *saturationLimit
+	stall      *stallDetector
+	memory     *memoryThrottle
+	redelivery redelivery
+	retries    *retryBudget
+	backoff    Backoff
+	propagate  bool
+	policy     SchedulingPolicy
+	maxAge     time.Duration
+	maxErrors  int
+	queueSize  int
+	window     time.Duration
+
+	firstSuccess bool
+	idempotency  IdempotencyStore
+	cache        *cacheSetting
+	inline       bool
+	clock        Clock
+	middleware   []func(Task) Task
+	sinks        []func(Event)
+	onProgress   func(done, total int)
+	shards       int
+	lazy         bool
+	recovers     bool
+	lockThreads  bool
+	requeue      bool
+	prestart     int
+	profile      Profile
 }
 
 type Option func(o *config)
 
+// TaskOption configures a single task, see Pool.SubmitFuture.
+type TaskOption func(j *job)
+
 // WithContext returns an Option that sets the context for the pool.
 // If the context is canceled the pool will be closed.
 func WithContext(ctx context.Context) Option {
@@ -26,6 +78,16 @@ func WithNumWorkers(numWorkers int) Option {
 	}
 }
 
+// WithQueueSize returns an Option that bounds the queue to n tasks, instead of the task count the pool is
+// created with, which then only tells how many tasks are expected, see Progress. It is meant for pools that
+// run indefinitely, see NewLongLived. Zero keeps the default, a negative n results in ErrInvalidBuffer on
+// Pool initialization.
+func WithQueueSize(n int) Option {
+	return func(o *config) {
+		o.queueSize = n
+	}
+}
+
 // WithExitOnError returns an Option that sets the exitOnErr for the pool.
 // If the exitOnErr is true, the pool will be closed when the first error is received.
 func WithExitOnError(exitOnErr bool) Option {
@@ -34,14 +96,252 @@ func WithExitOnError(exitOnErr bool) Option {
 	}
 }
 
+// WithMaxErrors returns an Option that closes the pool once n tasks have failed.
+// Unlike WithExitOnError, the pool keeps going through occasional failures. Wait returns the first error.
+// Zero, the default, means no limit. A negative n results in ErrInvalidMaxErrors on Pool initialization.
+// It is ignored along with WithFirstSuccess.
+func WithMaxErrors(n int) Option {
+	return func(o *config) {
+		o.maxErrors = n
+	}
+}
+
+// WithErrorClassifier returns an Option that sets the function deciding the Severity of the errors
+// returned by tasks. It lets the pool go on despite expected errors and stop on unrecoverable ones,
+// instead of treating all the errors alike. Futures and hooks still receive the errors unchanged.
+// classify is called by the workers, so it should be safe for concurrent use.
+func WithErrorClassifier(classify func(error) Severity) Option {
+	return func(o *config) {
+		o.classify = classify
+	}
+}
+
+// WithFirstSuccess returns an Option that stops the pool as soon as any task succeeds, i.e. returns nil.
+// Tasks that haven't started by then are discarded and Wait reports success. Errors don't stop the pool
+// in this mode, WithExitOnError is ignored. If no task succeeds, Wait returns the first error.
+// This is useful to query several replicas and take the fastest answer.
+func WithFirstSuccess() Option {
+	return func(o *config) {
+		o.firstSuccess = true
+	}
+}
+
+// WithWeightedRandomDispatch returns an Option that splits the queue of the pool into labeled sub-queues.
+// Workers pick the next task from a random non-empty sub-queue, with a probability proportional to its weight.
+// Tasks are labeled with TaskLabel, unlabeled tasks have the empty label. Submitting a task whose label
+// is not present in weights fails with ErrUnknownLabel. Weights should be greater than zero.
+func WithWeightedRandomDispatch(weights map[string]int) Option {
+	return func(o *config) {
+		o.weights, o.fair = weights, false
+	}
+}
+
+// WithWeightedFairQueuing returns an Option that splits the queue of the pool into labeled sub-queues, like
+// WithWeightedRandomDispatch, but workers pick from the non-empty sub-queues in turn, proportionally to their
+// weights, e.g. with weights of 7 and 3, 7 out of every 10 tasks come from the first sub-queue, interleaved with
+// the ones of the second. Unlike random dispatch, the shares hold over short runs of tasks too.
+func WithWeightedFairQueuing(weights map[string]int) Option {
+	return func(o *config) {
+		o.weights, o.fair = weights, true
+	}
+}
+
+// WithOrderedResults returns an Option that makes a TypedPool deliver results in submission order,
+// even though tasks are executed in parallel. Results of tasks that finish early are held back
+// until the results of all the tasks submitted before them have been delivered.
+// It has no effect on a Pool.
+func WithOrderedResults() Option {
+	return func(o *config) {
+		o.ordered = true
+	}
+}
+
+// TaskLabel returns a TaskOption that puts the task in the sub-queue with the given label.
+// See WithWeightedRandomDispatch and WithWeightedFairQueuing.
+func TaskLabel(label string) TaskOption {
+	return func(j *job) {
+		j.label = label
+	}
+}
+
+// TaskName returns a TaskOption that names the task, e.g. after the item it processes. The name is reported
+// along with the errors of the task, see TaskError, and to hooks.
+func TaskName(name string) TaskOption {
+	return func(j *job) {
+		j.name = name
+	}
+}
+
+// validate checks every setting and returns the errors of all the invalid ones, joined.
 func (o *config) validate() error {
+	var errs []error
+
+	if o.profile != 0 && o.profile.Workers() == 0 {
+		errs = append(errs, ErrInvalidProfile)
+	}
+
 	if o.numWorkers <= 0 {
-		return ErrInvalidWorkerCnt
+		errs = append(errs, ErrInvalidWorkerCnt)
 	}
 
 	if o.ctx == nil {
-		return ErrNilContext
+		errs = append(errs, ErrNilContext)
+	}
+
+	if o.clock == nil {
+		errs = append(errs, ErrNilClock)
+	}
+
+	if o.boost != nil && (o.boost.threshold <= 0 || o.boost.ceiling < o.numWorkers) {
+		errs = append(errs, ErrInvalidBoost)
+	}
+
+	if o.burst != nil && (o.burst.refill <= 0 || o.burst.ceiling < o.numWorkers) {
+		errs = append(errs, ErrInvalidBurst)
+	}
+
+	if o.adaptive != nil && (o.adaptive.min <= 0 || o.adaptive.min > o.numWorkers || o.adaptive.tolerance <= 1) {
+		errs = append(errs, ErrInvalidAdaptive)
+	}
+
+	if o.gc != nil && (o.gc.min <= 0 || o.gc.min > o.numWorkers || o.gc.threshold <= 0 || o.gc.threshold >= 1) {
+		errs = append(errs, ErrInvalidGCLimit)
+	}
+
+	if a := o.autoscale; a != nil && (a.target <= 0 || a.percentile <= 0 || a.percentile > 1 || a.min <= 0 ||
+		a.min > o.numWorkers || o.numWorkers > a.max) {
+		errs = append(errs, ErrInvalidAutoscaler)
+	}
+
+	if o.slowLog != nil && (o.slowLog.threshold <= 0 || o.slowLog.rate <= 0 || o.slowLog.rate > 1) {
+		errs = append(errs, ErrInvalidSlowTaskLog)
+	}
+
+	if o.capacity != nil && o.capacity.size <= 0 {
+		errs = append(errs, ErrInvalidCapacity)
+	}
+
+	for _, n := range o.classes {
+		if n <= 0 {
+			errs = append(errs, ErrInvalidClassLimit)
+			break
+		}
+	}
+
+	if o.tenants != nil && (o.tenants.maxRunning < 0 || o.tenants.maxQueued < 0) {
+		errs = append(errs, ErrInvalidQuota)
+	}
+
+	if o.breaker != nil && (o.breaker.threshold <= 0 || o.breaker.cooldown <= 0) {
+		errs = append(errs, ErrInvalidBreaker)
+	}
+
+	if o.saturation != nil && (o.saturation.threshold <= 0 || o.saturation.threshold > 1 || o.saturation.window <= 0) {
+		errs = append(errs, ErrInvalidSaturation)
+	}
+
+	if o.stall != nil && (o.stall.timeout <= 0 || o.stall.report == nil) {
+		errs = append(errs, ErrInvalidStall)
+	}
+
+	if o.memory != nil && (o.memory.high == 0 || o.memory.low > o.memory.high || o.memory.interval <= 0) {
+		errs = append(errs, ErrInvalidMemoryThrottle)
+	}
+
+	if o.redelivery.timeout < 0 || o.redelivery.maxAttempts < 0 {
+		errs = append(errs, ErrInvalidRedelivery)
+	}
+
+	if o.retries != nil && (o.retries.perSecond < 0 || o.retries.ratio < 0) {
+		errs = append(errs, ErrInvalidRetryBudget)
+	}
+
+	if o.cache != nil && (o.cache.c == nil || o.cache.ttl <= 0) {
+		errs = append(errs, ErrInvalidCache)
+	}
+
+	if o.window < 0 {
+		errs = append(errs, ErrInvalidWindow)
+	}
+
+	if o.maxErrors < 0 {
+		errs = append(errs, ErrInvalidMaxErrors)
+	}
+
+	if o.queueSize < 0 {
+		errs = append(errs, ErrInvalidBuffer)
+	}
+
+	if o.maxAge < 0 {
+		errs = append(errs, ErrInvalidAging)
+	}
+
+	if o.shards < 0 {
+		errs = append(errs, ErrInvalidShards)
+	}
+
+	if o.prestart < 0 || o.prestart > o.numWorkers {
+		errs = append(errs, ErrInvalidPrestart)
+	}
+
+	if o.policy < FIFO || o.policy > HighestPriority {
+		errs = append(errs, ErrInvalidPolicy)
+	}
+
+	if o.weights != nil {
+		if len(o.weights) == 0 {
+			errs = append(errs, ErrInvalidWeights)
+		}
+
+		for _, w := range o.weights {
+			if w <= 0 {
+				errs = append(errs, ErrInvalidWeights)
+				break
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// newQueue returns the queue matching the configured dispatch and scheduling policies.
+// With tenants, each tenant gets the queue of the other policies. With weighted dispatch,
+// the scheduling policy orders the jobs within each sub-queue.
+func (o *config) newQueue() queue {
+	if o.tenants != nil {
+		return newTenantQueue(*o.tenants, o.newDispatchQueue)
+	}
+
+	return o.newDispatchQueue()
+}
+
+func (o *config) newDispatchQueue() queue {
+	if o.weights != nil {
+		return newWeightedQueue(o.weights, o.fair, o.newOrderedQueue)
+	}
+
+	return o.newOrderedQueue()
+}
+
+func (o *config) newOrderedQueue() queue {
+	if o.maxAge > 0 && o.policy != FIFO {
+		return newAgingQueue(o.newPolicyQueue(), o.maxAge, o.clock)
+	}
+
+	return o.newPolicyQueue()
+}
+
+func (o *config) newPolicyQueue() queue {
+	switch o.policy {
+	case FIFO:
+		return &fifo{}
+	case LIFO:
+		return &lifo{}
+	case EarliestDeadline:
+		return newDeadlineQueue()
+	case HighestPriority:
+		return newPriorityQueue()
 	}
 
-	return nil
+	return newDurationQueue(o.policy == LongestFirst)
 }
@@ -0,0 +1,63 @@
+// Package oteltrace traces the tasks executed by gowp pools with OpenTelemetry.
+//
+// It is a separate module, so that the core package doesn't depend on OpenTelemetry.
+//
+// Example:
+//	tracer := otel.Tracer("emailer")
+//	wp, _ := gowp.New(10, gowp.WithHooks(oteltrace.Hooks(tracer)))
+package oteltrace // import "github.com/akshaybharambe14/gowp/oteltrace"
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/akshaybharambe14/gowp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// attribute keys recorded on task spans.
+const (
+	attrTaskID    = attribute.Key("gowp.task.id")
+	attrTaskLabel = attribute.Key("gowp.task.label")
+)
+
+// SpanName is the name of the spans created for tasks.
+const SpanName = "gowp.task"
+
+// Hooks returns gowp.Hooks that record a span for every executed task, from the moment
+// it was submitted until it finishes. The moment a worker picked it up is recorded as the
+// span's "started" event. The returned Hooks should be registered with a single pool.
+func Hooks(tracer trace.Tracer) gowp.Hooks {
+	var spans sync.Map // task id -> trace.Span
+
+	return gowp.Hooks{
+		OnStart: func(info gowp.TaskInfo) {
+			_, span := tracer.Start(context.Background(), SpanName,
+				trace.WithTimestamp(info.SubmittedAt),
+				trace.WithAttributes(
+					attrTaskID.Int64(int64(info.ID)),
+					attrTaskLabel.String(info.Label),
+				),
+			)
+			span.AddEvent("started", trace.WithTimestamp(info.StartedAt))
+			spans.Store(info.ID, span)
+		},
+		OnFinish: func(info gowp.TaskInfo, err error) {
+			v, ok := spans.LoadAndDelete(info.ID)
+			if !ok {
+				return // never started.
+			}
+
+			span := v.(trace.Span)
+			if err != nil && !errors.Is(err, gowp.ErrTaskCanceled) {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+			}
+
+			span.End()
+		},
+	}
+}
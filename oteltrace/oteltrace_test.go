@@ -0,0 +1,44 @@
+package oteltrace
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/akshaybharambe14/gowp"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestHooks(t *testing.T) {
+	rec := tracetest.NewSpanRecorder()
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(rec)).Tracer("test")
+
+	wp, err := gowp.New(10, gowp.WithNumWorkers(1), gowp.WithHooks(Hooks(tracer)))
+	if err != nil {
+		t.Fatalf("gowp.New() error = %v", err)
+	}
+
+	_ = wp.Submit(func() error { return nil })
+	_ = wp.Submit(func() error { return errors.New("failed") })
+	_ = wp.Wait()
+
+	spans := rec.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("recorded %d spans, want 2", len(spans))
+	}
+
+	if got := spans[0].Status().Code; got != codes.Unset {
+		t.Errorf("span status = %v, want %v", got, codes.Unset)
+	}
+
+	if got := spans[1].Status().Code; got != codes.Error {
+		t.Errorf("span status = %v, want %v", got, codes.Error)
+	}
+
+	for _, s := range spans {
+		if s.Name() != SpanName || len(s.Events()) == 0 || s.Events()[0].Name != "started" {
+			t.Errorf("span = %q with events %v, want %q with a started event", s.Name(), s.Events(), SpanName)
+		}
+	}
+}
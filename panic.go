@@ -0,0 +1,53 @@
+package gowp
+
+import (
+	"fmt"
+	"runtime/debug"
+	"sync/atomic"
+)
+
+// PanicError is the error of a task that panicked, see WithPanicRecovery.
+type PanicError struct {
+	Value interface{} // the value passed to panic.
+	Stack []byte      // the stack of the worker when the task panicked.
+}
+
+// WithPanicRecovery returns an Option that recovers panics of tasks. A task that panics fails with
+// a *PanicError, which is handled like any other error of a task, e.g. as per WithExitOnError.
+// The worker that ran it is replaced by a new one, so that goroutine-local state the task may have
+// left behind, e.g. pprof labels or a locked OS thread, doesn't leak into other tasks and the pool keeps
+// its capacity. Stats counts the replacements. Tasks executed inline are recovered but no worker is replaced.
+func WithPanicRecovery() Option {
+	return func(o *config) {
+		o.recovers = true
+	}
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("task panicked: %v", e.Value)
+}
+
+// Unwrap returns the value passed to panic, if it is an error.
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// call runs the task and turns a panic into a PanicError.
+func (j *job) call() (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			j.panicked = &PanicError{Value: r, Stack: debug.Stack()}
+			err = j.panicked
+		}
+	}()
+
+	return j.fn()
+}
+
+// replace starts a worker in place of the calling one, which exits right after as a task it ran panicked.
+// The new worker takes over the slot of the calling one, the number of workers doesn't change.
+func (p *Pool) replace(temporary bool) {
+	atomic.AddInt64(&p.counts.restarts, 1)
+	p.spawn(temporary)
+}
@@ -0,0 +1,51 @@
+package gowp
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestWithPanicRecovery(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   interface{}
+		wantErr error // matched by the PanicError, if not nil.
+	}{
+		{name: "error", value: testErr, wantErr: testErr},
+		{name: "string", value: "boom"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := testPool(context.Background(), 2, testDefaultNumTasks, false, WithPanicRecovery())
+
+			f, _ := p.SubmitFuture(func() error { panic(tt.value) })
+
+			var pe *PanicError
+			if err := f.Err(); !errors.As(err, &pe) || pe.Value != tt.value || len(pe.Stack) == 0 {
+				t.Fatalf("Future.Err() = %v, want a PanicError with %v and the stack", err, tt.value)
+			}
+
+			if tt.wantErr != nil && !errors.Is(pe, tt.wantErr) {
+				t.Errorf("PanicError = %v, want it to match %v", pe, tt.wantErr)
+			}
+
+			next, _ := p.SubmitFuture(testNoOpFunc)
+			if err := next.Err(); err != nil {
+				t.Errorf("Future.Err() = %v after a panic, want nil", err)
+			}
+
+			if s := p.Stats(); s.Workers != 2 {
+				t.Errorf("Pool.Stats().Workers = %d after a panic, want 2", s.Workers)
+			}
+
+			if err := p.Wait(); !errors.As(err, &pe) {
+				t.Errorf("Pool.Wait() = %v, want a PanicError", err)
+			}
+
+			if s := p.Stats(); s.Restarts != 1 || s.Workers != 0 || s.Failed != 1 {
+				t.Errorf("Pool.Stats() = %+v after Wait, want 1 restart, no workers and 1 failure", s)
+			}
+		})
+	}
+}
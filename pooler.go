@@ -0,0 +1,14 @@
+package gowp
+
+// interface guard
+var _ Pooler = (*Pool)(nil)
+
+// Pooler is the part of a Pool needed to submit tasks and wait for them. Code depending on it rather than on *Pool
+// can be tested against a fake, e.g. testutil.MockPool, to exercise error paths such as ErrNoBuffer.
+type Pooler interface {
+	Submit(t Task) error
+	SubmitFuture(t Task, opts ...TaskOption) (*Future, error)
+	Close()
+	Wait() error
+	Stats() Stats
+}
@@ -0,0 +1,34 @@
+package gowp
+
+import "sync"
+
+// WithPrestart returns an Option that makes New wait until n workers are running and have called the
+// OnWorkerStart hooks, see Hooks. The first tasks submitted then don't wait for goroutines to start or
+// for workers to initialize. Along with WithLazyWorkers, the n workers are started upfront, the others
+// as tasks are queued.
+//
+// n should be between zero and the number of workers, otherwise ErrInvalidPrestart will be returned on
+// Pool initialization. It has no effect along with WithInlineExecution.
+func WithPrestart(n int) Option {
+	return func(o *config) {
+		o.prestart = n
+	}
+}
+
+// startWorkers starts the regular workers of a new pool and waits for the first prestart of them to be ready.
+func (p *Pool) startWorkers(prestart int) {
+	n := p.workers // read upfront, workers decrement it as they exit.
+
+	var warm sync.WaitGroup
+	for i := 0; i < n; i++ {
+		var started func()
+		if i < prestart {
+			warm.Add(1)
+			started = warm.Done
+		}
+
+		p.start(false, started)
+	}
+
+	warm.Wait()
+}
@@ -0,0 +1,77 @@
+package gowp
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWithPrestart(t *testing.T) {
+	tests := []struct {
+		name        string
+		prestart    int
+		lazy        bool
+		wantErr     error
+		wantWorkers int
+	}{
+		{name: "negative", prestart: -1, wantErr: ErrInvalidPrestart},
+		{name: "over the worker count", prestart: 5, wantErr: ErrInvalidPrestart},
+		{name: "some", prestart: 2, wantWorkers: 4},
+		{name: "all", prestart: 4, wantWorkers: 4},
+		{name: "lazy", prestart: 2, lazy: true, wantWorkers: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var started int64
+			opts := []Option{WithNumWorkers(4), WithPrestart(tt.prestart), WithHooks(Hooks{OnWorkerStart: func() {
+				time.Sleep(10 * time.Millisecond) // a slow initialization.
+				atomic.AddInt64(&started, 1)
+			}})}
+			if tt.lazy {
+				opts = append(opts, WithLazyWorkers())
+			}
+
+			p, err := New(10, opts...)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("New() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if err != nil {
+				return
+			}
+
+			if n := atomic.LoadInt64(&started); n < int64(tt.prestart) {
+				t.Errorf("%d workers started when New returned, want at least %d", n, tt.prestart)
+			}
+
+			if w := p.Stats().Workers; w != tt.wantWorkers {
+				t.Errorf("Stats().Workers = %d, want %d", w, tt.wantWorkers)
+			}
+
+			if err := p.Wait(); err != nil {
+				t.Errorf("Pool.Wait() = %v, want nil", err)
+			}
+		})
+	}
+}
+
+func TestOnWorkerStart_panic(t *testing.T) {
+	var herr *HookError
+	p, _ := New(1, WithNumWorkers(1), WithPrestart(1),
+		WithHooks(Hooks{OnWorkerStart: func() { panic("boom") }}),
+		WithHookErrorHandler(func(err error) { errors.As(err, &herr) }),
+	)
+
+	if err := p.Submit(func() error { return nil }); err != nil {
+		t.Fatalf("Pool.Submit() error = %v", err)
+	}
+
+	if err := p.Wait(); err != nil {
+		t.Errorf("Pool.Wait() = %v, want nil", err)
+	}
+
+	if herr == nil || herr.Hook != "OnWorkerStart" {
+		t.Errorf("hook error = %v, want a panic of OnWorkerStart", herr)
+	}
+}
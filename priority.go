@@ -0,0 +1,45 @@
+package gowp
+
+import "sync/atomic"
+
+// TaskPriority returns a TaskOption that sets the priority of the task, zero by default. Under the HighestPriority
+// scheduling policy, tasks with a higher priority are run first. It has no effect under the other policies.
+func TaskPriority(priority int) TaskOption {
+	return func(j *job) {
+		j.priority = priority
+	}
+}
+
+// SetPriority changes the priority of a queued task, see TaskPriority, e.g. to move a background job ahead
+// once a user waits for it. It reports whether the task was still queued, the priority of a task that has
+// started, finished or been cancelled can't change. Tasks held back, e.g. by SubmitAfter, aren't queued yet.
+func (f *Future) SetPriority(priority int) bool {
+	j := f.job.Load()
+	if j == nil {
+		return false
+	}
+
+	p := f.pool
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if atomic.LoadUint32(&f.state) != taskQueued || f.job.Load() != j {
+		return false
+	}
+
+	if p.policy != HighestPriority || j.priority == priority {
+		j.priority = priority
+		return true
+	}
+
+	// the queue orders jobs as they are pushed, take the job out and push it again to move it.
+	if removed := p.queue.removeIf(func(q *job) bool { return q == j }); len(removed) == 0 {
+		j.priority = priority // still in a shard, it is ordered once transferred to the queue.
+		return true
+	}
+
+	j.priority = priority
+	_ = p.queue.push(j) // there was room for the job already.
+
+	return true
+}
@@ -0,0 +1,94 @@
+package gowp
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestFuture_SetPriority(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  SchedulingPolicy
+		raise   int // index of the task whose priority is raised once queued.
+		want    []int
+		wantSet bool
+	}{
+		{name: "highest priority", policy: HighestPriority, raise: 3, want: []int{3, 1, 0, 2}, wantSet: true},
+		{name: "fifo keeps the order", policy: FIFO, raise: 3, want: []int{0, 1, 2, 3}, wantSet: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := New(10, WithNumWorkers(1), WithSchedulingPolicy(tt.policy))
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+
+			// hold the only worker, so that the tasks are queued before any of them is picked.
+			started, release := make(chan struct{}), make(chan struct{})
+			blocker, _ := p.SubmitFuture(func() error { close(started); <-release; return nil })
+			<-started
+
+			var (
+				mu  sync.Mutex
+				got []int
+			)
+			priorities := []int{0, 5, 0, 0}
+			futures := make([]*Future, len(priorities))
+			for i, prio := range priorities {
+				i := i
+				futures[i], err = p.SubmitFuture(func() error {
+					mu.Lock()
+					got = append(got, i)
+					mu.Unlock()
+					return nil
+				}, TaskPriority(prio))
+				if err != nil {
+					t.Fatalf("SubmitFuture() error = %v", err)
+				}
+			}
+
+			if set := futures[tt.raise].SetPriority(10); set != tt.wantSet {
+				t.Errorf("SetPriority() = %v, want %v", set, tt.wantSet)
+			}
+
+			if blocker.SetPriority(10) {
+				t.Error("SetPriority() of a running task = true, want false")
+			}
+
+			close(release)
+			if err := p.Wait(); err != nil {
+				t.Fatalf("Wait() error = %v", err)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("execution order = %v, want %v", got, tt.want)
+			}
+
+			if futures[0].SetPriority(1) {
+				t.Error("SetPriority() of a finished task = true, want false")
+			}
+		})
+	}
+}
+
+func TestFuture_SetPriority_held(t *testing.T) {
+	p, err := New(1, WithNumWorkers(1))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	f, err := p.SubmitAfter(time.Hour, func() error { return nil })
+	if err != nil {
+		t.Fatalf("SubmitAfter() error = %v", err)
+	}
+
+	if f.SetPriority(1) {
+		t.Error("SetPriority() of a held task = true, want false")
+	}
+
+	f.Cancel()
+	_ = p.Wait()
+}
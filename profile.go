@@ -0,0 +1,50 @@
+package gowp
+
+import "runtime"
+
+// Profile is the shape of a workload, it decides how a pool is sized, see WithProfile.
+type Profile int
+
+const (
+	// CPUBound tasks keep a CPU busy while they run. More workers than CPUs would only compete for them.
+	CPUBound Profile = iota + 1
+	// IOBound tasks spend most of their time waiting, e.g. for the network or the disk.
+	// Many more workers than CPUs keep the CPUs busy meanwhile.
+	IOBound
+)
+
+// ioWorkersPerCPU is the number of workers per CPU of the IOBound profile.
+const ioWorkersPerCPU = 16
+
+// WithProfile returns an Option that sets the number of workers for the given workload shape,
+// see Profile.Workers. Like any option, a later WithNumWorkers overrides it.
+// The queue is sized by New, Profile.QueueSize suggests a size:
+//
+//	wp, _ := gowp.New(gowp.IOBound.QueueSize(), gowp.WithProfile(gowp.IOBound))
+//
+// An unknown profile results in ErrInvalidProfile on Pool initialization.
+func WithProfile(pr Profile) Option {
+	return func(o *config) {
+		o.profile = pr
+		o.numWorkers = pr.Workers()
+	}
+}
+
+// Workers returns the number of workers suited to the profile: one per CPU usable by the program, see
+// runtime.GOMAXPROCS, for CPUBound and 16 per CPU for IOBound. It returns zero for an unknown profile.
+func (pr Profile) Workers() int {
+	switch pr {
+	case CPUBound:
+		return runtime.GOMAXPROCS(0)
+	case IOBound:
+		return ioWorkersPerCPU * runtime.GOMAXPROCS(0)
+	default:
+		return 0
+	}
+}
+
+// QueueSize returns a queue size suited to the profile, for New. The queue holds two tasks per worker,
+// so that workers find the next task queued as they finish one, while submitters feel backpressure early.
+func (pr Profile) QueueSize() int {
+	return 2 * pr.Workers()
+}
@@ -0,0 +1,47 @@
+package gowp
+
+import (
+	"errors"
+	"runtime"
+	"testing"
+)
+
+func TestWithProfile(t *testing.T) {
+	procs := runtime.GOMAXPROCS(0)
+
+	tests := []struct {
+		name        string
+		opts        []Option
+		wantErr     error
+		wantWorkers int
+	}{
+		{name: "cpu bound", opts: []Option{WithProfile(CPUBound)}, wantWorkers: procs},
+		{name: "io bound", opts: []Option{WithProfile(IOBound)}, wantWorkers: ioWorkersPerCPU * procs},
+		{name: "overridden", opts: []Option{WithProfile(IOBound), WithNumWorkers(3)}, wantWorkers: 3},
+		{name: "unknown", opts: []Option{WithProfile(Profile(42))}, wantErr: ErrInvalidProfile},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := New(IOBound.QueueSize(), tt.opts...)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("New() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if err != nil {
+				return
+			}
+
+			if w := p.Stats().Workers; w != tt.wantWorkers {
+				t.Errorf("Stats().Workers = %d, want %d", w, tt.wantWorkers)
+			}
+
+			if err := p.Wait(); err != nil {
+				t.Errorf("Pool.Wait() = %v, want nil", err)
+			}
+		})
+	}
+
+	if got, want := CPUBound.QueueSize(), 2*procs; got != want {
+		t.Errorf("CPUBound.QueueSize() = %d, want %d", got, want)
+	}
+}
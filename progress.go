@@ -0,0 +1,44 @@
+package gowp
+
+// WithOnProgress returns an Option that calls fn with the progress of the pool, see Progress, every time
+// a worker is done with a task and once more when the pool completes, e.g. to render a progress bar.
+// fn is called from the worker goroutines, possibly concurrently, so it should be fast and safe for concurrent
+// use. A panic in fn is recovered, see WithHookErrorHandler. A nil fn is ignored.
+func WithOnProgress(fn func(done, total int)) Option {
+	return func(o *config) {
+		o.onProgress = fn
+	}
+}
+
+// Progress returns the number of tasks that are done, i.e. executed, cancelled, discarded or expired, out of total.
+// total is the task count the pool was created with, see New, or the number of tasks submitted if more were.
+// Once the pool has completed, total is the number of tasks it dealt with, so that done equals total.
+func (p *Pool) Progress() (done, total int) {
+	r := p.tally()
+	done = r.Succeeded + r.Failed + r.Ignored + r.Canceled + r.Discarded + r.Expired
+
+	total = r.Submitted
+	select {
+	case <-p.done:
+	default:
+		if total < p.expected {
+			total = p.expected
+		}
+	}
+
+	if total < done {
+		total = done // tasks cancelled while held are never counted as submitted.
+	}
+
+	return done, total
+}
+
+// progress reports the progress of the pool, if asked to.
+func (p *Pool) progress() {
+	if p.onProgress == nil {
+		return
+	}
+
+	done, total := p.Progress()
+	p.guard("OnProgress", TaskInfo{}, func() { p.onProgress(done, total) })
+}
@@ -0,0 +1,66 @@
+package gowp
+
+import (
+	"context"
+	"sync"
+	"testing"
+)
+
+func TestPool_Progress(t *testing.T) {
+	tests := []struct {
+		name      string
+		numTasks  int
+		submitted int
+		wantTotal int // before the pool completes.
+	}{
+		{name: "as many tasks as announced", numTasks: 4, submitted: 4, wantTotal: 4},
+		{name: "fewer tasks than announced", numTasks: 8, submitted: 3, wantTotal: 8},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				mu    sync.Mutex
+				calls [][2]int
+			)
+			onProgress := func(done, total int) {
+				mu.Lock()
+				calls = append(calls, [2]int{done, total})
+				mu.Unlock()
+			}
+
+			p := testPool(context.Background(), 1, tt.numTasks, false, WithOnProgress(onProgress))
+
+			release := make(chan struct{})
+			_ = p.Submit(func() error { <-release; return nil })
+			for i := 1; i < tt.submitted; i++ {
+				_ = p.Submit(testNoOpFunc)
+			}
+
+			if done, total := p.Progress(); done != 0 || total != tt.wantTotal {
+				t.Errorf("Pool.Progress() = %d, %d, want 0, %d", done, total, tt.wantTotal)
+			}
+
+			close(release)
+			_ = p.Wait()
+
+			if done, total := p.Progress(); done != tt.submitted || total != tt.submitted {
+				t.Errorf("Pool.Progress() = %d, %d after Wait, want %d, %d", done, total, tt.submitted, tt.submitted)
+			}
+
+			// once per task, then on completion.
+			if len(calls) != tt.submitted+1 {
+				t.Fatalf("WithOnProgress() called %d times, want %d: %v", len(calls), tt.submitted+1, calls)
+			}
+
+			for i, c := range calls[:tt.submitted] {
+				if c != [2]int{i + 1, tt.wantTotal} {
+					t.Errorf("WithOnProgress() call %d = %v, want %v", i, c, [2]int{i + 1, tt.wantTotal})
+				}
+			}
+
+			if last := calls[tt.submitted]; last != [2]int{tt.submitted, tt.submitted} {
+				t.Errorf("WithOnProgress() last call = %v, want %v", last, [2]int{tt.submitted, tt.submitted})
+			}
+		})
+	}
+}
@@ -0,0 +1,24 @@
+module github.com/akshaybharambe14/gowp/prommetrics
+
+go 1.20
+
+require (
+	github.com/akshaybharambe14/gowp v0.0.0-00010101000000-000000000000
+	github.com/prometheus/client_golang v1.20.5
+)
+
+require (
+	github.com/beorn7/perks v1.0.1 // indirect
+	github.com/cespare/xxhash/v2 v2.3.0 // indirect
+	github.com/kylelemons/godebug v1.1.0 // indirect
+	github.com/munnerz/goautoneg v0.0.0-20191010083416-a7dc8b61c822 // indirect
+	github.com/prometheus/client_model v0.6.1 // indirect
+	github.com/prometheus/common v0.55.0 // indirect
+	github.com/prometheus/procfs v0.15.1 // indirect
+	golang.org/x/sys v0.22.0 // indirect
+	google.golang.org/protobuf v1.34.2 // indirect
+)
+
+// the core module is built from this repository until a release of it with the APIs used here is tagged,
+// the required version is a placeholder.
+replace github.com/akshaybharambe14/gowp => ./..
@@ -0,0 +1,132 @@
+// Package prommetrics exports metrics of gowp pools to Prometheus.
+//
+// It is a separate module, so that the core package doesn't depend on the Prometheus client.
+//
+// Example:
+//	m := prommetrics.New("emailer")
+//	prometheus.MustRegister(m)
+//
+//	wp, _ := gowp.New(10, gowp.WithHooks(m.Hooks()))
+package prommetrics // import "github.com/akshaybharambe14/gowp/prommetrics"
+
+import (
+	"errors"
+	"time"
+
+	"github.com/akshaybharambe14/gowp"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// results of a finished task, used as values of the "result" label.
+const (
+	resultSuccess   = "success"
+	resultError     = "error"
+	resultRejected  = "rejected"
+	resultCanceled  = "canceled"
+	resultDiscarded = "discarded"
+)
+
+// Metrics is a prometheus.Collector for the tasks of a pool.
+//
+// Zero value is not usable. Use New() to create Metrics.
+type Metrics struct {
+	submitted prometheus.Counter
+	started   prometheus.Counter
+	finished  *prometheus.CounterVec
+	queueWait prometheus.Histogram
+	duration  prometheus.Histogram
+}
+
+// interface guard to ensure Metrics implements prometheus.Collector interface
+var _ prometheus.Collector = (*Metrics)(nil)
+
+// New creates Metrics for a pool, pool is attached to every metric as the "pool" label.
+func New(pool string) *Metrics {
+	labels := prometheus.Labels{"pool": pool}
+
+	return &Metrics{
+		submitted: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace:   "gowp",
+			Name:        "tasks_submitted_total",
+			Help:        "Number of tasks submitted to the pool.",
+			ConstLabels: labels,
+		}),
+		started: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace:   "gowp",
+			Name:        "tasks_started_total",
+			Help:        "Number of tasks picked up by a worker.",
+			ConstLabels: labels,
+		}),
+		finished: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   "gowp",
+			Name:        "tasks_finished_total",
+			Help:        "Number of tasks finished, partitioned by result.",
+			ConstLabels: labels,
+		}, []string{"result"}),
+		queueWait: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace:   "gowp",
+			Name:        "task_queue_wait_seconds",
+			Help:        "Time tasks spent in the queue before a worker picked them up.",
+			ConstLabels: labels,
+			Buckets:     prometheus.ExponentialBuckets(0.0001, 4, 10),
+		}),
+		duration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace:   "gowp",
+			Name:        "task_duration_seconds",
+			Help:        "Time taken to execute tasks.",
+			ConstLabels: labels,
+			Buckets:     prometheus.ExponentialBuckets(0.0001, 4, 10),
+		}),
+	}
+}
+
+// Hooks returns the gowp.Hooks that record metrics, pass them to gowp.WithHooks.
+func (m *Metrics) Hooks() gowp.Hooks {
+	return gowp.Hooks{
+		OnSubmit: func(gowp.TaskInfo) {
+			m.submitted.Inc()
+		},
+		OnStart: func(info gowp.TaskInfo) {
+			m.started.Inc()
+			m.queueWait.Observe(info.StartedAt.Sub(info.SubmittedAt).Seconds())
+		},
+		OnFinish: func(info gowp.TaskInfo, err error) {
+			if !info.StartedAt.IsZero() {
+				m.duration.Observe(time.Since(info.StartedAt).Seconds())
+			}
+
+			m.finished.WithLabelValues(result(info, err)).Inc()
+		},
+	}
+}
+
+func (m *Metrics) Describe(ch chan<- *prometheus.Desc) {
+	m.submitted.Describe(ch)
+	m.started.Describe(ch)
+	m.finished.Describe(ch)
+	m.queueWait.Describe(ch)
+	m.duration.Describe(ch)
+}
+
+func (m *Metrics) Collect(ch chan<- prometheus.Metric) {
+	m.submitted.Collect(ch)
+	m.started.Collect(ch)
+	m.finished.Collect(ch)
+	m.queueWait.Collect(ch)
+	m.duration.Collect(ch)
+}
+
+func result(info gowp.TaskInfo, err error) string {
+	switch {
+	case err == nil:
+		return resultSuccess
+	case errors.Is(err, gowp.ErrTaskCanceled):
+		return resultCanceled
+	case errors.Is(err, gowp.ErrTaskDiscarded):
+		return resultDiscarded
+	case info.StartedAt.IsZero():
+		return resultRejected
+	default:
+		return resultError
+	}
+}
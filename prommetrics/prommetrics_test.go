@@ -0,0 +1,41 @@
+package prommetrics
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/akshaybharambe14/gowp"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestMetrics(t *testing.T) {
+	m := New("test")
+
+	wp, err := gowp.New(10, gowp.WithNumWorkers(2), gowp.WithHooks(m.Hooks()))
+	if err != nil {
+		t.Fatalf("gowp.New() error = %v", err)
+	}
+
+	_ = wp.Submit(func() error { return nil })
+	_ = wp.Submit(func() error { return errors.New("failed") })
+	_ = wp.Wait()
+
+	want := `
+# HELP gowp_tasks_finished_total Number of tasks finished, partitioned by result.
+# TYPE gowp_tasks_finished_total counter
+gowp_tasks_finished_total{pool="test",result="error"} 1
+gowp_tasks_finished_total{pool="test",result="success"} 1
+# HELP gowp_tasks_submitted_total Number of tasks submitted to the pool.
+# TYPE gowp_tasks_submitted_total counter
+gowp_tasks_submitted_total{pool="test"} 2
+`
+	err = testutil.CollectAndCompare(m, strings.NewReader(want), "gowp_tasks_finished_total", "gowp_tasks_submitted_total")
+	if err != nil {
+		t.Error(err)
+	}
+
+	if n := testutil.CollectAndCount(m, "gowp_task_duration_seconds"); n != 1 {
+		t.Errorf("CollectAndCount(gowp_task_duration_seconds) = %d, want 1", n)
+	}
+}
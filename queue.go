@@ -0,0 +1,337 @@
+package gowp
+
+import (
+	"math/rand"
+	"sort"
+	"sync"
+)
+
+type (
+	// queue holds the jobs waiting for a worker. The order in which jobs are popped
+	// is decided by the implementation. Implementations need not be safe for concurrent use,
+	// the pool guards them with its mutex.
+	queue interface {
+		push(j *job) error
+		pop() *job // returns nil if the queue is empty.
+		len() int
+		// oldest returns the job that was submitted first among the queued ones, nil if the queue is empty.
+		oldest() *job
+		// removeIf removes the jobs for which drop returns true, preserving the order of the rest.
+		removeIf(drop func(*job) bool) []*job
+		// popIf is pop restricted to the jobs for which match returns true: among them, it removes and returns
+		// the one pop would return first, with the same accounting. nil, if there is none.
+		popIf(match func(*job) bool) *job
+	}
+
+	// fifo is a growable ring buffer of jobs, it is the default queue of the pool.
+	fifo struct {
+		buf  []*job
+		head int
+		n    int
+	}
+
+	// weightedQueue keeps a sub-queue per label and picks the label to pop from at random,
+	// proportionally to the weights of the labels that have pending jobs. If it is fair, labels are
+	// picked in turn instead, by smooth weighted round-robin.
+	weightedQueue struct {
+		labels  []string // sorted, so that the draw is deterministic for a given random source.
+		weights map[string]int
+		queues  map[string]queue
+		credit  map[string]int // credit of the labels in the round-robin. nil, unless the queue is fair.
+		n       int
+	}
+)
+
+// interface guards
+var (
+	_ queue = (*fifo)(nil)
+	_ queue = (*weightedQueue)(nil)
+)
+
+func (q *fifo) push(j *job) error {
+	if q.n == len(q.buf) {
+		q.grow()
+	}
+
+	q.buf[(q.head+q.n)%len(q.buf)] = j
+	q.n++
+
+	return nil
+}
+
+func (q *fifo) pop() *job {
+	if q.n == 0 {
+		return nil
+	}
+
+	j := q.buf[q.head]
+	q.buf[q.head] = nil // let the GC collect the job once it is done.
+	q.head = (q.head + 1) % len(q.buf)
+	q.n--
+
+	return j
+}
+
+// popIf scans the queue from the head and stops at the first match, only the jobs skipped before it are moved.
+func (q *fifo) popIf(match func(*job) bool) *job {
+	for i := 0; i < q.n; i++ {
+		k := (q.head + i) % len(q.buf)
+		j := q.buf[k]
+		if !match(j) {
+			continue
+		}
+
+		// shift the skipped jobs by one toward the tail, over the match, to keep their order.
+		for ; i > 0; i-- {
+			prev := (q.head + i - 1) % len(q.buf)
+			q.buf[k] = q.buf[prev]
+			k = prev
+		}
+
+		q.buf[q.head] = nil // let the GC collect the job once it is done.
+		q.head = (q.head + 1) % len(q.buf)
+		q.n--
+
+		return j
+	}
+
+	return nil
+}
+
+func (q *fifo) len() int {
+	return q.n
+}
+
+func (q *fifo) oldest() *job {
+	if q.n == 0 {
+		return nil
+	}
+
+	return q.buf[q.head]
+}
+
+func (q *fifo) removeIf(drop func(*job) bool) []*job {
+	var removed []*job
+
+	kept := 0
+	for i := 0; i < q.n; i++ {
+		j := q.buf[(q.head+i)%len(q.buf)]
+		if drop(j) {
+			removed = append(removed, j)
+			continue
+		}
+
+		q.buf[(q.head+kept)%len(q.buf)] = j
+		kept++
+	}
+
+	for i := kept; i < q.n; i++ {
+		q.buf[(q.head+i)%len(q.buf)] = nil
+	}
+
+	q.n = kept
+
+	return removed
+}
+
+// contains reports whether q holds a job for which match returns true.
+func contains(q queue, match func(*job) bool) bool {
+	found := false
+	q.removeIf(func(j *job) bool {
+		found = found || match(j)
+		return false
+	})
+
+	return found
+}
+
+// buffers recycles the ring buffers of the pools that have completed, so that short-lived pools are cheap.
+var buffers = sync.Pool{New: func() interface{} { return make([]*job, 8) }}
+
+func (q *fifo) grow() {
+	if len(q.buf) == 0 {
+		q.buf, q.head = buffers.Get().([]*job), 0
+		return
+	}
+
+	buf := make([]*job, 2*len(q.buf))
+	for i := 0; i < q.n; i++ {
+		buf[i] = q.buf[(q.head+i)%len(q.buf)]
+	}
+
+	q.buf, q.head = buf, 0
+}
+
+// release hands the buffer of an empty queue over to other pools. The queue remains usable.
+func (q *fifo) release() {
+	if q.n > 0 || len(q.buf) == 0 {
+		return
+	}
+
+	buffers.Put(q.buf) // popped slots are nil already, the buffer holds no job.
+	q.buf, q.head = nil, 0
+}
+
+// newWeightedQueue returns a weightedQueue whose sub-queues are created by newSub.
+func newWeightedQueue(weights map[string]int, fair bool, newSub func() queue) *weightedQueue {
+	q := &weightedQueue{
+		weights: make(map[string]int, len(weights)),
+		queues:  make(map[string]queue, len(weights)),
+	}
+
+	if fair {
+		q.credit = make(map[string]int, len(weights))
+	}
+
+	for label, w := range weights {
+		q.labels = append(q.labels, label)
+		q.weights[label] = w
+		q.queues[label] = newSub()
+	}
+
+	sort.Strings(q.labels)
+
+	return q
+}
+
+func (q *weightedQueue) push(j *job) error {
+	sq, ok := q.queues[j.label]
+	if !ok {
+		return ErrUnknownLabel
+	}
+
+	q.n++
+
+	return sq.push(j)
+}
+
+func (q *weightedQueue) pop() *job {
+	return q.popMatching(nil)
+}
+
+func (q *weightedQueue) popIf(match func(*job) bool) *job {
+	return q.popMatching(match)
+}
+
+// popMatching pops from a label drawn among the ones that have pending jobs for which match returns true,
+// any pending job if match is nil.
+func (q *weightedQueue) popMatching(match func(*job) bool) *job {
+	if q.n == 0 {
+		return nil
+	}
+
+	candidate := func(label string) bool {
+		sq := q.queues[label]
+		return sq.len() > 0 && (match == nil || contains(sq, match))
+	}
+
+	var (
+		label string
+		ok    bool
+	)
+	if q.credit != nil {
+		label, ok = q.pickFair(candidate)
+	} else {
+		label, ok = q.pickRandom(candidate)
+	}
+
+	if !ok {
+		return nil
+	}
+
+	q.n--
+	if match == nil {
+		return q.queues[label].pop()
+	}
+
+	return q.queues[label].popIf(match)
+}
+
+// pickRandom draws a candidate label at random, proportionally to the weights of the candidates.
+func (q *weightedQueue) pickRandom(candidate func(string) bool) (string, bool) {
+	total := 0
+	for _, label := range q.labels {
+		if candidate(label) {
+			total += q.weights[label]
+		}
+	}
+
+	if total == 0 {
+		return "", false
+	}
+
+	r := rand.Intn(total)
+	for _, label := range q.labels {
+		if !candidate(label) {
+			continue
+		}
+
+		if r -= q.weights[label]; r < 0 {
+			return label, true
+		}
+	}
+
+	return "", false // unreachable, r is always less than total.
+}
+
+// pickFair picks the candidate label with the most credit, once every label that has pending jobs has been credited
+// its weight. The label picked is debited the weights of all of them, so that over a round the labels are
+// picked proportionally to their weights and interleaved, e.g. a, a, b, a, a for weights 4 and 1.
+func (q *weightedQueue) pickFair(candidate func(string) bool) (string, bool) {
+	found := false
+	for _, label := range q.labels {
+		if found = candidate(label); found {
+			break
+		}
+	}
+
+	if !found {
+		return "", false // nothing to pick, leave the credits as they are.
+	}
+
+	best, total, found := "", 0, false
+	for _, label := range q.labels {
+		if q.queues[label].len() == 0 {
+			q.credit[label] = 0 // an idle label doesn't save up credit.
+			continue
+		}
+
+		w := q.weights[label]
+		q.credit[label] += w
+		total += w
+
+		if candidate(label) && (!found || q.credit[label] > q.credit[best]) {
+			best, found = label, true
+		}
+	}
+
+	q.credit[best] -= total
+
+	return best, true
+}
+
+func (q *weightedQueue) len() int {
+	return q.n
+}
+
+func (q *weightedQueue) removeIf(drop func(*job) bool) []*job {
+	var removed []*job
+	for _, label := range q.labels {
+		removed = append(removed, q.queues[label].removeIf(drop)...)
+	}
+
+	q.n -= len(removed)
+
+	return removed
+}
+
+func (q *weightedQueue) oldest() *job {
+	var oldest *job
+	for _, label := range q.labels {
+		if j := q.queues[label].oldest(); j != nil && (oldest == nil || j.id < oldest.id) {
+			oldest = j
+		}
+	}
+
+	return oldest
+}
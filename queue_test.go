@@ -0,0 +1,151 @@
+package gowp
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestFifo(t *testing.T) {
+	q := &fifo{}
+	jobs := make([]*job, 20)
+	for i := range jobs {
+		jobs[i] = &job{}
+	}
+
+	// interleave pushes and pops so that the ring wraps around while growing.
+	for _, j := range jobs[:5] {
+		_ = q.push(j)
+	}
+	for _, want := range jobs[:3] {
+		if got := q.pop(); got != want {
+			t.Fatalf("fifo.pop() = %p, want %p", got, want)
+		}
+	}
+	for _, j := range jobs[5:] {
+		_ = q.push(j)
+	}
+	for _, want := range jobs[3:] {
+		if got := q.pop(); got != want {
+			t.Fatalf("fifo.pop() = %p, want %p", got, want)
+		}
+	}
+
+	if got := q.pop(); got != nil || q.len() != 0 {
+		t.Errorf("fifo.pop() = %p, len = %d on empty queue", got, q.len())
+	}
+}
+
+func TestFifo_popIf(t *testing.T) {
+	q := &fifo{}
+	for i := 0; i < 6; i++ {
+		_ = q.push(&job{id: uint64(i)})
+	}
+	_ = q.pop() // so that the ring wraps around.
+	_ = q.push(&job{id: 6})
+
+	even := func(j *job) bool { return j.id%2 == 0 }
+
+	var got []uint64
+	for j := q.popIf(even); j != nil; j = q.popIf(even) {
+		got = append(got, j.id)
+	}
+	for j := q.pop(); j != nil; j = q.pop() {
+		got = append(got, j.id)
+	}
+
+	if want := []uint64{2, 4, 6, 1, 3, 5}; !reflect.DeepEqual(got, want) {
+		t.Errorf("fifo.popIf() then pop() = %v, want %v", got, want)
+	}
+}
+
+func BenchmarkFifo_popIf(b *testing.B) {
+	const depth = 10000
+
+	for _, skipped := range []int{0, 100} {
+		b.Run(fmt.Sprintf("%d skipped", skipped), func(b *testing.B) {
+			q := &fifo{}
+			for i := 0; i < depth; i++ {
+				j := &job{}
+				if i < skipped {
+					j.class = "db" // at its limit, the tasks stay at the head of the queue.
+				}
+				_ = q.push(j)
+			}
+
+			admits := func(j *job) bool { return j.class != "db" }
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				_ = q.push(q.popIf(admits))
+			}
+		})
+	}
+}
+
+func TestWeightedQueue(t *testing.T) {
+	const n = 10000
+
+	q := newWeightedQueue(map[string]int{"stable": 9, "canary": 1}, false, func() queue { return &fifo{} })
+	for i := 0; i < n; i++ {
+		_ = q.push(&job{label: "stable"})
+		_ = q.push(&job{label: "canary"})
+	}
+
+	canary := 0
+	for i := 0; i < n; i++ {
+		if q.pop().label == "canary" {
+			canary++
+		}
+	}
+
+	// expect ~10% canary picks while both sub-queues are non-empty.
+	if ratio := float64(canary) / n; math.Abs(ratio-0.1) > 0.02 {
+		t.Errorf("canary ratio = %v, want ~0.1", ratio)
+	}
+
+	if err := q.push(&job{label: "unknown"}); err != ErrUnknownLabel {
+		t.Errorf("weightedQueue.push() = %v, want %v", err, ErrUnknownLabel)
+	}
+
+	for q.len() > 0 {
+		if q.pop() == nil {
+			t.Fatal("weightedQueue.pop() = nil on non-empty queue")
+		}
+	}
+}
+
+func TestWeightedQueue_fair(t *testing.T) {
+	tests := []struct {
+		name    string
+		weights map[string]int
+		pending map[string]int // jobs queued per label.
+		want    string         // labels of the popped jobs, in order.
+	}{
+		{name: "interleaved", weights: map[string]int{"a": 4, "b": 1}, pending: map[string]int{"a": 8, "b": 2}, want: "aabaaaabaa"},
+		{name: "seventy thirty", weights: map[string]int{"i": 7, "b": 3}, pending: map[string]int{"i": 7, "b": 3}, want: "ibiibiiibi"},
+		{name: "idle label", weights: map[string]int{"a": 1, "b": 1}, pending: map[string]int{"a": 3}, want: "aaa"},
+		{name: "label drained", weights: map[string]int{"a": 1, "b": 1}, pending: map[string]int{"a": 1, "b": 3}, want: "abbb"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := newWeightedQueue(tt.weights, true, func() queue { return &fifo{} })
+			for label, n := range tt.pending {
+				for i := 0; i < n; i++ {
+					_ = q.push(&job{label: label})
+				}
+			}
+
+			got := ""
+			for q.len() > 0 {
+				got += q.pop().label
+			}
+
+			if got != tt.want {
+				t.Errorf("weightedQueue.pop() order = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package gowp
+
+import "context"
+
+// Limiter paces the execution of tasks, see WithRateLimit.
+// *rate.Limiter from golang.org/x/time/rate implements it.
+type Limiter interface {
+	// Wait blocks until the next task may run. It returns an error if ctx is done.
+	Wait(ctx context.Context) error
+}
+
+// WithRateLimit returns an Option that makes workers wait for l before running each task,
+// e.g. WithRateLimit(rate.NewLimiter(100, 1)) runs at most 100 tasks per second, however many workers there are.
+// Waiting workers are released when the pool stops, the task they hold is discarded.
+// Any other error returned by l is reported as the error of the task, without running it.
+func WithRateLimit(l Limiter) Option {
+	return func(o *config) {
+		o.limiter = l
+	}
+}
+
+// throttle waits for the limiter before j runs. It returns false if j must not run, j is accounted for then.
+func (p *Pool) throttle(j *job) bool {
+	err := p.limiter.Wait(p.halt)
+	if err == nil {
+		return true
+	}
+
+	if p.halt.Err() != nil {
+		p.drop(j) // the pool is stopping.
+		return false
+	}
+
+	j.fn = func() error { return err }
+
+	return true
+}
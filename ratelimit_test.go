@@ -0,0 +1,119 @@
+package gowp
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// tokenLimiter lets a task run for every token sent on its channel.
+type tokenLimiter chan struct{}
+
+func (l tokenLimiter) Wait(ctx context.Context) error {
+	select {
+	case <-l:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// countingLimiter never blocks, it counts the tokens taken.
+type countingLimiter struct{ n int32 }
+
+func (l *countingLimiter) Wait(context.Context) error {
+	atomic.AddInt32(&l.n, 1)
+	return nil
+}
+
+type failingLimiter struct{}
+
+func (failingLimiter) Wait(context.Context) error { return testErr }
+
+func TestWithRateLimit(t *testing.T) {
+	tokens := make(tokenLimiter)
+	p := testPool(context.Background(), 2, testDefaultNumTasks, false, WithRateLimit(tokens))
+
+	var futs []*Future
+	for i := 0; i < 3; i++ {
+		f, _ := p.SubmitFuture(testNoOpFunc)
+		futs = append(futs, f)
+	}
+
+	// every task needs a token, even though there are idle workers.
+	for i, f := range futs {
+		select {
+		case <-f.Done():
+			t.Fatalf("task %d finished before a token was available", i)
+		default:
+		}
+
+		tokens <- struct{}{}
+	}
+
+	if err := p.Wait(); err != nil {
+		t.Errorf("Pool.Wait() = %v, want nil", err)
+	}
+
+	for _, f := range futs {
+		if err := f.Err(); err != nil {
+			t.Errorf("Future.Err() = %v, want nil", err)
+		}
+	}
+}
+
+func TestWithRateLimit_stop(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	p := testPool(ctx, 1, testDefaultNumTasks, false, WithRateLimit(make(tokenLimiter)))
+
+	f, _ := p.SubmitFuture(testNoOpFunc)
+	cancel()
+
+	if err := p.Wait(); !errors.Is(err, context.Canceled) {
+		t.Errorf("Pool.Wait() = %v, want %v", err, context.Canceled)
+	}
+
+	if err := f.Err(); !errors.Is(err, ErrTaskDiscarded) {
+		t.Errorf("Future.Err() = %v, want %v", err, ErrTaskDiscarded)
+	}
+
+	p = testPool(context.Background(), 1, testDefaultNumTasks, false, WithRateLimit(failingLimiter{}))
+	f, _ = p.SubmitFuture(testNoOpFunc)
+
+	if err := f.Err(); !errors.Is(err, testErr) {
+		t.Errorf("Future.Err() = %v, want the limiter error %v", err, testErr)
+	}
+
+	_ = p.Wait()
+}
+
+func TestWithRateLimit_skipped(t *testing.T) {
+	var l countingLimiter
+	p := testPool(context.Background(), 1, testDefaultNumTasks, false, WithRateLimit(&l))
+
+	release := make(chan struct{})
+	_ = p.Submit(func() error {
+		<-release
+		return nil
+	})
+
+	canceled, _ := p.SubmitFuture(testNoOpFunc)
+	canceled.Cancel()
+	expired, _ := p.SubmitWithDeadline(time.Now().Add(-time.Second), testNoOpFunc)
+	close(release)
+
+	if err := p.Wait(); err != nil {
+		t.Errorf("Pool.Wait() = %v, want nil", err)
+	}
+
+	if err := expired.Err(); !errors.Is(err, ErrDeadlineExceeded) {
+		t.Errorf("Future.Err() = %v, want %v", err, ErrDeadlineExceeded)
+	}
+
+	// only the task that ran took a token.
+	if n := atomic.LoadInt32(&l.n); n != 1 {
+		t.Errorf("tokens taken = %d, want 1", n)
+	}
+}
@@ -0,0 +1,294 @@
+// Package redisqueue shares the work of gowp pools across processes through a Redis list.
+//
+// It is a separate module, so that the core package doesn't depend on a Redis client.
+//
+// Any process can submit tasks to the list, the processes consuming it execute them with their own pool.
+// Closures can't travel between processes, so tasks are made of a kind and a payload. The kind names
+// a Handler registered by the consumers, the payload is whatever the handler needs, e.g. JSON.
+//
+// The list is not a backend of the queue of the pool: a Queue is a consumer of its own, that takes tasks from
+// the list as the pool has room for them and submits them to the pool like any other task.
+//
+// A consumer moves each task it takes to a processing list of its own until the task has been executed,
+// whether it failed or not. When a consumer starts, the tasks left in its processing list by a crash, or
+// discarded by a pool that stopped, are put back in the queue, so that delivery is at least once. This requires consumer ids that are stable
+// across restarts, see WithConsumerID.
+//
+// Example:
+//	rdb := redis.NewClient(&redis.Options{Addr: "localhost:6379"})
+//	wp, _ := gowp.New(100)
+//
+//	q := redisqueue.New(rdb, "emails", wp, redisqueue.Handlers{
+//		"send": func(payload []byte) error { return send(payload) },
+//	})
+//	q.Consume(ctx)
+//
+//	_ = q.Submit(ctx, "send", []byte(`{"to":"someone@example.com"}`))
+//
+//	err := q.Wait() // stops consuming and waits for the pool.
+package redisqueue // import "github.com/akshaybharambe14/gowp/redisqueue"
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/akshaybharambe14/gowp"
+	"github.com/redis/go-redis/v9"
+)
+
+// ErrUnknownKind is reported to the error handler for tasks that have no handler, they are dropped.
+const ErrUnknownKind = gowp.Error("no handler registered for the task kind")
+
+type (
+	// Handler executes a task of a given kind.
+	Handler func(payload []byte) error
+
+	// Handlers maps task kinds to their handler.
+	Handlers map[string]Handler
+
+	// Option configures a Queue.
+	Option func(q *Queue)
+
+	// Queue submits tasks to a Redis list and executes the tasks of the list with a pool.
+	//
+	// Zero value is not usable. Use New() to create a Queue.
+	Queue struct {
+		rdb      redis.UniversalClient
+		key      string
+		p        *gowp.Pool
+		handlers Handlers
+
+		id       string        // see WithConsumerID.
+		prefetch int           // see WithPrefetch.
+		poll     time.Duration // how long a blocking pop waits, so that cancellation is noticed.
+		onError  func(error)   // see WithErrorHandler.
+
+		mu     sync.Mutex
+		cancel context.CancelFunc // stops the consumer. nil, if not consuming. Guarded by mu.
+		done   chan struct{}      // closed once the consumer has returned.
+	}
+
+	// message is the representation of a task in Redis.
+	message struct {
+		ID      string `json:"id"` // makes identical tasks distinct, so that they can be removed one by one.
+		Kind    string `json:"kind"`
+		Payload []byte `json:"payload"`
+	}
+)
+
+// WithConsumerID returns an Option that sets the id of the consumer, the name of its processing list derives
+// from it. It defaults to the host name, ids should be unique among the consumers of a queue.
+func WithConsumerID(id string) Option {
+	return func(q *Queue) {
+		q.id = id
+	}
+}
+
+// WithPrefetch returns an Option that bounds the number of tasks the consumer takes from the queue and
+// hasn't executed yet. It defaults to the number of workers of the pool.
+func WithPrefetch(n int) Option {
+	return func(q *Queue) {
+		q.prefetch = n
+	}
+}
+
+// WithErrorHandler returns an Option that sets the function receiving the errors of the consumer,
+// e.g. Redis being unreachable or tasks without a handler. Errors returned by the tasks are not included,
+// the pool reports them. Errors are dropped by default.
+func WithErrorHandler(onError func(error)) Option {
+	return func(q *Queue) {
+		q.onError = onError
+	}
+}
+
+// New creates a Queue storing tasks in the Redis list named key and executing them with p.
+func New(rdb redis.UniversalClient, key string, p *gowp.Pool, handlers Handlers, opts ...Option) *Queue {
+	q := &Queue{
+		rdb:      rdb,
+		key:      key,
+		p:        p,
+		handlers: handlers,
+		poll:     time.Second,
+		onError:  func(error) {},
+	}
+
+	q.id, _ = os.Hostname()
+
+	for _, opt := range opts {
+		opt(q)
+	}
+
+	if q.prefetch <= 0 {
+		q.prefetch = p.Stats().Workers
+	}
+
+	if q.prefetch <= 0 {
+		q.prefetch = 1 // the workers of the pool have exited already.
+	}
+
+	return q
+}
+
+// Submit adds a task of the given kind to the queue, for any consumer to execute it.
+func (q *Queue) Submit(ctx context.Context, kind string, payload []byte) error {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return fmt.Errorf("redisqueue.Queue.Submit(): %w", err)
+	}
+
+	b, err := json.Marshal(message{ID: hex.EncodeToString(id), Kind: kind, Payload: payload})
+	if err != nil {
+		return fmt.Errorf("redisqueue.Queue.Submit(): %w", err)
+	}
+
+	if err := q.rdb.LPush(ctx, q.key, b).Err(); err != nil {
+		return fmt.Errorf("redisqueue.Queue.Submit(): %w", err)
+	}
+
+	return nil
+}
+
+// Consume starts executing the tasks of the queue with the pool, until ctx is done or Wait is called.
+// Tasks left in the processing list of the consumer are put back in the queue first.
+// Calling Consume while consuming has no effect.
+func (q *Queue) Consume(ctx context.Context) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.cancel != nil {
+		return
+	}
+
+	ctx, q.cancel = context.WithCancel(ctx)
+	q.done = make(chan struct{})
+
+	go q.consume(ctx)
+}
+
+// Wait stops consuming, then waits for the pool, see gowp.Pool.Wait. Tasks taken from the queue are executed.
+func (q *Queue) Wait() error {
+	q.mu.Lock()
+	cancel, done := q.cancel, q.done
+	q.mu.Unlock()
+
+	if cancel != nil {
+		cancel()
+		<-done
+	}
+
+	return q.p.Wait()
+}
+
+func (q *Queue) consume(ctx context.Context) {
+	defer close(q.done)
+
+	processing := q.key + ":processing:" + q.id
+	q.requeue(ctx, processing)
+
+	slots := make(chan struct{}, q.prefetch)
+	for {
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+			return
+		}
+
+		b, err := q.rdb.BLMove(ctx, q.key, processing, "RIGHT", "LEFT", q.poll).Bytes()
+		if err != nil {
+			<-slots
+
+			switch {
+			case ctx.Err() != nil:
+				return
+			case errors.Is(err, redis.Nil):
+				continue // nothing to do within the poll timeout.
+			}
+
+			q.onError(fmt.Errorf("redisqueue: taking a task: %w", err))
+			q.pause(ctx)
+
+			continue
+		}
+
+		var f *gowp.Future
+		task, err := q.task(b)
+		if err == nil {
+			f, err = q.p.SubmitFuture(func() error {
+				defer q.ack(processing, b)
+
+				return task()
+			})
+		}
+
+		if err != nil {
+			<-slots
+			q.onError(fmt.Errorf("redisqueue: submitting a task: %w", err))
+			q.ack(processing, b) // the task can't be executed by this consumer.
+
+			continue
+		}
+
+		go func() {
+			// a task discarded by the pool is left in the processing list, it is put back in the queue
+			// the next time the consumer starts.
+			<-f.Done()
+			<-slots
+		}()
+	}
+}
+
+// task returns the pool task executing the message b.
+func (q *Queue) task(b []byte) (gowp.Task, error) {
+	var m message
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, err
+	}
+
+	h, ok := q.handlers[m.Kind]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownKind, m.Kind)
+	}
+
+	return func() error { return h(m.Payload) }, nil
+}
+
+// ack removes an executed task from the processing list.
+func (q *Queue) ack(processing string, b []byte) {
+	// the consumer may have stopped, the task is done anyway.
+	if err := q.rdb.LRem(context.Background(), processing, 1, b).Err(); err != nil {
+		q.onError(fmt.Errorf("redisqueue: acknowledging a task: %w", err))
+	}
+}
+
+// requeue puts the tasks left in the processing list back in the queue.
+func (q *Queue) requeue(ctx context.Context, processing string) {
+	for {
+		err := q.rdb.LMove(ctx, processing, q.key, "RIGHT", "RIGHT").Err()
+		if errors.Is(err, redis.Nil) {
+			return
+		}
+
+		if err != nil {
+			q.onError(fmt.Errorf("redisqueue: recovering tasks: %w", err))
+			return
+		}
+	}
+}
+
+// pause backs off after an error, so that an unreachable server isn't hammered.
+func (q *Queue) pause(ctx context.Context) {
+	t := time.NewTimer(q.poll)
+	defer t.Stop()
+
+	select {
+	case <-t.C:
+	case <-ctx.Done():
+	}
+}
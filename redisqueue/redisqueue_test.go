@@ -0,0 +1,147 @@
+package redisqueue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/akshaybharambe14/gowp"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func TestQueue(t *testing.T) {
+	srv := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: srv.Addr()})
+
+	var (
+		mu  sync.Mutex
+		ran []string
+	)
+	handlers := Handlers{
+		"echo": func(payload []byte) error {
+			mu.Lock()
+			ran = append(ran, string(payload))
+			mu.Unlock()
+			return nil
+		},
+	}
+
+	// a task left behind by a crashed run of the consumer.
+	left, _ := json.Marshal(message{ID: "1", Kind: "echo", Payload: []byte("left")})
+	srv.Lpush("tasks:processing:c1", string(left))
+
+	var (
+		errMu sync.Mutex
+		errs  []error
+	)
+	onError := func(err error) {
+		errMu.Lock()
+		errs = append(errs, err)
+		errMu.Unlock()
+	}
+
+	p, _ := gowp.New(10, gowp.WithNumWorkers(2))
+	q := New(rdb, "tasks", p, handlers, WithConsumerID("c1"), WithErrorHandler(onError))
+	q.poll = 10 * time.Millisecond
+
+	ctx := context.Background()
+	for _, s := range []string{"a", "b", "c"} {
+		if err := q.Submit(ctx, "echo", []byte(s)); err != nil {
+			t.Fatalf("Queue.Submit() error = %v", err)
+		}
+	}
+	_ = q.Submit(ctx, "unknown", nil)
+
+	q.Consume(ctx)
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		mu.Lock()
+		n := len(ran)
+		mu.Unlock()
+
+		if n == 4 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	if err := q.Wait(); err != nil {
+		t.Errorf("Queue.Wait() = %v, want nil", err)
+	}
+
+	sort.Strings(ran)
+	if want := []string{"a", "b", "c", "left"}; len(ran) != len(want) || ran[0] != "a" || ran[3] != "left" {
+		t.Errorf("executed %v, want %v", ran, want)
+	}
+
+	if len(errs) != 1 || !errors.Is(errs[0], ErrUnknownKind) {
+		t.Errorf("errors = %v, want %v", errs, ErrUnknownKind)
+	}
+
+	for _, key := range []string{"tasks", "tasks:processing:c1"} {
+		if n, _ := rdb.LLen(ctx, key).Result(); n != 0 {
+			t.Errorf("%s holds %d tasks, want none", key, n)
+		}
+	}
+}
+
+func TestQueue_discarded(t *testing.T) {
+	srv := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: srv.Addr()})
+
+	ran := make(chan string, 2)
+	handlers := Handlers{
+		"echo": func(payload []byte) error {
+			ran <- string(payload)
+			return nil
+		},
+	}
+
+	p, _ := gowp.New(10, gowp.WithNumWorkers(1))
+	q := New(rdb, "tasks", p, handlers, WithConsumerID("c1"), WithPrefetch(1))
+	q.poll = 10 * time.Millisecond
+
+	// keep the worker busy, so that the task taken from the queue waits in the pool.
+	release := make(chan struct{})
+	_ = p.Submit(func() error { <-release; return nil })
+
+	ctx := context.Background()
+	_ = q.Submit(ctx, "echo", []byte("a"))
+	q.Consume(ctx)
+
+	deadline := time.Now().Add(time.Second)
+	for p.Pending() == 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+
+	if n := p.Purge(); n != 1 {
+		t.Fatalf("Pool.Purge() = %d, want 1", n)
+	}
+	close(release)
+
+	// the slot of the discarded task is free again.
+	_ = q.Submit(ctx, "echo", []byte("b"))
+
+	select {
+	case got := <-ran:
+		if got != "b" {
+			t.Errorf("executed %q, want %q", got, "b")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("the consumer stalled after a task was discarded")
+	}
+
+	if err := q.Wait(); err != nil {
+		t.Errorf("Queue.Wait() = %v, want nil", err)
+	}
+
+	if n, _ := rdb.LLen(ctx, "tasks:processing:c1").Result(); n != 1 {
+		t.Errorf("processing list holds %d tasks, want the discarded one", n)
+	}
+}
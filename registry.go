@@ -0,0 +1,65 @@
+package gowp
+
+import (
+	"fmt"
+	"sync"
+)
+
+// registry holds the pools registered by name, see Register.
+var registry = struct {
+	mu    sync.RWMutex
+	pools map[string]*Pool
+}{pools: make(map[string]*Pool)}
+
+// Register makes p available process-wide under name, so that packages can share it, see Lookup, and so that
+// metrics and admin endpoints can enumerate the pools of the process, see Registered. A pool stays registered
+// until Unregister is called, even once it has completed. It fails with ErrNameTaken if name is registered already.
+func Register(name string, p *Pool) error {
+	switch {
+	case name == "":
+		return fmt.Errorf("gowp.Register(): %w", ErrInvalidName)
+	case p == nil:
+		return fmt.Errorf("gowp.Register(): %w", ErrNilPool)
+	}
+
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	if _, ok := registry.pools[name]; ok {
+		return fmt.Errorf("gowp.Register(): %q: %w", name, ErrNameTaken)
+	}
+
+	registry.pools[name] = p
+
+	return nil
+}
+
+// Unregister removes the pool registered under name, if any.
+func Unregister(name string) {
+	registry.mu.Lock()
+	delete(registry.pools, name)
+	registry.mu.Unlock()
+}
+
+// Lookup returns the pool registered under name and whether there is one.
+func Lookup(name string) (*Pool, bool) {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+
+	p, ok := registry.pools[name]
+
+	return p, ok
+}
+
+// Registered returns the registered pools by name. The map is a copy, it can be modified freely.
+func Registered() map[string]*Pool {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+
+	pools := make(map[string]*Pool, len(registry.pools))
+	for name, p := range registry.pools {
+		pools[name] = p
+	}
+
+	return pools
+}
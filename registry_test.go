@@ -0,0 +1,53 @@
+package gowp
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRegister(t *testing.T) {
+	p, err := New(1)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer func() {
+		p.Close()
+		_ = p.Wait()
+	}()
+
+	t.Cleanup(func() { Unregister("emailer") })
+
+	tests := []struct {
+		name     string
+		poolName string
+		pool     *Pool
+		wantErr  error
+	}{
+		{name: "registers", poolName: "emailer", pool: p},
+		{name: "duplicate name", poolName: "emailer", pool: p, wantErr: ErrNameTaken},
+		{name: "empty name", poolName: "", pool: p, wantErr: ErrInvalidName},
+		{name: "nil pool", poolName: "resizer", pool: nil, wantErr: ErrNilPool},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Register(tt.poolName, tt.pool)
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil && err != nil) {
+				t.Errorf("Register() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	if got, ok := Lookup("emailer"); !ok || got != p {
+		t.Errorf("Lookup() = %p, %v, want %p, true", got, ok, p)
+	}
+
+	if got := Registered(); len(got) != 1 || got["emailer"] != p {
+		t.Errorf("Registered() = %v, want only emailer", got)
+	}
+
+	Unregister("emailer")
+	if _, ok := Lookup("emailer"); ok {
+		t.Error("Lookup() found a pool after Unregister")
+	}
+}
@@ -0,0 +1,154 @@
+package gowp
+
+import "sync/atomic"
+
+type (
+	// Report summarizes the execution of a pool that has completed.
+	Report struct {
+		Err       error // the first error that occurred in the execution, same as the one reported by Wait.
+		Submitted int   // tasks accepted by the pool.
+		Succeeded int   // tasks that returned nil.
+		Failed    int   // tasks that returned an error.
+		Ignored   int   // tasks that returned an error classified as SeverityIgnore, they are not counted as failed.
+		Canceled  int   // tasks cancelled through their Future before they started.
+		Discarded int   // tasks dropped without execution, because the pool stopped early.
+		Expired   int   // tasks dropped without execution, because their deadline passed while queued.
+	}
+
+	// counters track the outcome of tasks. Fields should be manipulated by sync/atomic.
+	counters struct {
+		seq        uint64 // last assigned job id.
+		tombstones int64  // cancelled jobs that are still in the queue.
+		restarts   int64  // workers replaced after a task panicked.
+		submitted  int64
+		succeeded  int64
+		failed     int64
+		ignored    int64
+		canceled   int64
+		discarded  int64
+		expired    int64
+	}
+
+	afterFunc struct {
+		fn func(Report)
+	}
+)
+
+// AfterFunc arranges to call fn in its own goroutine once the pool has completed, i.e. once Wait has
+// finished waiting for all the tasks. If the pool has already completed, fn is called immediately
+// in its own goroutine. AfterFunc doesn't close the pool, Wait still needs to be called, see Done.
+//
+// Calling the returned stop function stops the association of fn with the pool.
+// It returns true if the call stopped fn from being run, mirroring context.AfterFunc.
+// A panic in fn is recovered, see WithHookErrorHandler.
+func (p *Pool) AfterFunc(fn func(Report)) (stop func() bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.completed {
+		r := p.report()
+		go p.guard("AfterFunc", TaskInfo{}, func() { fn(r) })
+		return func() bool { return false }
+	}
+
+	af := &afterFunc{fn: fn}
+	if p.afterFuncs == nil {
+		p.afterFuncs = make(map[*afterFunc]struct{})
+	}
+	p.afterFuncs[af] = struct{}{}
+
+	return func() bool {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+
+		if _, ok := p.afterFuncs[af]; !ok {
+			return false
+		}
+
+		delete(p.afterFuncs, af)
+
+		return true
+	}
+}
+
+// Done returns a channel that is closed once the pool has completed, i.e. once its tasks have been executed
+// or discarded and Wait would return right away. It lets callers select over the completion of the pool along
+// with other channels.
+//
+// Once Done has been called, closing the pool or stopping it, e.g. on error, is enough for it to complete,
+// Wait doesn't need to be called anymore. It still reports the error of the pool.
+//
+//	wp.Close()
+//	select {
+//	case <-wp.Done():
+//		err := wp.Wait()
+//	case <-ctx.Done():
+//	}
+func (p *Pool) Done() <-chan struct{} {
+	p.settling.Do(func() {
+		go func() {
+			select {
+			case <-p.closing:
+			case <-p.quit:
+			case <-p.done:
+				return
+			}
+
+			_ = p.wait()
+		}()
+	})
+
+	return p.done
+}
+
+// complete marks the pool as completed with err and runs the registered AfterFunc callbacks.
+func (p *Pool) complete(err error) {
+	r := p.tally()
+	r.Err = err
+
+	p.mu.Lock()
+	p.err = err
+	p.completed = true
+	if err != nil {
+		p.halts(err)
+	} else {
+		p.halts(ErrPoolClosed)
+	}
+	close(p.done)
+	afs := p.afterFuncs
+	p.afterFuncs = nil
+	p.mu.Unlock()
+
+	p.emit(Event{Kind: EventCompleted, Err: err})
+	p.progress()
+
+	if p.parent != nil {
+		p.parent.orphan(p)
+	}
+
+	for af := range afs {
+		fn := af.fn
+		go p.guard("AfterFunc", TaskInfo{}, func() { fn(r) })
+	}
+}
+
+// report returns the Report of a pool that has completed. p.mu must be held.
+func (p *Pool) report() Report {
+	r := p.tally()
+	r.Err = p.err
+
+	return r
+}
+
+// tally counts the outcome of tasks so far. Unlike report, it doesn't read the error, which Wait sets.
+func (p *Pool) tally() Report {
+	return Report{
+		Submitted: int(atomic.LoadInt64(&p.counts.submitted)),
+		Succeeded: int(atomic.LoadInt64(&p.counts.succeeded)),
+		Failed:    int(atomic.LoadInt64(&p.counts.failed)),
+		Ignored:   int(atomic.LoadInt64(&p.counts.ignored)),
+		Canceled:  int(atomic.LoadInt64(&p.counts.canceled)),
+		Discarded: int(atomic.LoadInt64(&p.counts.discarded)),
+		Expired:   int(atomic.LoadInt64(&p.counts.expired)),
+	}
+}
@@ -0,0 +1,97 @@
+package gowp
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestPool_AfterFunc(t *testing.T) {
+	p := testPool(context.Background(), 1, testDefaultNumTasks, false)
+
+	release := make(chan struct{})
+	_ = p.Submit(func() error {
+		<-release
+		return testErr
+	})
+	_ = p.Submit(testNoOpFunc)
+	canceled, _ := p.SubmitFuture(testNoOpFunc)
+	canceled.Cancel()
+
+	reports := make(chan Report, 1)
+	p.AfterFunc(func(r Report) { reports <- r })
+
+	stop := p.AfterFunc(func(Report) { t.Error("stopped AfterFunc was called") })
+	if !stop() {
+		t.Error("stop() = false, want true")
+	}
+
+	close(release)
+	_ = p.Wait()
+
+	r := <-reports
+	if !errors.Is(r.Err, testErr) {
+		t.Errorf("Report.Err = %v, want %v", r.Err, testErr)
+	}
+
+	want := Report{Err: r.Err, Submitted: 3, Succeeded: 1, Failed: 1, Canceled: 1}
+	if r != want {
+		t.Errorf("Report = %+v, want %+v", r, want)
+	}
+
+	// registering after completion runs the callback right away.
+	late := make(chan Report, 1)
+	if p.AfterFunc(func(r Report) { late <- r })() {
+		t.Error("stop() = true after completion, want false")
+	}
+
+	if r := <-late; r != want {
+		t.Errorf("Report = %+v, want %+v", r, want)
+	}
+}
+
+func TestPool_Done(t *testing.T) {
+	tests := []struct {
+		name      string
+		exitOnErr bool
+		task      Task
+		close     bool
+		wantErr   error
+	}{
+		{name: "closed", task: testNoOpFunc, close: true},
+		{name: "stopped on error", exitOnErr: true, task: testFuncWithErr, wantErr: testErr},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := testPool(context.Background(), 1, testDefaultNumTasks, tt.exitOnErr)
+
+			release := make(chan struct{})
+			_ = p.Submit(func() error { <-release; return nil })
+			_ = p.Submit(tt.task)
+
+			done := p.Done()
+			if tt.close {
+				p.Close()
+			}
+
+			select {
+			case <-done:
+				t.Fatal("Pool.Done() closed before the tasks have finished")
+			case <-time.After(10 * time.Millisecond):
+			}
+
+			close(release)
+
+			select {
+			case <-done:
+			case <-time.After(time.Second):
+				t.Fatal("Pool.Done() not closed after the tasks have finished")
+			}
+
+			if err := p.Wait(); !errors.Is(err, tt.wantErr) || (err == nil) != (tt.wantErr == nil) {
+				t.Errorf("Pool.Wait() = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
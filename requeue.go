@@ -0,0 +1,105 @@
+package gowp
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+// WithRequeue returns an Option that keeps the tasks that fail, so that RequeueFailed can submit them again.
+// Tasks are kept until they are requeued, along with everything their closures hold, so a pool that keeps
+// failing without RequeueFailed being called grows. Errors classified as SeverityIgnore are not kept.
+func WithRequeue() Option {
+	return func(o *config) {
+		o.requeue = true
+	}
+}
+
+// Requeue submits the task of f again, with the same options, once it has failed, e.g. after the caller fixed
+// what made it fail. It returns the Future of the new attempt. Only tasks submitted with SubmitFuture that were
+// executed and returned an error can be requeued, once. It fails with ErrNotRequeueable otherwise, and for the
+// same reasons as SubmitFuture.
+func (f *Future) Requeue() (*Future, error) {
+	j := f.job.Load()
+
+	select {
+	case <-f.done:
+	default:
+		j = nil // still queued or running.
+	}
+
+	if j == nil || !f.requeueable || f.err == nil || atomic.LoadUint32(&f.state) != taskRunning {
+		return nil, fmt.Errorf("gowp.Future.Requeue(): %w", ErrNotRequeueable)
+	}
+
+	if !atomic.CompareAndSwapUint32(&j.requeued, 0, 1) {
+		return nil, fmt.Errorf("gowp.Future.Requeue(): %w", ErrNotRequeueable)
+	}
+
+	nf, err := f.pool.resubmit(j)
+	if err != nil {
+		atomic.StoreUint32(&j.requeued, 0) // it can be tried again.
+		return nil, fmt.Errorf("gowp.Future.Requeue(): %w", err)
+	}
+
+	return nf, nil
+}
+
+// RequeueFailed submits again the tasks that failed since the last call, see WithRequeue, with the same options.
+// It returns the number of tasks requeued. It stops at the first task that can't be submitted, e.g. because
+// the queue is full, and returns the error, the remaining tasks are kept for the next call. Tasks requeued
+// through their Future already are skipped.
+func (p *Pool) RequeueFailed() (int, error) {
+	p.mu.Lock()
+	jobs := p.retained
+	p.retained = nil
+	p.mu.Unlock()
+
+	n := 0
+	for i, j := range jobs {
+		if !atomic.CompareAndSwapUint32(&j.requeued, 0, 1) {
+			continue
+		}
+
+		if _, err := p.resubmit(j); err != nil {
+			atomic.StoreUint32(&j.requeued, 0)
+
+			p.mu.Lock()
+			p.retained = append(jobs[i:len(jobs):len(jobs)], p.retained...)
+			p.mu.Unlock()
+
+			return n, fmt.Errorf("gowp.Pool.RequeueFailed(): %w", err)
+		}
+
+		n++
+	}
+
+	return n, nil
+}
+
+// resubmit submits the task of j again, with the same options. It returns the Future of the new attempt, if j had one.
+func (p *Pool) resubmit(j *job) (*Future, error) {
+	if j.fut == nil {
+		return nil, p.submit(j.task, nil, j.opts)
+	}
+
+	f := newFuture()
+	f.requeueable = true
+
+	if err := p.submit(j.task, f, j.opts); err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+// requeueable reports whether the task of j can be submitted again as is, see Future.Requeue.
+func (j *job) requeueable() bool {
+	return j.fut == nil || j.fut.requeueable
+}
+
+// retain keeps j for RequeueFailed.
+func (p *Pool) retain(j *job) {
+	p.mu.Lock()
+	p.retained = append(p.retained, j)
+	p.mu.Unlock()
+}
@@ -0,0 +1,130 @@
+package gowp
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+)
+
+func TestFuture_Requeue(t *testing.T) {
+	errBroken := errors.New("broken")
+
+	p, err := New(4, WithNumWorkers(1))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	var fixed, runs int32
+	task := func() error {
+		atomic.AddInt32(&runs, 1)
+		if atomic.LoadInt32(&fixed) == 0 {
+			return errBroken
+		}
+		return nil
+	}
+
+	f, err := p.SubmitFuture(task, TaskName("fragile"))
+	if err != nil {
+		t.Fatalf("SubmitFuture() error = %v", err)
+	}
+
+	ok, _ := p.SubmitFuture(testNoOpFunc)
+	typed := &TypedFuture[int]{Future: newFuture()}
+	_ = p.submit(func() error { return errBroken }, typed.Future, nil)
+
+	tests := []struct {
+		name    string
+		f       *Future
+		wantErr error
+	}{
+		{name: "failed", f: f},
+		{name: "twice", f: f, wantErr: ErrNotRequeueable},
+		{name: "succeeded", f: ok, wantErr: ErrNotRequeueable},
+		{name: "not submitted with SubmitFuture", f: typed.Future, wantErr: ErrNotRequeueable},
+	}
+
+	_ = f.Err()
+	_ = ok.Err()
+	_ = typed.Err()
+	atomic.StoreInt32(&fixed, 1)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nf, err := tt.f.Requeue()
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil && err != nil) {
+				t.Fatalf("Requeue() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if err != nil {
+				return
+			}
+
+			if err := nf.Err(); err != nil {
+				t.Errorf("requeued task error = %v, want nil", err)
+			}
+		})
+	}
+
+	if got := atomic.LoadInt32(&runs); got != 2 {
+		t.Errorf("task ran %d times, want 2", got)
+	}
+
+	_ = p.Wait()
+}
+
+func TestPool_RequeueFailed(t *testing.T) {
+	errBroken := errors.New("broken")
+
+	tests := []struct {
+		name      string
+		opts      []Option
+		wantFirst int // tasks requeued by the first call.
+	}{
+		{name: "with requeue", opts: []Option{WithRequeue()}, wantFirst: 3},
+		{name: "without requeue", wantFirst: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := New(8, append([]Option{WithNumWorkers(1)}, tt.opts...)...)
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+
+			var fixed, succeeded int32
+			task := func() error {
+				if atomic.LoadInt32(&fixed) == 0 {
+					return errBroken
+				}
+				atomic.AddInt32(&succeeded, 1)
+				return nil
+			}
+
+			_ = p.Submit(task)
+			_ = p.Submit(task)
+			_, _ = p.SubmitFuture(task)
+
+			// a single worker executes in order, the failures are retained once the last task is done.
+			last, _ := p.SubmitFuture(testNoOpFunc)
+			_ = last.Err()
+			atomic.StoreInt32(&fixed, 1)
+
+			n, err := p.RequeueFailed()
+			if err != nil || n != tt.wantFirst {
+				t.Fatalf("RequeueFailed() = %d, %v, want %d, nil", n, err, tt.wantFirst)
+			}
+
+			if n, _ := p.RequeueFailed(); n != 0 {
+				t.Errorf("RequeueFailed() again = %d, want 0", n)
+			}
+
+			if err := p.Wait(); !errors.Is(err, errBroken) {
+				t.Errorf("Wait() error = %v, want %v", err, errBroken)
+			}
+
+			if got := atomic.LoadInt32(&succeeded); got != int32(tt.wantFirst) {
+				t.Errorf("requeued tasks succeeded %d times, want %d", got, tt.wantFirst)
+			}
+		})
+	}
+}
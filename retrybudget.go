@@ -0,0 +1,62 @@
+package gowp
+
+import (
+	"sync"
+	"time"
+)
+
+// retryBudget bounds the redeliveries of the pool, see WithRetryBudget.
+type retryBudget struct {
+	perSecond float64
+	ratio     float64
+
+	mu      sync.Mutex
+	start   time.Time // start of the current one second window.
+	firsts  int       // first deliveries within the window.
+	retries int       // redeliveries within the window.
+}
+
+// WithRetryBudget returns an Option that bounds the redeliveries of the tasks submitted with SubmitAcked,
+// so that a broken downstream doesn't turn into a retry storm consuming the workers. Within each second,
+// at most perSecond redeliveries are allowed, plus ratio of the first deliveries of the same second, e.g.
+// WithRetryBudget(10, 0.2) allows 10 redeliveries per second plus one for every five tasks submitted.
+// A task that would be delivered again once the budget is spent is given up on, its Future reports
+// ErrRetryBudgetExhausted. There is no budget by default. perSecond and ratio should not be negative,
+// otherwise ErrInvalidRetryBudget will be returned on Pool initialization.
+func WithRetryBudget(perSecond, ratio float64) Option {
+	return func(o *config) {
+		o.retries = &retryBudget{perSecond: perSecond, ratio: ratio}
+	}
+}
+
+// deposit records a first delivery.
+func (b *retryBudget) deposit(now time.Time) {
+	b.mu.Lock()
+	b.roll(now)
+	b.firsts++
+	b.mu.Unlock()
+}
+
+// withdraw reports whether a redelivery is allowed, and records it if so.
+func (b *retryBudget) withdraw(now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.roll(now)
+	if float64(b.retries+1) > b.perSecond+b.ratio*float64(b.firsts) {
+		return false
+	}
+
+	b.retries++
+
+	return true
+}
+
+// roll starts a new window once a second has elapsed since the current one started. b.mu must be held.
+func (b *retryBudget) roll(now time.Time) {
+	if now.Sub(b.start) < time.Second {
+		return
+	}
+
+	b.start, b.firsts, b.retries = now, 0, 0
+}
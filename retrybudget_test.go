@@ -0,0 +1,48 @@
+package gowp
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRetryBudget(t *testing.T) {
+	b := &retryBudget{perSecond: 1, ratio: 0.5}
+	now := time.Now()
+
+	steps := []struct {
+		name   string
+		at     time.Duration
+		first  bool // a first delivery, deposited instead of withdrawn.
+		wantOK bool
+	}{
+		{name: "rate", wantOK: true},
+		{name: "rate spent"},
+		{name: "first delivery", first: true},
+		{name: "one first isn't a whole retry"},
+		{name: "second delivery", first: true},
+		{name: "ratio", wantOK: true},
+		{name: "ratio spent", at: 500 * time.Millisecond},
+		{name: "next second", at: time.Second, wantOK: true},
+		{name: "next second spent", at: 1500 * time.Millisecond},
+	}
+
+	for _, s := range steps {
+		t.Run(s.name, func(t *testing.T) {
+			if s.first {
+				b.deposit(now.Add(s.at))
+				return
+			}
+
+			if ok := b.withdraw(now.Add(s.at)); ok != s.wantOK {
+				t.Fatalf("retryBudget.withdraw() = %v, want %v", ok, s.wantOK)
+			}
+		})
+	}
+}
+
+func TestWithRetryBudget_invalid(t *testing.T) {
+	if _, err := New(1, WithRetryBudget(-1, 0)); !errors.Is(err, ErrInvalidRetryBudget) {
+		t.Errorf("New() error = %v, want %v", err, ErrInvalidRetryBudget)
+	}
+}
@@ -0,0 +1,410 @@
+package gowp
+
+import (
+	"container/heap"
+	"time"
+)
+
+// SchedulingPolicy decides the order in which queued tasks are handed to workers.
+type SchedulingPolicy int
+
+const (
+	// FIFO runs tasks in submission order. It is the default policy.
+	FIFO SchedulingPolicy = iota
+	// LongestFirst runs the tasks with the longest expected duration first, see TaskDuration.
+	// Starting long tasks early minimizes the time needed to finish a batch (LPT scheduling).
+	// Tasks without a hint are run last.
+	LongestFirst
+	// ShortestFirst runs the tasks with the shortest expected duration first, see TaskDuration.
+	// It minimizes the average waiting time of the tasks. Tasks without a hint are run first.
+	ShortestFirst
+	// LIFO runs the most recently submitted tasks first, see WithLIFO.
+	LIFO
+	// EarliestDeadline runs the tasks with the earliest deadline first, see TaskDeadline.
+	// Tasks without a deadline are run last, in submission order.
+	EarliestDeadline
+	// HighestPriority runs the tasks with the highest priority first, see TaskPriority and Future.SetPriority.
+	// Tasks with the same priority are run in submission order.
+	HighestPriority
+)
+
+// durationQueue is a heap of jobs ordered by their expected duration, or by another key, see newDeadlineQueue
+// and newPriorityQueue.
+// Jobs with the same duration are popped in submission order.
+type durationQueue struct {
+	jobs         durationHeap
+	longestFirst bool
+}
+
+// lifo is a stack of jobs, the last pushed is popped first.
+type lifo struct {
+	jobs []*job
+}
+
+// agingQueue pops the oldest job of the queue it wraps once it has waited for maxAge, see WithAging.
+// The jobs are indexed in submission order, so that the oldest one is found without scanning the queue.
+type agingQueue struct {
+	queue
+	maxAge time.Duration
+	clock  Clock
+
+	order  durationHeap      // the jobs pushed, oldest first, including the ones that left since, see queued.
+	queued map[*job]struct{} // the jobs of order still in the queue, the others are dropped from order lazily.
+}
+
+// durationHeap implements heap.Interface, less is set by the owning durationQueue.
+type durationHeap struct {
+	jobs []*job
+	less func(a, b *job) bool
+}
+
+// interface guards
+var (
+	_ queue          = (*durationQueue)(nil)
+	_ queue          = (*lifo)(nil)
+	_ queue          = (*agingQueue)(nil)
+	_ heap.Interface = (*durationHeap)(nil)
+)
+
+// WithSchedulingPolicy returns an Option that sets the order in which queued tasks are run.
+// Tasks carry their expected duration with TaskDuration. Along with WithWeightedRandomDispatch,
+// the policy orders the tasks within each sub-queue.
+// An unknown policy results in ErrInvalidPolicy on Pool initialization.
+func WithSchedulingPolicy(policy SchedulingPolicy) Option {
+	return func(o *config) {
+		o.policy = policy
+	}
+}
+
+// WithLIFO returns an Option that runs the most recently submitted tasks first, e.g. for latest-wins workloads
+// such as recomputing a view, where the last request matters most and older ones may be cancelled or are less
+// likely to be waited for. Tasks queued early may wait long under sustained load. It is a shorthand for
+// WithSchedulingPolicy(LIFO).
+func WithLIFO() Option {
+	return WithSchedulingPolicy(LIFO)
+}
+
+// WithAging returns an Option that keeps the scheduling policy from starving tasks: a task queued for maxAge
+// or longer is run before the others, whatever the policy, the oldest first. Without it, a steady stream of
+// short tasks may hold back a long one forever under ShortestFirst, for instance. It has no effect under FIFO.
+// Zero, the default, disables aging. A negative maxAge results in ErrInvalidAging on Pool initialization.
+func WithAging(maxAge time.Duration) Option {
+	return func(o *config) {
+		o.maxAge = maxAge
+	}
+}
+
+// TaskDuration returns a TaskOption that sets the expected run time of the task.
+// It is only a hint for the scheduler, see WithSchedulingPolicy. The task isn't interrupted if it runs longer.
+func TaskDuration(d time.Duration) TaskOption {
+	return func(j *job) {
+		j.duration = d
+	}
+}
+
+func newDurationQueue(longestFirst bool) *durationQueue {
+	q := &durationQueue{longestFirst: longestFirst}
+	q.jobs.less = q.less
+
+	return q
+}
+
+// newDeadlineQueue returns a durationQueue that pops the job with the earliest deadline first.
+func newDeadlineQueue() *durationQueue {
+	q := &durationQueue{}
+	q.jobs.less = earlierDeadline
+
+	return q
+}
+
+// newPriorityQueue returns a durationQueue that pops the job with the highest priority first.
+func newPriorityQueue() *durationQueue {
+	q := &durationQueue{}
+	q.jobs.less = higherPriority
+
+	return q
+}
+
+func (q *durationQueue) push(j *job) error {
+	heap.Push(&q.jobs, j)
+
+	return nil
+}
+
+func (q *durationQueue) pop() *job {
+	if len(q.jobs.jobs) == 0 {
+		return nil
+	}
+
+	return heap.Pop(&q.jobs).(*job)
+}
+
+func (q *durationQueue) popIf(match func(*job) bool) *job {
+	var first *job
+	for _, j := range q.jobs.jobs {
+		if match(j) && (first == nil || q.jobs.less(j, first)) {
+			first = j
+		}
+	}
+
+	if first != nil {
+		q.removeIf(func(j *job) bool { return j == first })
+	}
+
+	return first
+}
+
+func (q *durationQueue) len() int {
+	return len(q.jobs.jobs)
+}
+
+func (q *durationQueue) oldest() *job {
+	var oldest *job
+	for _, j := range q.jobs.jobs {
+		if oldest == nil || j.id < oldest.id {
+			oldest = j
+		}
+	}
+
+	return oldest
+}
+
+func (q *durationQueue) removeIf(drop func(*job) bool) []*job {
+	var removed []*job
+
+	kept := q.jobs.jobs[:0]
+	for _, j := range q.jobs.jobs {
+		if drop(j) {
+			removed = append(removed, j)
+			continue
+		}
+
+		kept = append(kept, j)
+	}
+
+	for i := len(kept); i < len(q.jobs.jobs); i++ {
+		q.jobs.jobs[i] = nil
+	}
+
+	q.jobs.jobs = kept
+	heap.Init(&q.jobs)
+
+	return removed
+}
+
+func (q *durationQueue) less(a, b *job) bool {
+	if a.duration == b.duration {
+		return a.id < b.id
+	}
+
+	if q.longestFirst {
+		return a.duration > b.duration
+	}
+
+	return a.duration < b.duration
+}
+
+func earlierDeadline(a, b *job) bool {
+	switch {
+	case a.deadline.Equal(b.deadline):
+		return a.id < b.id
+	case a.deadline.IsZero():
+		return false
+	case b.deadline.IsZero():
+		return true
+	}
+
+	return a.deadline.Before(b.deadline)
+}
+
+func higherPriority(a, b *job) bool {
+	if a.priority == b.priority {
+		return a.id < b.id
+	}
+
+	return a.priority > b.priority
+}
+
+func (h *durationHeap) Len() int           { return len(h.jobs) }
+func (h *durationHeap) Less(i, k int) bool { return h.less(h.jobs[i], h.jobs[k]) }
+func (h *durationHeap) Swap(i, k int)      { h.jobs[i], h.jobs[k] = h.jobs[k], h.jobs[i] }
+func (h *durationHeap) Push(x interface{}) { h.jobs = append(h.jobs, x.(*job)) }
+
+func (h *durationHeap) Pop() interface{} {
+	last := len(h.jobs) - 1
+	j := h.jobs[last]
+	h.jobs[last] = nil // let the GC collect the job once it is done.
+	h.jobs = h.jobs[:last]
+
+	return j
+}
+
+func (q *lifo) push(j *job) error {
+	q.jobs = append(q.jobs, j)
+
+	return nil
+}
+
+func (q *lifo) pop() *job {
+	if len(q.jobs) == 0 {
+		return nil
+	}
+
+	last := len(q.jobs) - 1
+	j := q.jobs[last]
+	q.jobs[last] = nil // let the GC collect the job once it is done.
+	q.jobs = q.jobs[:last]
+
+	return j
+}
+
+func (q *lifo) popIf(match func(*job) bool) *job {
+	for i := len(q.jobs) - 1; i >= 0; i-- {
+		if j := q.jobs[i]; match(j) {
+			copy(q.jobs[i:], q.jobs[i+1:])
+			q.jobs[len(q.jobs)-1] = nil // let the GC collect the job once it is done.
+			q.jobs = q.jobs[:len(q.jobs)-1]
+
+			return j
+		}
+	}
+
+	return nil
+}
+
+func (q *lifo) len() int {
+	return len(q.jobs)
+}
+
+func (q *lifo) oldest() *job {
+	if len(q.jobs) == 0 {
+		return nil
+	}
+
+	return q.jobs[0]
+}
+
+func (q *lifo) removeIf(drop func(*job) bool) []*job {
+	var removed []*job
+
+	kept := q.jobs[:0]
+	for _, j := range q.jobs {
+		if drop(j) {
+			removed = append(removed, j)
+			continue
+		}
+
+		kept = append(kept, j)
+	}
+
+	for i := len(kept); i < len(q.jobs); i++ {
+		q.jobs[i] = nil
+	}
+
+	q.jobs = kept
+
+	return removed
+}
+
+func newAgingQueue(q queue, maxAge time.Duration, clock Clock) *agingQueue {
+	a := &agingQueue{queue: q, maxAge: maxAge, clock: clock, queued: make(map[*job]struct{})}
+	a.order.less = func(a, b *job) bool { return a.id < b.id }
+
+	return a
+}
+
+func (q *agingQueue) push(j *job) error {
+	if err := q.queue.push(j); err != nil {
+		return err
+	}
+
+	heap.Push(&q.order, j)
+	q.queued[j] = struct{}{}
+
+	return nil
+}
+
+func (q *agingQueue) pop() *job {
+	if oldest := q.aged(); oldest != nil {
+		return q.take(oldest)
+	}
+
+	return q.left(q.queue.pop())
+}
+
+// popIf pops a job that has waited for maxAge first if it matches, so that matching jobs don't starve it.
+// Otherwise, the wrapped queue decides, so that the other jobs are not held back by it.
+func (q *agingQueue) popIf(match func(*job) bool) *job {
+	if oldest := q.aged(); oldest != nil && match(oldest) {
+		return q.take(oldest)
+	}
+
+	return q.left(q.queue.popIf(match))
+}
+
+func (q *agingQueue) oldest() *job {
+	for len(q.order.jobs) > 0 {
+		j := q.order.jobs[0]
+		if _, ok := q.queued[j]; ok {
+			return j
+		}
+
+		heap.Pop(&q.order)
+	}
+
+	return nil
+}
+
+func (q *agingQueue) removeIf(drop func(*job) bool) []*job {
+	removed := q.queue.removeIf(drop)
+	for _, j := range removed {
+		q.left(j)
+	}
+
+	return removed
+}
+
+// aged returns the oldest job if it has waited for maxAge, nil otherwise.
+func (q *agingQueue) aged() *job {
+	oldest := q.oldest()
+	if oldest == nil || q.clock.Now().Sub(oldest.submittedAt) < q.maxAge {
+		return nil
+	}
+
+	return oldest
+}
+
+// take removes j from the wrapped queue and returns it.
+func (q *agingQueue) take(j *job) *job {
+	q.queue.removeIf(func(o *job) bool { return o == j })
+
+	return q.left(j)
+}
+
+// left accounts for j leaving the queue, if not nil, and returns it. Once most of the index is made of jobs
+// that left, e.g. while an old job is held back, it is rebuilt so that it doesn't grow without bounds.
+func (q *agingQueue) left(j *job) *job {
+	if j == nil {
+		return nil
+	}
+
+	delete(q.queued, j)
+
+	if n := len(q.order.jobs); n > 64 && n > 2*len(q.queued) {
+		kept := q.order.jobs[:0]
+		for _, o := range q.order.jobs {
+			if _, ok := q.queued[o]; ok {
+				kept = append(kept, o)
+			}
+		}
+
+		for i := len(kept); i < n; i++ {
+			q.order.jobs[i] = nil
+		}
+
+		q.order.jobs = kept
+		heap.Init(&q.order)
+	}
+
+	return j
+}
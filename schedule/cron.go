@@ -0,0 +1,145 @@
+package schedule
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronSpec is a parsed cron expression, each field is a bit set of the allowed values.
+type cronSpec struct {
+	minute, hour, dom, month, dow uint64
+
+	anyDom, anyDow bool // day fields starting with "*", e.g. "*/2", see matchDay.
+}
+
+// bounds of a cron field.
+type cronField struct {
+	name     string
+	min, max int
+}
+
+var cronFields = [...]cronField{
+	{"minute", 0, 59},
+	{"hour", 0, 23},
+	{"day of month", 1, 31},
+	{"month", 1, 12},
+	{"day of week", 0, 6},
+}
+
+// searchLimit bounds the search of the next activation, for expressions like "0 0 30 2 *" that never match.
+const searchLimit = 5 * 366 * 24 * time.Hour
+
+// Cron parses a standard cron expression with five fields: minute, hour, day of month, month and
+// day of week (0 is Sunday). Fields accept "*", values, ranges "a-b", lists "a,b" and steps "*/n" or "a-b/n".
+// As in cron, when both day fields are restricted a day matching either of them is a match. A day field
+// starting with "*", like "*/2", is not considered restricted.
+// Activations are computed in the location of the time passed to Next.
+func Cron(expr string) (Spec, error) {
+	parts := strings.Fields(expr)
+	if len(parts) != len(cronFields) {
+		return nil, fmt.Errorf("schedule.Cron(): %w: %q should have %d fields", ErrInvalidCron, expr, len(cronFields))
+	}
+
+	var (
+		s    cronSpec
+		sets = [...]*uint64{&s.minute, &s.hour, &s.dom, &s.month, &s.dow}
+	)
+
+	for i, part := range parts {
+		set, err := parseCronField(part, cronFields[i])
+		if err != nil {
+			return nil, fmt.Errorf("schedule.Cron(): %w: %v", ErrInvalidCron, err)
+		}
+
+		*sets[i] = set
+	}
+
+	// as in Vixie cron, a day field starting with "*" doesn't restrict the days, even with a step.
+	s.anyDom, s.anyDow = strings.HasPrefix(parts[2], "*"), strings.HasPrefix(parts[4], "*")
+
+	return &s, nil
+}
+
+func parseCronField(field string, f cronField) (uint64, error) {
+	var set uint64
+	for _, item := range strings.Split(field, ",") {
+		rng, step := item, 1
+		if i := strings.IndexByte(item, '/'); i >= 0 {
+			n, err := strconv.Atoi(item[i+1:])
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("%s: invalid step in %q", f.name, item)
+			}
+
+			rng, step = item[:i], n
+		}
+
+		lo, hi := f.min, f.max
+		if rng != "*" {
+			var err error
+			if lo, hi, err = parseCronRange(rng); err != nil || lo < f.min || hi > f.max || lo > hi {
+				return 0, fmt.Errorf("%s: invalid range %q, values should be within %d-%d", f.name, item, f.min, f.max)
+			}
+		}
+
+		for v := lo; v <= hi; v += step {
+			set |= 1 << uint(v)
+		}
+	}
+
+	return set, nil
+}
+
+func parseCronRange(rng string) (lo, hi int, err error) {
+	from, to, isRange := strings.Cut(rng, "-")
+	if lo, err = strconv.Atoi(from); err != nil {
+		return 0, 0, err
+	}
+
+	if !isRange {
+		return lo, lo, nil
+	}
+
+	hi, err = strconv.Atoi(to)
+
+	return lo, hi, err
+}
+
+// Next returns the first minute after t matching the expression, zero if there is none in the next years.
+func (s *cronSpec) Next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.Add(searchLimit)
+
+	for t.Before(limit) {
+		switch {
+		case !has(s.month, int(t.Month())):
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+		case !s.matchDay(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+		case !has(s.hour, t.Hour()):
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+		case !has(s.minute, t.Minute()):
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+
+	return time.Time{}
+}
+
+// matchDay applies the cron rule for days: if one of the day fields starts with "*", both have to match,
+// otherwise matching either field is enough.
+func (s *cronSpec) matchDay(t time.Time) bool {
+	dom, dow := has(s.dom, t.Day()), has(s.dow, int(t.Weekday()))
+	if s.anyDom || s.anyDow {
+		return dom && dow
+	}
+
+	return dom || dow
+}
+
+func has(set uint64, v int) bool {
+	return set&(1<<uint(v)) != 0
+}
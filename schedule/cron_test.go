@@ -0,0 +1,47 @@
+package schedule
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCron(t *testing.T) {
+	base := time.Date(2021, time.September, 5, 10, 30, 15, 0, time.UTC) // a Sunday.
+
+	tests := []struct {
+		name string
+		expr string
+		want time.Time
+	}{
+		{name: "every minute", expr: "* * * * *", want: time.Date(2021, 9, 5, 10, 31, 0, 0, time.UTC)},
+		{name: "step", expr: "*/20 * * * *", want: time.Date(2021, 9, 5, 10, 40, 0, 0, time.UTC)},
+		{name: "next hour", expr: "15 * * * *", want: time.Date(2021, 9, 5, 11, 15, 0, 0, time.UTC)},
+		{name: "list and range", expr: "0 8-9,12 * * *", want: time.Date(2021, 9, 5, 12, 0, 0, 0, time.UTC)},
+		{name: "day of week", expr: "0 0 * * 3", want: time.Date(2021, 9, 8, 0, 0, 0, 0, time.UTC)},
+		{name: "day of month or week", expr: "0 0 1 * 3", want: time.Date(2021, 9, 8, 0, 0, 0, 0, time.UTC)},
+		{name: "day of month step and week", expr: "0 0 */2 * 1", want: time.Date(2021, 9, 13, 0, 0, 0, 0, time.UTC)},
+		{name: "next year", expr: "0 0 1 1 *", want: time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "never", expr: "0 0 30 2 *", want: time.Time{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec, err := Cron(tt.expr)
+			if err != nil {
+				t.Fatalf("Cron() error = %v", err)
+			}
+
+			if got := spec.Next(base); !got.Equal(tt.want) {
+				t.Errorf("Spec.Next() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCron_invalid(t *testing.T) {
+	for _, expr := range []string{"", "* * * *", "60 * * * *", "* * 0 * *", "*/0 * * * *", "5-1 * * * *", "a * * * *"} {
+		if _, err := Cron(expr); !errors.Is(err, ErrInvalidCron) {
+			t.Errorf("Cron(%q) error = %v, want %v", expr, err, ErrInvalidCron)
+		}
+	}
+}
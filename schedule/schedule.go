@@ -0,0 +1,256 @@
+// Package schedule runs tasks periodically, on intervals or cron expressions, using a gowp pool for execution.
+//
+// Example:
+//	wp, _ := gowp.New(10, gowp.WithNumWorkers(2))
+//	s := schedule.New(wp)
+//
+//	spec, _ := schedule.Cron("*/5 * * * *")
+//	_, _ = s.Add(spec, cleanup, schedule.Skip)
+//	_, _ = s.Add(schedule.Every(time.Minute), heartbeat, schedule.Queue)
+//
+//	// on shutdown
+//	s.Stop()
+//	_ = wp.Wait()
+package schedule // import "github.com/akshaybharambe14/gowp/schedule"
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/akshaybharambe14/gowp"
+)
+
+// errors reported by the package.
+const (
+	ErrInvalidCron     = gowp.Error("invalid cron expression")
+	ErrInvalidInterval = gowp.Error("interval should be greater than zero")
+	ErrStopped         = gowp.Error("scheduler is stopped")
+)
+
+// Overlap decides what happens when a run is due while the previous one hasn't finished.
+const (
+	// Skip drops the run.
+	Skip Overlap = iota
+	// Queue runs it once the previous one has finished. At most one run is kept waiting.
+	Queue
+	// Replace cancels the previous run if it hasn't started yet, and submits the new one.
+	// A running task can't be interrupted, so both may run at the same time.
+	Replace
+)
+
+type (
+	// Spec decides when a task runs.
+	Spec interface {
+		// Next returns the first activation after t, zero if there is none.
+		Next(t time.Time) time.Time
+	}
+
+	// Overlap is a policy for runs that are due while the previous one hasn't finished.
+	Overlap int
+
+	// Option configures a Scheduler.
+	Option func(s *Scheduler)
+
+	// Scheduler submits tasks to a pool as per their Spec.
+	//
+	// Zero value is not usable. Use New() to create a Scheduler.
+	Scheduler struct {
+		pool    *gowp.Pool
+		onError func(error)
+
+		mu      sync.Mutex
+		entries map[*Entry]struct{} // guarded by mu.
+		stopped bool                // guarded by mu.
+		wg      sync.WaitGroup
+	}
+
+	// Entry is a task registered with a Scheduler.
+	Entry struct {
+		s       *Scheduler
+		spec    Spec
+		task    gowp.Task
+		overlap Overlap
+		quit    chan struct{} // closed by Remove.
+		once    sync.Once
+
+		mu      sync.Mutex
+		active  *gowp.Future // the last submitted run, nil once it has finished. Guarded by mu.
+		pending bool         // a run is waiting for the active one, see Queue. Guarded by mu.
+	}
+
+	every time.Duration
+)
+
+// New creates a Scheduler that submits tasks to p. The pool should stay open until Stop is called.
+// Activations are timed by the clock of the pool, see gowp.WithClock.
+func New(p *gowp.Pool, opts ...Option) *Scheduler {
+	s := &Scheduler{pool: p, entries: make(map[*Entry]struct{})}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// WithErrorHandler returns an Option that sets the handler of submission errors, e.g. when the pool is full.
+// Without a handler, such errors are dropped and the run is skipped.
+func WithErrorHandler(fn func(error)) Option {
+	return func(s *Scheduler) {
+		s.onError = fn
+	}
+}
+
+// Every returns a Spec that activates every d, starting d from now. d should be greater than zero.
+func Every(d time.Duration) Spec {
+	return every(d)
+}
+
+func (e every) Next(t time.Time) time.Time {
+	return t.Add(time.Duration(e))
+}
+
+// Add registers t to be run as per spec. Runs that are due while the previous one hasn't finished
+// are handled as per overlap.
+func (s *Scheduler) Add(spec Spec, t gowp.Task, overlap Overlap) (*Entry, error) {
+	if t == nil {
+		return nil, fmt.Errorf("schedule.Scheduler.Add(): %w", gowp.ErrNilTask)
+	}
+
+	if e, ok := spec.(every); ok && e <= 0 {
+		return nil, fmt.Errorf("schedule.Scheduler.Add(): %w", ErrInvalidInterval)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.stopped {
+		return nil, fmt.Errorf("schedule.Scheduler.Add(): %w", ErrStopped)
+	}
+
+	e := &Entry{s: s, spec: spec, task: t, overlap: overlap, quit: make(chan struct{})}
+	s.entries[e] = struct{}{}
+
+	s.wg.Add(1)
+	go e.loop()
+
+	return e, nil
+}
+
+// Stop removes all the entries and waits for their timers to stop. Runs that were submitted already
+// are left to the pool, Wait on the pool to wait for them. Stop is idempotent.
+func (s *Scheduler) Stop() {
+	s.mu.Lock()
+	s.stopped = true
+	entries := s.entries
+	s.entries = make(map[*Entry]struct{})
+	s.mu.Unlock()
+
+	for e := range entries {
+		e.stop()
+	}
+
+	s.wg.Wait()
+}
+
+// Remove stops scheduling the entry. A run that was submitted already is left to the pool.
+func (e *Entry) Remove() {
+	e.s.mu.Lock()
+	delete(e.s.entries, e)
+	e.s.mu.Unlock()
+
+	e.stop()
+}
+
+func (e *Entry) stop() {
+	e.once.Do(func() { close(e.quit) })
+}
+
+func (e *Entry) loop() {
+	defer e.s.wg.Done()
+
+	clock := e.s.pool.Clock()
+	due := make(chan struct{}, 1)
+
+	for {
+		now := clock.Now()
+		next := e.spec.Next(now)
+		if next.IsZero() {
+			return // no more activations.
+		}
+
+		t := clock.AfterFunc(next.Sub(now), func() { due <- struct{}{} })
+		select {
+		case <-e.quit:
+			t.Stop()
+			return
+		case <-due:
+			e.tick()
+		}
+	}
+}
+
+// tick handles an activation as per the overlap policy.
+func (e *Entry) tick() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.active != nil {
+		switch e.overlap {
+		case Skip:
+			return
+		case Queue:
+			e.pending = true
+			return
+		case Replace:
+			e.active.Cancel()
+		}
+	}
+
+	e.submit()
+}
+
+// submit submits a run. e.mu must be held.
+func (e *Entry) submit() {
+	f, err := e.s.pool.SubmitFuture(e.task)
+	if err != nil {
+		if e.s.onError != nil {
+			e.s.onError(err)
+		}
+
+		return
+	}
+
+	select {
+	case <-f.Done():
+		return // executed already, by an inline pool.
+	default:
+	}
+
+	e.active = f
+	go e.watch(f)
+}
+
+// watch clears the active run once it has finished and submits the pending one, if any.
+func (e *Entry) watch(f *gowp.Future) {
+	<-f.Done()
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.active != f {
+		return // replaced.
+	}
+
+	e.active = nil
+
+	if e.pending {
+		e.pending = false
+
+		select {
+		case <-e.quit:
+		default:
+			e.submit()
+		}
+	}
+}
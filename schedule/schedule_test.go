@@ -0,0 +1,91 @@
+package schedule
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/akshaybharambe14/gowp"
+)
+
+func TestScheduler(t *testing.T) {
+	const interval = 2 * time.Millisecond
+
+	tests := []struct {
+		name    string
+		overlap Overlap
+		check   func(runs int32) bool
+	}{
+		// the task takes several intervals, so most activations find it running.
+		{name: "skip", overlap: Skip, check: func(runs int32) bool { return runs >= 1 && runs <= 4 }},
+		{name: "queue", overlap: Queue, check: func(runs int32) bool { return runs >= 1 && runs <= 4 }},
+		{name: "replace", overlap: Replace, check: func(runs int32) bool { return runs >= 1 }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wp, _ := gowp.New(100, gowp.WithNumWorkers(4))
+			s := New(wp)
+
+			var runs, concurrent, peak int32
+			_, err := s.Add(Every(interval), func() error {
+				n := atomic.AddInt32(&concurrent, 1)
+				defer atomic.AddInt32(&concurrent, -1)
+
+				for {
+					old := atomic.LoadInt32(&peak)
+					if n <= old || atomic.CompareAndSwapInt32(&peak, old, n) {
+						break
+					}
+				}
+
+				atomic.AddInt32(&runs, 1)
+				time.Sleep(5 * interval)
+
+				return nil
+			}, tt.overlap)
+			if err != nil {
+				t.Fatalf("Scheduler.Add() error = %v", err)
+			}
+
+			time.Sleep(15 * interval)
+			s.Stop()
+
+			if err := wp.Wait(); err != nil {
+				t.Fatalf("Pool.Wait() error = %v", err)
+			}
+
+			if !tt.check(atomic.LoadInt32(&runs)) {
+				t.Errorf("runs = %d, unexpected for %s", runs, tt.name)
+			}
+
+			if tt.overlap != Replace && peak > 1 {
+				t.Errorf("peak concurrency = %d, runs should not overlap with %s", peak, tt.name)
+			}
+		})
+	}
+}
+
+func TestScheduler_Add(t *testing.T) {
+	wp, _ := gowp.New(1)
+	s := New(wp)
+
+	if _, err := s.Add(Every(0), func() error { return nil }, Skip); !errors.Is(err, ErrInvalidInterval) {
+		t.Errorf("Scheduler.Add() error = %v, want %v", err, ErrInvalidInterval)
+	}
+
+	if _, err := s.Add(Every(time.Second), nil, Skip); !errors.Is(err, gowp.ErrNilTask) {
+		t.Errorf("Scheduler.Add() error = %v, want %v", err, gowp.ErrNilTask)
+	}
+
+	e, _ := s.Add(Every(time.Hour), func() error { return nil }, Skip)
+	e.Remove()
+	e.Remove()
+
+	s.Stop()
+	if _, err := s.Add(Every(time.Second), func() error { return nil }, Skip); !errors.Is(err, ErrStopped) {
+		t.Errorf("Scheduler.Add() after Stop error = %v, want %v", err, ErrStopped)
+	}
+
+	_ = wp.Wait()
+}
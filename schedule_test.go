@@ -0,0 +1,216 @@
+package gowp
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDurationQueue(t *testing.T) {
+	durations := []time.Duration{3, 0, 5, 3, 1}
+
+	tests := []struct {
+		name         string
+		longestFirst bool
+		want         []uint64 // ids in pop order.
+	}{
+		{name: "longest first", longestFirst: true, want: []uint64{3, 1, 4, 5, 2}},
+		{name: "shortest first", longestFirst: false, want: []uint64{2, 5, 1, 4, 3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := newDurationQueue(tt.longestFirst)
+			for i, d := range durations {
+				_ = q.push(&job{id: uint64(i + 1), duration: d})
+			}
+
+			if got := q.oldest(); got == nil || got.id != 1 {
+				t.Errorf("durationQueue.oldest() = %v, want id 1", got)
+			}
+
+			var got []uint64
+			for j := q.pop(); j != nil; j = q.pop() {
+				got = append(got, j.id)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("durationQueue.pop() order = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDurationQueue_removeIf(t *testing.T) {
+	q := newDurationQueue(true)
+	for i := 1; i <= 6; i++ {
+		_ = q.push(&job{id: uint64(i), duration: time.Duration(i)})
+	}
+
+	removed := q.removeIf(func(j *job) bool { return j.id%2 == 0 })
+	if len(removed) != 3 || q.len() != 3 {
+		t.Fatalf("durationQueue.removeIf() removed %d, left %d, want 3 and 3", len(removed), q.len())
+	}
+
+	for _, want := range []uint64{5, 3, 1} {
+		if got := q.pop(); got.id != want {
+			t.Errorf("durationQueue.pop() = %d, want %d", got.id, want)
+		}
+	}
+}
+
+func TestLIFO(t *testing.T) {
+	q := &lifo{}
+	for i := 1; i <= 5; i++ {
+		_ = q.push(&job{id: uint64(i)})
+	}
+
+	if got := q.oldest(); got == nil || got.id != 1 {
+		t.Errorf("lifo.oldest() = %v, want id 1", got)
+	}
+
+	removed := q.removeIf(func(j *job) bool { return j.id == 2 })
+	if len(removed) != 1 || q.len() != 4 {
+		t.Fatalf("lifo.removeIf() removed %d, left %d, want 1 and 4", len(removed), q.len())
+	}
+
+	var got []uint64
+	for j := q.pop(); j != nil; j = q.pop() {
+		got = append(got, j.id)
+	}
+
+	if want := []uint64{5, 4, 3, 1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("lifo.pop() order = %v, want %v", got, want)
+	}
+}
+
+func TestAgingQueue(t *testing.T) {
+	c := &testClock{now: time.Now()}
+	q := newAgingQueue(newDurationQueue(false), time.Minute, c)
+
+	push := func(id uint64, d time.Duration) {
+		_ = q.push(&job{id: id, duration: d, submittedAt: c.Now()})
+	}
+
+	push(1, 10) // long, starved while shorter tasks keep coming.
+	push(2, 2)
+	c.advance(30 * time.Second)
+	push(3, 1)
+
+	var got []uint64
+	got = append(got, q.pop().id) // nothing is old enough yet.
+
+	c.advance(30 * time.Second)
+	push(4, 1)
+	for j := q.pop(); j != nil; j = q.pop() {
+		got = append(got, j.id)
+	}
+
+	if want := []uint64{3, 1, 2, 4}; !reflect.DeepEqual(got, want) {
+		t.Errorf("agingQueue.pop() order = %v, want %v", got, want)
+	}
+}
+
+func TestAgingQueue_popIf(t *testing.T) {
+	c := &testClock{now: time.Now()}
+	q := newAgingQueue(newDurationQueue(false), time.Minute, c)
+
+	_ = q.push(&job{id: 1, duration: 4, class: "db", submittedAt: c.Now()})
+	for id := uint64(2); id <= 4; id++ {
+		_ = q.push(&job{id: id, duration: time.Duration(5 - id), submittedAt: c.Now()})
+	}
+	c.advance(time.Minute)
+
+	// the aged job doesn't match, e.g. its class is at its limit: the others are not held back by it.
+	other := func(j *job) bool { return j.class != "db" }
+	if j := q.popIf(other); j == nil || j.id != 4 {
+		t.Fatalf("agingQueue.popIf() = %v, want job 4", j)
+	}
+
+	if j := q.oldest(); j == nil || j.id != 1 {
+		t.Errorf("agingQueue.oldest() = %v, want job 1", j)
+	}
+
+	if j := q.popIf(func(*job) bool { return true }); j == nil || j.id != 1 {
+		t.Errorf("agingQueue.popIf() = %v, want the aged job 1", j)
+	}
+
+	q.removeIf(func(j *job) bool { return j.id == 2 })
+
+	if j := q.oldest(); j == nil || j.id != 3 {
+		t.Errorf("agingQueue.oldest() = %v, want job 3", j)
+	}
+}
+
+func TestWithAging(t *testing.T) {
+	if _, err := New(1, WithAging(-time.Second)); !errors.Is(err, ErrInvalidAging) {
+		t.Errorf("New() error = %v, want %v", err, ErrInvalidAging)
+	}
+
+	tests := []struct {
+		name   string
+		policy SchedulingPolicy
+		want   bool
+	}{
+		{name: "fifo", policy: FIFO, want: false},
+		{name: "shortest first", policy: ShortestFirst, want: true},
+		{name: "lifo", policy: LIFO, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := newConfig(1, []Option{WithSchedulingPolicy(tt.policy), WithAging(time.Minute)})
+			if err != nil {
+				t.Fatalf("newConfig() error = %v", err)
+			}
+
+			if _, got := cfg.newQueue().(*agingQueue); got != tt.want {
+				t.Errorf("newQueue() ages = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithSchedulingPolicy(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy SchedulingPolicy
+		want   []time.Duration
+	}{
+		{name: "fifo", policy: FIFO, want: []time.Duration{2, 5, 1}},
+		{name: "longest first", policy: LongestFirst, want: []time.Duration{5, 2, 1}},
+		{name: "shortest first", policy: ShortestFirst, want: []time.Duration{1, 2, 5}},
+		{name: "lifo", policy: LIFO, want: []time.Duration{1, 5, 2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wp := testPool(context.Background(), 1, 4, false, WithSchedulingPolicy(tt.policy))
+
+			// hold the only worker, so that the tasks are queued before any of them is picked.
+			started, release := make(chan struct{}), make(chan struct{})
+			_ = wp.Submit(func() error { close(started); <-release; return nil })
+			<-started
+
+			var got []time.Duration
+			for _, d := range []time.Duration{2, 5, 1} {
+				d := d
+				_, _ = wp.SubmitFuture(func() error { got = append(got, d); return nil }, TaskDuration(d))
+			}
+
+			close(release)
+
+			if err := wp.Wait(); err != nil {
+				t.Fatalf("Pool.Wait() error = %v", err)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("execution order = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := New(1, WithSchedulingPolicy(SchedulingPolicy(42))); err == nil {
+		t.Error("New() with unknown policy succeeded, want ErrInvalidPolicy")
+	}
+}
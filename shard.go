@@ -0,0 +1,170 @@
+package gowp
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+type (
+	// shards buffer the jobs submitted to the pool before they are queued, see WithShards.
+	shards struct {
+		next     uint64 // round-robin counter of submissions. Kept first for 64-bit alignment, manipulated by sync/atomic.
+		sleepers int32  // workers about to wait for a job. Manipulated by sync/atomic.
+		turn     int    // shard to move jobs from first. Guarded by the mutex of the pool.
+		size     int    // jobs a shard buffers.
+		list     []shard
+	}
+
+	shard struct {
+		mu     sync.Mutex
+		jobs   []*job // guarded by mu.
+		closed bool   // set once the intake of the pool is closed. Guarded by mu.
+	}
+)
+
+// WithShards returns an Option that spreads submissions over n intake shards, each with its own lock, so that
+// many goroutines submitting at once don't contend on the lock of the pool. Submit appends the task to a shard,
+// round-robin, and takes the lock of the pool only to wake up an idle worker. Workers move tasks from the shards
+// to the queue in batches, as there is room.
+//
+// Each shard buffers up to numTasks/n tasks on top of the queue, Submit fails with ErrNoBuffer once they are all full.
+// The order of submission is kept within a shard only, scheduling policies and orderings apply once tasks are queued.
+// Delayed tasks and blocking submissions bypass the shards.
+//
+// n less than two disables sharding, a negative n results in ErrInvalidShards on Pool initialization.
+// It has no effect along with WithInlineExecution, WithWeightedRandomDispatch and WithTenantQuotas,
+// whose queues may refuse tasks.
+func WithShards(n int) Option {
+	return func(o *config) {
+		o.shards = n
+	}
+}
+
+func newShards(n, numTasks int) *shards {
+	size := numTasks / n
+	if size < 1 {
+		size = 1
+	}
+
+	return &shards{size: size, list: make([]shard, n)}
+}
+
+// shard buffers j in the next shard with room.
+func (p *Pool) shard(j *job) error {
+	s := p.shards
+	start := atomic.AddUint64(&s.next, 1)
+
+	for i := 0; i < len(s.list); i++ {
+		sh := &s.list[(start+uint64(i))%uint64(len(s.list))]
+
+		sh.mu.Lock()
+		if sh.closed {
+			sh.mu.Unlock()
+			return ErrInvalidSend
+		}
+
+		if len(sh.jobs) == s.size {
+			sh.mu.Unlock()
+			continue
+		}
+
+		sh.jobs = append(sh.jobs, j)
+		sh.mu.Unlock()
+
+		atomic.AddInt64(&p.counts.submitted, 1)
+
+		// a worker that counted itself as a sleeper either sees j or is woken up.
+		if atomic.LoadInt32(&s.sleepers) > 0 {
+			p.mu.Lock()
+			p.ready.Signal()
+			p.mu.Unlock()
+		}
+
+		return nil
+	}
+
+	return ErrNoBuffer
+}
+
+// transfer moves the jobs of the shards to the queue, as far as there is room. It returns the number of jobs moved.
+// p.mu must be held.
+func (p *Pool) transfer() int {
+	s := p.shards
+	moved := 0
+
+	for i := range s.list {
+		sh := &s.list[(s.turn+i)%len(s.list)]
+
+		sh.mu.Lock()
+		n := len(sh.jobs)
+		if room := p.size - p.queue.len(); n > room {
+			n = room
+		}
+
+		for _, j := range sh.jobs[:n] {
+			_ = p.queue.push(j) // can't fail, see WithShards.
+		}
+
+		left := copy(sh.jobs, sh.jobs[n:])
+		for k := left; k < len(sh.jobs); k++ {
+			sh.jobs[k] = nil
+		}
+		sh.jobs = sh.jobs[:left]
+		sh.mu.Unlock()
+
+		moved += n
+	}
+
+	s.turn = (s.turn + 1) % len(s.list)
+
+	if moved > 1 {
+		p.ready.Broadcast()
+	}
+
+	if moved > 0 && p.burst != nil {
+		p.checkBurst()
+	}
+
+	if moved > 0 && p.saturation != nil {
+		p.noteLoad()
+	}
+
+	return moved
+}
+
+// len returns the number of jobs buffered by the shards.
+func (s *shards) len() int {
+	n := 0
+	for i := range s.list {
+		sh := &s.list[i]
+		sh.mu.Lock()
+		n += len(sh.jobs)
+		sh.mu.Unlock()
+	}
+
+	return n
+}
+
+// close refuses further jobs, so that the shards only drain from then on.
+func (s *shards) close() {
+	for i := range s.list {
+		sh := &s.list[i]
+		sh.mu.Lock()
+		sh.closed = true
+		sh.mu.Unlock()
+	}
+}
+
+// drain empties the shards and returns the jobs they buffered.
+func (s *shards) drain() []*job {
+	var jobs []*job
+	for i := range s.list {
+		sh := &s.list[i]
+		sh.mu.Lock()
+		jobs = append(jobs, sh.jobs...)
+		sh.jobs = nil
+		sh.mu.Unlock()
+	}
+
+	return jobs
+}
@@ -0,0 +1,142 @@
+package gowp
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestWithShards(t *testing.T) {
+	noop := func() error { return nil }
+
+	tests := []struct {
+		name    string
+		shards  int
+		wantErr error
+		run     func(t *testing.T, p *Pool)
+	}{
+		{name: "invalid", shards: -1, wantErr: ErrInvalidShards},
+		{name: "concurrent submitters", shards: 4, run: func(t *testing.T, p *Pool) {
+			var (
+				ran int64
+				wg  sync.WaitGroup
+			)
+			for g := 0; g < 8; g++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+
+					for i := 0; i < 1000; i++ {
+						for p.Submit(func() error { atomic.AddInt64(&ran, 1); return nil }) != nil {
+							runtime.Gosched() // the shards are full.
+						}
+					}
+				}()
+			}
+			wg.Wait()
+
+			if err := p.Wait(); err != nil {
+				t.Errorf("Pool.Wait() = %v, want nil", err)
+			}
+
+			if ran != 8000 {
+				t.Errorf("%d tasks ran, want 8000", ran)
+			}
+		}},
+		{name: "full shards", shards: 2, run: func(t *testing.T, p *Pool) {
+			p.Pause()
+
+			for i := 0; i < 8; i++ {
+				if err := p.Submit(noop); err != nil {
+					t.Fatalf("Pool.Submit() #%d error = %v", i, err)
+				}
+			}
+
+			if err := p.Submit(noop); !errors.Is(err, ErrNoBuffer) {
+				t.Errorf("Pool.Submit() = %v, want %v", err, ErrNoBuffer)
+			}
+
+			if s := p.Stats(); s.Queued != 8 {
+				t.Errorf("Stats().Queued = %d, want 8", s.Queued)
+			}
+
+			p.Resume()
+			p.Close()
+
+			if err := p.Submit(noop); !errors.Is(err, ErrPoolClosed) {
+				t.Errorf("Pool.Submit() after Close = %v, want %v", err, ErrPoolClosed)
+			}
+
+			if err := p.Wait(); err != nil {
+				t.Errorf("Pool.Wait() = %v, want nil", err)
+			}
+
+			if s := p.Stats(); s.Succeeded != 8 {
+				t.Errorf("Stats().Succeeded = %d, want 8", s.Succeeded)
+			}
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := New(8, WithNumWorkers(2), WithShards(tt.shards))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("New() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if err == nil {
+				tt.run(t, p)
+			}
+		})
+	}
+}
+
+func TestWithShards_stop(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	p, _ := New(4, WithNumWorkers(1), WithShards(2), WithContext(ctx))
+	p.Pause()
+
+	f, err := p.SubmitFuture(func() error { return nil })
+	if err != nil {
+		t.Fatalf("Pool.SubmitFuture() error = %v", err)
+	}
+
+	cancel()
+
+	if err := p.Wait(); !errors.Is(err, context.Canceled) {
+		t.Errorf("Pool.Wait() = %v, want %v", err, context.Canceled)
+	}
+
+	if err := f.Err(); !errors.Is(err, ErrTaskDiscarded) {
+		t.Errorf("Future.Err() = %v, want %v", err, ErrTaskDiscarded)
+	}
+}
+
+func BenchmarkWithShards(b *testing.B) {
+	tests := []struct {
+		name   string
+		shards int
+	}{
+		{name: "unsharded"},
+		{name: "8 shards", shards: 8},
+	}
+
+	for _, tt := range tests {
+		b.Run(tt.name, func(b *testing.B) {
+			b.ReportAllocs()
+
+			p, _ := New(1024, WithNumWorkers(8), WithShards(tt.shards))
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					for p.Submit(func() error { return nil }) != nil {
+						runtime.Gosched() // the queue is full.
+					}
+				}
+			})
+
+			_ = p.Wait()
+		})
+	}
+}
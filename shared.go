@@ -0,0 +1,55 @@
+package gowp
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+// SubmitShared submits t under key, unless a task submitted with the same key is still pending or running.
+// In that case t is dropped and the Future of the in-flight task is returned, so that all the callers
+// share one execution and observe the same error, like golang.org/x/sync/singleflight, but bounded by the pool.
+// Once the task has finished, the next submission with the key executes again.
+//
+// The Future is shared too: cancelling it cancels the task for all the callers.
+// If the submission fails, the callers that joined it in the meantime receive the error through the Future.
+func (p *Pool) SubmitShared(key string, t Task, opts ...TaskOption) (*Future, error) {
+	if t == nil {
+		return nil, fmt.Errorf("gowp.Pool.SubmitShared(): %w", ErrNilTask)
+	}
+
+	p.mu.Lock()
+	if f, ok := p.shared[key]; ok {
+		p.mu.Unlock()
+		return f, nil
+	}
+
+	f := newFuture()
+	f.pool = p // others may cancel the Future before it is submitted.
+	if p.shared == nil {
+		p.shared = make(map[string]*Future)
+	}
+	p.shared[key] = f
+	p.mu.Unlock()
+
+	go p.forget(key, f)
+
+	if err := p.submit(t, f, opts); err != nil {
+		if !f.reject(err) {
+			atomic.AddInt64(&p.counts.tombstones, -1) // cancelled by a caller that joined, it never made it to the queue.
+		}
+		return nil, fmt.Errorf("gowp.Pool.SubmitShared(): %w", err)
+	}
+
+	return f, nil
+}
+
+// forget releases key once its task is done.
+func (p *Pool) forget(key string, f *Future) {
+	<-f.Done()
+
+	p.mu.Lock()
+	if p.shared[key] == f {
+		delete(p.shared, key)
+	}
+	p.mu.Unlock()
+}
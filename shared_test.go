@@ -0,0 +1,77 @@
+package gowp
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"sync/atomic"
+	"testing"
+)
+
+func TestPool_SubmitShared(t *testing.T) {
+	p := testPool(context.Background(), 2, testDefaultNumTasks, false)
+
+	var runs int32
+	release := make(chan struct{})
+	task := func() error {
+		atomic.AddInt32(&runs, 1)
+		<-release
+		return testErr
+	}
+
+	first, err := p.SubmitShared("key", task)
+	if err != nil {
+		t.Fatalf("Pool.SubmitShared() error = %v", err)
+	}
+
+	second, _ := p.SubmitShared("key", task)
+	other, _ := p.SubmitShared("other", testNoOpFunc)
+
+	if first != second {
+		t.Error("Pool.SubmitShared() with an in-flight key returned a new Future")
+	}
+
+	close(release)
+
+	for _, f := range []*Future{first, second} {
+		if err := f.Err(); !errors.Is(err, testErr) {
+			t.Errorf("Future.Err() = %v, want %v", err, testErr)
+		}
+	}
+
+	if err := other.Err(); err != nil {
+		t.Errorf("Future.Err() = %v, want nil", err)
+	}
+
+	// the key is released once the task is done.
+	for {
+		p.mu.Lock()
+		n := len(p.shared)
+		p.mu.Unlock()
+
+		if n == 0 {
+			break
+		}
+
+		runtime.Gosched()
+	}
+
+	third, _ := p.SubmitShared("key", task)
+	if third == first {
+		t.Error("Pool.SubmitShared() after the task finished returned the old Future")
+	}
+
+	p.Wait()
+
+	if n := atomic.LoadInt32(&runs); n != 2 {
+		t.Errorf("task ran %d times, want 2", n)
+	}
+
+	if _, err := p.SubmitShared("key", nil); !errors.Is(err, ErrNilTask) {
+		t.Errorf("Pool.SubmitShared(nil) = %v, want %v", err, ErrNilTask)
+	}
+
+	if _, err := p.SubmitShared("closed", task); !errors.Is(err, ErrPoolClosed) {
+		t.Errorf("Pool.SubmitShared() after Wait = %v, want %v", err, ErrPoolClosed)
+	}
+}
@@ -0,0 +1,97 @@
+package gowp
+
+import (
+	"log"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	slowLogDepth = 16 // frames captured at submission, enough to get past the wrappers of the package.
+
+	pkgPath = "github.com/akshaybharambe14/gowp"
+)
+
+// slowLog logs a sample of the slow tasks, see WithSlowTaskLog.
+type slowLog struct {
+	slow uint64 // slow tasks seen so far, kept first to guarantee 64-bit alignment. Should be manipulated by sync/atomic.
+
+	threshold time.Duration
+	rate      float64
+	logf      func(format string, args ...any)
+}
+
+// WithSlowTaskLog returns an Option that logs the tasks that run for longer than threshold, with the standard
+// logger of the log package. Only a sampleRate share of them is logged, e.g. 0.01 logs one slow task out of
+// a hundred, and 1 all of them, so that outliers show up in production logs without flooding them. The line
+// tells the ID, the name and the duration of the task, and where it was submitted from: the submitting call
+// site is recorded for every task, which costs a stack walk per submission.
+//
+// threshold should be greater than zero and sampleRate within (0, 1], otherwise ErrInvalidSlowTaskLog
+// will be returned on Pool initialization.
+func WithSlowTaskLog(threshold time.Duration, sampleRate float64) Option {
+	return func(o *config) {
+		o.slowLog = &slowLog{threshold: threshold, rate: sampleRate, logf: log.Printf}
+	}
+}
+
+// callers returns the program counters of the goroutine submitting a task.
+func callers() []uintptr {
+	pcs := make([]uintptr, slowLogDepth)
+	return pcs[:runtime.Callers(3, pcs)] // skip runtime.Callers, callers and submitJob.
+}
+
+// observe logs j if it ran for longer than the threshold and is sampled.
+func (s *slowLog) observe(j *job, took time.Duration) {
+	if took <= s.threshold {
+		return
+	}
+
+	// log whenever the sampled share of the slow tasks reaches a new integer.
+	n := atomic.AddUint64(&s.slow, 1)
+	if int64(float64(n)*s.rate) == int64(float64(n-1)*s.rate) {
+		return
+	}
+
+	task := "task " + strconv.FormatUint(j.id, 10)
+	if j.name != "" {
+		task += " " + strconv.Quote(j.name)
+	}
+
+	s.logf("gowp: slow %s took %v, submitted at %s", task, took, callSite(j.callers))
+}
+
+// callSite returns the file and line of the first frame of pcs outside of the package, tests aside.
+func callSite(pcs []uintptr) string {
+	frames := runtime.CallersFrames(pcs)
+	for more := len(pcs) > 0; more; {
+		var f runtime.Frame
+		f, more = frames.Next()
+		if strings.HasPrefix(f.Function, "runtime.") {
+			break // the stack of a goroutine of the pool, e.g. submitting a task once its dependencies succeeded.
+		}
+
+		if !inPackage(f) {
+			return f.File + ":" + strconv.Itoa(f.Line)
+		}
+	}
+
+	return "unknown"
+}
+
+// inPackage reports whether f is a frame of this package or of its v2.
+func inPackage(f runtime.Frame) bool {
+	if strings.HasSuffix(f.File, "_test.go") {
+		return false
+	}
+
+	fn := strings.TrimPrefix(f.Function, pkgPath)
+	if len(fn) == len(f.Function) {
+		return false
+	}
+
+	return strings.HasPrefix(fn, ".") || strings.HasPrefix(fn, "/v2.")
+}
@@ -0,0 +1,80 @@
+package gowp
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWithSlowTaskLog(t *testing.T) {
+	tests := []struct {
+		name     string
+		rate     float64
+		took     time.Duration // time every task runs for, as per the clock of the pool.
+		wantLogs int
+	}{
+		{name: "fast tasks", rate: 1, took: time.Millisecond},
+		{name: "all slow tasks", rate: 1, took: time.Minute, wantLogs: 4},
+		{name: "half of the slow tasks", rate: 0.5, took: time.Minute, wantLogs: 2},
+		{name: "at the threshold", rate: 1, took: time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				mu   sync.Mutex
+				logs []string
+			)
+			capture := func(o *config) {
+				o.slowLog.logf = func(format string, args ...any) {
+					mu.Lock()
+					logs = append(logs, fmt.Sprintf(format, args...))
+					mu.Unlock()
+				}
+			}
+
+			c := &testClock{now: time.Now()}
+			p, _ := New(4, WithNumWorkers(1), WithClock(c), WithSlowTaskLog(time.Second, tt.rate), capture)
+
+			for i := 0; i < 4; i++ {
+				if _, err := p.SubmitFuture(func() error {
+					c.advance(tt.took)
+					return nil
+				}, TaskName("report")); err != nil {
+					t.Fatalf("Pool.SubmitFuture() error = %v", err)
+				}
+			}
+
+			if err := p.Wait(); err != nil {
+				t.Fatalf("Pool.Wait() error = %v", err)
+			}
+
+			if len(logs) != tt.wantLogs {
+				t.Fatalf("logged %d slow tasks, want %d: %q", len(logs), tt.wantLogs, logs)
+			}
+
+			for _, l := range logs {
+				if !strings.Contains(l, `"report" took 1m0s`) || !strings.Contains(l, "slowlog_test.go:") {
+					t.Errorf("logged %q, want the name, the duration and the call site of the task", l)
+				}
+			}
+		})
+	}
+
+	if _, err := New(1, WithSlowTaskLog(time.Second, 0)); !errors.Is(err, ErrInvalidSlowTaskLog) {
+		t.Errorf("New() error = %v, want %v", err, ErrInvalidSlowTaskLog)
+	}
+}
+
+func TestCallSite(t *testing.T) {
+	if got := callSite(nil); got != "unknown" {
+		t.Errorf("callSite(nil) = %q, want %q", got, "unknown")
+	}
+
+	if got := callSite(callers()); !strings.Contains(got, "testing.go:") {
+		t.Errorf("callSite() = %q, want the caller of the test", got)
+	}
+}
@@ -0,0 +1,319 @@
+// Package sqs processes the messages of an Amazon SQS queue with a gowp pool.
+//
+// It is a separate module, so that the core package doesn't depend on the AWS SDK.
+//
+// A Poller long-polls the queue, as long as fewer messages than its limit are in flight, see WithMaxInFlight.
+// Each message is submitted as a task and deleted once its handler returns nil. A message that failed is
+// made visible again right away, so that it is retried, use a redrive policy to set poison messages aside.
+// The visibility timeout of in-flight messages is extended until they are handled, including while they
+// wait in the queue of the pool, so that long tasks are not delivered twice.
+//
+// Example:
+//	cfg, _ := config.LoadDefaultConfig(ctx)
+//	wp, _ := gowp.New(100)
+//
+//	p := sqs.New(awssqs.NewFromConfig(cfg), queueURL, wp, func(msg types.Message) error {
+//		return process(aws.ToString(msg.Body))
+//	})
+//	p.Consume(ctx)
+//
+//	err := p.Wait() // stops polling and waits for the pool.
+package sqs // import "github.com/akshaybharambe14/gowp/sqs"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/akshaybharambe14/gowp"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+)
+
+// maxBatch is the maximum number of messages SQS returns per receive.
+const maxBatch = 10
+
+type (
+	// API is the part of the SQS client a Poller uses, *sqs.Client implements it.
+	API interface {
+		ReceiveMessage(ctx context.Context, in *sqs.ReceiveMessageInput, opts ...func(*sqs.Options)) (*sqs.ReceiveMessageOutput, error)
+		DeleteMessage(ctx context.Context, in *sqs.DeleteMessageInput, opts ...func(*sqs.Options)) (*sqs.DeleteMessageOutput, error)
+		ChangeMessageVisibility(ctx context.Context, in *sqs.ChangeMessageVisibilityInput, opts ...func(*sqs.Options)) (*sqs.ChangeMessageVisibilityOutput, error)
+	}
+
+	// Handler handles a message. The message is deleted from the queue if it returns nil.
+	Handler func(msg types.Message) error
+
+	// Option configures a Poller.
+	Option func(p *Poller)
+
+	// Poller hands the messages of a queue over to a pool.
+	//
+	// Zero value is not usable. Use New() to create a Poller.
+	Poller struct {
+		api API
+		url string
+		p   *gowp.Pool
+		h   Handler
+
+		maxInFlight int           // see WithMaxInFlight.
+		visibility  time.Duration // see WithVisibilityTimeout.
+		heartbeat   time.Duration // how often the visibility of in-flight messages is extended.
+		wait        time.Duration // see WithWaitTime.
+		onError     func(error)   // see WithErrorHandler.
+
+		mu     sync.Mutex
+		cancel context.CancelFunc // stops the poller. nil, if not polling. Guarded by mu.
+		done   chan struct{}      // closed once the poller has returned.
+	}
+)
+
+// WithMaxInFlight returns an Option that bounds the number of messages received and not handled yet.
+// It defaults to the number of workers of the pool.
+func WithMaxInFlight(n int) Option {
+	return func(p *Poller) {
+		p.maxInFlight = n
+	}
+}
+
+// WithVisibilityTimeout returns an Option that sets the visibility timeout of the messages received,
+// it is extended by the same amount while they are in flight. It defaults to 30 seconds.
+func WithVisibilityTimeout(d time.Duration) Option {
+	return func(p *Poller) {
+		if d >= time.Second {
+			p.visibility = d
+			p.heartbeat = d / 2
+		}
+	}
+}
+
+// WithWaitTime returns an Option that sets how long a receive waits for messages, up to 20 seconds.
+// It defaults to 20 seconds.
+func WithWaitTime(d time.Duration) Option {
+	return func(p *Poller) {
+		if d >= 0 && d <= 20*time.Second {
+			p.wait = d
+		}
+	}
+}
+
+// WithErrorHandler returns an Option that sets the function receiving the errors of the poller, e.g. failed
+// receives or deletions. Errors returned by the handler are not included, the pool reports them.
+// Errors are dropped by default.
+func WithErrorHandler(onError func(error)) Option {
+	return func(p *Poller) {
+		p.onError = onError
+	}
+}
+
+// New creates a Poller handling the messages of the queue at url with h on p.
+func New(api API, url string, p *gowp.Pool, h Handler, opts ...Option) *Poller {
+	pl := &Poller{
+		api:        api,
+		url:        url,
+		p:          p,
+		h:          h,
+		visibility: 30 * time.Second,
+		heartbeat:  15 * time.Second,
+		wait:       20 * time.Second,
+		onError:    func(error) {},
+	}
+
+	for _, opt := range opts {
+		opt(pl)
+	}
+
+	if pl.maxInFlight <= 0 {
+		pl.maxInFlight = p.Stats().Workers
+	}
+
+	if pl.maxInFlight <= 0 {
+		pl.maxInFlight = 1 // the workers of the pool have exited already.
+	}
+
+	return pl
+}
+
+// Consume starts handling the messages of the queue with the pool, until ctx is done or Wait is called.
+// Calling Consume while polling has no effect.
+func (pl *Poller) Consume(ctx context.Context) {
+	pl.mu.Lock()
+	defer pl.mu.Unlock()
+
+	if pl.cancel != nil {
+		return
+	}
+
+	ctx, pl.cancel = context.WithCancel(ctx)
+	pl.done = make(chan struct{})
+
+	go pl.poll(ctx)
+}
+
+// Wait stops polling, then waits for the pool, see gowp.Pool.Wait. Messages received are handled.
+func (pl *Poller) Wait() error {
+	pl.mu.Lock()
+	cancel, done := pl.cancel, pl.done
+	pl.mu.Unlock()
+
+	if cancel != nil {
+		cancel()
+		<-done
+	}
+
+	return pl.p.Wait()
+}
+
+func (pl *Poller) poll(ctx context.Context) {
+	defer close(pl.done)
+
+	slots := make(chan struct{}, pl.maxInFlight)
+	for {
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+			return
+		}
+
+		// take as many free slots as a receive can fill.
+		n := 1
+	fill:
+		for n < maxBatch {
+			select {
+			case slots <- struct{}{}:
+				n++
+			default:
+				break fill
+			}
+		}
+
+		out, err := pl.api.ReceiveMessage(ctx, &sqs.ReceiveMessageInput{
+			QueueUrl:            &pl.url,
+			MaxNumberOfMessages: int32(n),
+			VisibilityTimeout:   int32(pl.visibility / time.Second),
+			WaitTimeSeconds:     int32(pl.wait / time.Second),
+		})
+
+		received := 0
+		if err == nil {
+			received = len(out.Messages)
+		}
+
+		for i := received; i < n; i++ {
+			<-slots
+		}
+
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+
+			pl.onError(fmt.Errorf("sqs: receiving messages: %w", err))
+			pl.pause(ctx)
+
+			continue
+		}
+
+		for _, msg := range out.Messages {
+			pl.submit(msg, slots)
+		}
+	}
+}
+
+// submit hands msg over to the pool and extends its visibility until it is handled.
+func (pl *Poller) submit(msg types.Message, slots chan struct{}) {
+	stop := pl.extend(msg)
+
+	f, err := pl.p.SubmitFuture(func() error {
+		err := pl.h(msg)
+		stop()
+		pl.settle(msg, err)
+
+		return err
+	})
+	if err != nil {
+		<-slots
+		stop()
+		pl.onError(fmt.Errorf("sqs: submitting a message: %w", err))
+		pl.settle(msg, err)
+
+		return
+	}
+
+	go func() {
+		<-f.Done()
+		if err := f.Err(); errors.Is(err, gowp.ErrTaskDiscarded) || errors.Is(err, gowp.ErrTaskCanceled) {
+			stop() // not handled, the message is delivered again once its visibility times out.
+		}
+
+		<-slots
+	}()
+}
+
+// extend extends the visibility of msg periodically, until stop is called.
+func (pl *Poller) extend(msg types.Message) (stop func()) {
+	quit := make(chan struct{})
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		t := time.NewTicker(pl.heartbeat)
+		defer t.Stop()
+
+		for {
+			select {
+			case <-t.C:
+				pl.setVisibility(msg, pl.visibility)
+			case <-quit:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		close(quit)
+		<-done
+	}
+}
+
+// settle deletes msg if it was handled, otherwise makes it visible again for it to be retried.
+func (pl *Poller) settle(msg types.Message, err error) {
+	if err != nil {
+		pl.setVisibility(msg, 0)
+		return
+	}
+
+	// the poller may have stopped, the message is handled anyway.
+	_, err = pl.api.DeleteMessage(context.Background(), &sqs.DeleteMessageInput{
+		QueueUrl:      &pl.url,
+		ReceiptHandle: msg.ReceiptHandle,
+	})
+	if err != nil {
+		pl.onError(fmt.Errorf("sqs: deleting a message: %w", err))
+	}
+}
+
+func (pl *Poller) setVisibility(msg types.Message, d time.Duration) {
+	_, err := pl.api.ChangeMessageVisibility(context.Background(), &sqs.ChangeMessageVisibilityInput{
+		QueueUrl:          &pl.url,
+		ReceiptHandle:     msg.ReceiptHandle,
+		VisibilityTimeout: int32(d / time.Second),
+	})
+	if err != nil {
+		pl.onError(fmt.Errorf("sqs: changing the visibility of a message: %w", err))
+	}
+}
+
+// pause backs off after an error, so that an unreachable queue isn't hammered.
+func (pl *Poller) pause(ctx context.Context) {
+	t := time.NewTimer(time.Second)
+	defer t.Stop()
+
+	select {
+	case <-t.C:
+	case <-ctx.Done():
+	}
+}
@@ -0,0 +1,238 @@
+package sqs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/akshaybharambe14/gowp"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+)
+
+// fakeQueue is an in-memory queue implementing API.
+type fakeQueue struct {
+	mu         sync.Mutex
+	visible    []string        // ids of the messages that can be received.
+	inFlight   map[string]bool // ids of the messages received and not deleted.
+	extensions int
+	receives   int
+	failReads  int // number of receives to fail.
+}
+
+func (q *fakeQueue) ReceiveMessage(ctx context.Context, in *sqs.ReceiveMessageInput, _ ...func(*sqs.Options)) (*sqs.ReceiveMessageOutput, error) {
+	q.mu.Lock()
+	q.receives++
+
+	if q.failReads > 0 {
+		q.failReads--
+		q.mu.Unlock()
+		return nil, errors.New("unavailable")
+	}
+
+	var out sqs.ReceiveMessageOutput
+	for len(q.visible) > 0 && len(out.Messages) < int(in.MaxNumberOfMessages) {
+		id := q.visible[0]
+		q.visible = q.visible[1:]
+		q.inFlight[id] = true
+		out.Messages = append(out.Messages, types.Message{MessageId: aws.String(id), ReceiptHandle: aws.String(id), Body: aws.String(id)})
+	}
+	q.mu.Unlock()
+
+	if len(out.Messages) == 0 {
+		select {
+		case <-time.After(time.Millisecond):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	return &out, nil
+}
+
+func (q *fakeQueue) DeleteMessage(_ context.Context, in *sqs.DeleteMessageInput, _ ...func(*sqs.Options)) (*sqs.DeleteMessageOutput, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	delete(q.inFlight, *in.ReceiptHandle)
+
+	return &sqs.DeleteMessageOutput{}, nil
+}
+
+func (q *fakeQueue) ChangeMessageVisibility(_ context.Context, in *sqs.ChangeMessageVisibilityInput, _ ...func(*sqs.Options)) (*sqs.ChangeMessageVisibilityOutput, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	id := *in.ReceiptHandle
+	if in.VisibilityTimeout == 0 {
+		delete(q.inFlight, id)
+		q.visible = append(q.visible, id)
+	} else {
+		q.extensions++
+	}
+
+	return &sqs.ChangeMessageVisibilityOutput{}, nil
+}
+
+func TestPoller(t *testing.T) {
+	q := &fakeQueue{inFlight: make(map[string]bool), failReads: 1}
+	for i := 0; i < 20; i++ {
+		q.visible = append(q.visible, fmt.Sprint("m", i))
+	}
+
+	var (
+		mu       sync.Mutex
+		handled  = make(map[string]int)
+		inFlight int
+		maxSeen  int
+		failed   bool
+	)
+	errFailed := errors.New("failed")
+	h := func(msg types.Message) error {
+		mu.Lock()
+		inFlight++
+		if inFlight > maxSeen {
+			maxSeen = inFlight
+		}
+		fail := !failed && *msg.Body == "m3"
+		failed = failed || fail
+		mu.Unlock()
+
+		if *msg.Body == "m0" {
+			time.Sleep(20 * time.Millisecond) // long enough to be extended.
+		}
+
+		mu.Lock()
+		inFlight--
+		if !fail {
+			handled[*msg.Body]++
+		}
+		mu.Unlock()
+
+		if fail {
+			return errFailed // made visible again.
+		}
+
+		return nil
+	}
+
+	var (
+		errMu sync.Mutex
+		errs  []error
+	)
+	onError := func(err error) {
+		errMu.Lock()
+		errs = append(errs, err)
+		errMu.Unlock()
+	}
+
+	wp, _ := gowp.New(10, gowp.WithNumWorkers(4))
+	pl := New(q, "https://sqs.example.com/tasks", wp, h, WithMaxInFlight(3), WithErrorHandler(onError))
+	pl.heartbeat = 5 * time.Millisecond
+
+	ctx := context.Background()
+	pl.Consume(ctx)
+	pl.Consume(ctx) // no effect.
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		mu.Lock()
+		n := len(handled)
+		mu.Unlock()
+
+		if n == 20 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	if err := pl.Wait(); !errors.Is(err, errFailed) {
+		t.Errorf("Poller.Wait() = %v, want %v", err, errFailed)
+	}
+
+	for id, n := range handled {
+		if n != 1 {
+			t.Errorf("message %s handled %d times, want once", id, n)
+		}
+	}
+
+	if len(handled) != 20 || len(q.inFlight) != 0 || len(q.visible) != 0 {
+		t.Errorf("handled %d messages, %d in flight, %d visible, want 20, 0, 0", len(handled), len(q.inFlight), len(q.visible))
+	}
+
+	if maxSeen > 3 {
+		t.Errorf("%d messages handled concurrently, want at most 3", maxSeen)
+	}
+
+	if q.extensions == 0 {
+		t.Error("the visibility of the long message wasn't extended")
+	}
+
+	if len(errs) != 1 {
+		t.Errorf("errors = %v, want the failed receive", errs)
+	}
+}
+
+func TestPoller_Wait_notConsuming(t *testing.T) {
+	wp, _ := gowp.New(1)
+	pl := New(&fakeQueue{}, "", wp, func(types.Message) error { return nil }, WithVisibilityTimeout(time.Minute), WithWaitTime(time.Second))
+
+	if pl.visibility != time.Minute || pl.heartbeat != 30*time.Second || pl.wait != time.Second {
+		t.Errorf("visibility, heartbeat, wait = %v, %v, %v, want 1m, 30s, 1s", pl.visibility, pl.heartbeat, pl.wait)
+	}
+
+	if err := pl.Wait(); err != nil {
+		t.Errorf("Poller.Wait() = %v, want nil", err)
+	}
+}
+
+func TestPoller_poolStopped(t *testing.T) {
+	q := &fakeQueue{visible: []string{"1", "2"}, inFlight: make(map[string]bool)}
+
+	var (
+		started = make(chan struct{})
+		release = make(chan struct{})
+	)
+	wp, _ := gowp.New(2, gowp.WithNumWorkers(1))
+	pl := New(q, "", wp, func(msg types.Message) error {
+		if *msg.Body == "1" {
+			close(started)
+			<-release
+		}
+
+		return nil
+	}, WithMaxInFlight(2), WithVisibilityTimeout(time.Second))
+	pl.Consume(context.Background())
+
+	<-started
+
+	// stop the pool while the second message is queued.
+	errStop := errors.New("stop")
+	wp.CloseWithError(errStop)
+	close(release)
+
+	if err := pl.Wait(); !errors.Is(err, errStop) {
+		t.Errorf("Poller.Wait() = %v, want %v", err, errStop)
+	}
+
+	q.mu.Lock()
+	extensions, discarded := q.extensions, q.inFlight["2"]
+	q.mu.Unlock()
+
+	if !discarded {
+		t.Errorf("discarded message settled, want it left for redelivery")
+	}
+
+	time.Sleep(2 * time.Second) // the visibility would be extended meanwhile.
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.extensions != extensions {
+		t.Errorf("visibility extended %d times after Poller.Wait(), want 0", q.extensions-extensions)
+	}
+}
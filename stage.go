@@ -0,0 +1,235 @@
+package gowp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"sync"
+)
+
+type (
+	// Stage is a step of a pipeline that transforms values of type I into values of type O with a
+	// bounded number of workers. Stages are chained with Then, the compiler checks that the output
+	// of a stage matches the input of the next one.
+	//
+	// Zero value is not usable. Use NewStage() to create a new Stage.
+	Stage[I, O any] struct {
+		start func(pl *pipeline, in <-chan I) <-chan O
+	}
+
+	// pipeline is the state shared by the stages of a running pipeline.
+	pipeline struct {
+		ctx     context.Context
+		cancel  context.CancelFunc
+		wg      sync.WaitGroup // tracks the workers of all the stages.
+		errs    chan error     // receives the first error, then it is closed once all the workers are done.
+		errOnce sync.Once
+	}
+)
+
+// NewStage creates a Stage that applies fn to its input with the given number of workers.
+// If workers is less than or equal to zero, runtime.NumCPU() workers are used.
+// The output channel of the stage is buffered as per the number of workers.
+func NewStage[I, O any](workers int, fn func(context.Context, I) (O, error)) Stage[I, O] {
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	return Stage[I, O]{
+		start: func(pl *pipeline, in <-chan I) <-chan O {
+			out := make(chan O, workers)
+
+			var wg sync.WaitGroup
+			for i := 0; i < workers; i++ {
+				wg.Add(1)
+				pl.wg.Add(1)
+				go func() {
+					defer pl.wg.Done()
+					defer wg.Done()
+					runStage(pl, in, out, fn)
+				}()
+			}
+
+			go func() {
+				wg.Wait()
+				close(out)
+			}()
+
+			return out
+		},
+	}
+}
+
+// NewPoolStage creates a Stage that applies fn to its input with a pool created with opts, so that the stage
+// gets the options of a pool, e.g. hooks, rate limiting or panic recovery. At most buffer values are in flight
+// at once, queued or being transformed or waiting to be delivered, buffer is the size of the queue of the pool
+// and of the output channel. Once the limit is hit, the stage stops reading its input until a slot frees up,
+// so that a slow consumer slows down the producer. Values are output as they come, not in the order of the input.
+//
+// The pool is bound to the context of the pipeline, any WithContext option is overridden. It exits on the first
+// error by default, which stops the pipeline like for other stages. If WithExitOnError(false) is passed, the
+// stage transforms all its input and the pipeline reports its errors joined once the input is closed.
+// Invalid options are reported here, like by New.
+func NewPoolStage[I, O any](buffer int, fn func(context.Context, I) (O, error), opts ...Option) (Stage[I, O], error) {
+	opts = append([]Option{WithExitOnError(true)}, opts...)
+	if _, err := newConfig(buffer, opts); err != nil {
+		return Stage[I, O]{}, fmt.Errorf("gowp.NewPoolStage(): %w", err)
+	}
+
+	return Stage[I, O]{
+		start: func(pl *pipeline, in <-chan I) <-chan O {
+			cfg, _ := newConfig(buffer, append(opts, WithContext(pl.ctx))) // validated above.
+			p := newPool(cfg, buffer)
+			out := make(chan O, buffer)
+
+			var (
+				mu   sync.Mutex
+				errs []error
+			)
+			transform := func(ctx context.Context, v I) (O, error) {
+				o, err := fn(ctx, v)
+				if err != nil {
+					mu.Lock()
+					errs = append(errs, err)
+					mu.Unlock()
+				}
+
+				return o, err
+			}
+
+			pl.wg.Add(1)
+			go func() {
+				defer pl.wg.Done()
+
+				feed(p, in, buffer, out, transform)
+				p.Close()
+
+				err := p.wait()
+				if !cfg.exitOnErr && len(errs) > 0 {
+					err = errors.Join(errs...) // the pool reports only the first one.
+				}
+				close(out)
+
+				if err != nil {
+					pl.fail(err)
+				}
+			}()
+
+			return out
+		},
+	}, nil
+}
+
+// Then chains next after first, the output of first is the input of next.
+func Then[A, B, C any](first Stage[A, B], next Stage[B, C]) Stage[A, C] {
+	return Stage[A, C]{
+		start: func(pl *pipeline, in <-chan A) <-chan C {
+			return next.start(pl, first.start(pl, in))
+		},
+	}
+}
+
+// Run starts the pipeline, reading its input from in until it is closed. It returns the output channel
+// of the last stage, which is closed when the pipeline is done, and an error channel which receives the
+// first error reported by any stage, or the error of ctx, and is closed once all the workers have returned.
+// The first error cancels the pipeline. The caller should keep receiving from the output channel until it
+// is closed, or cancel ctx.
+func (s Stage[I, O]) Run(ctx context.Context, in <-chan I) (<-chan O, <-chan error) {
+	ctx, cancel := context.WithCancel(ctx)
+	pl := &pipeline{
+		ctx:    ctx,
+		cancel: cancel,
+		errs:   make(chan error, 1),
+	}
+
+	out := s.start(pl, in)
+
+	go func() {
+		pl.wg.Wait()
+		cancel()
+		close(pl.errs)
+	}()
+
+	return out, pl.errs
+}
+
+// fail records the first error and cancels the pipeline.
+func (pl *pipeline) fail(err error) {
+	pl.errOnce.Do(func() {
+		pl.errs <- err
+		pl.cancel()
+	})
+}
+
+func runStage[I, O any](pl *pipeline, in <-chan I, out chan<- O, fn func(context.Context, I) (O, error)) {
+	for {
+		select {
+		case <-pl.ctx.Done():
+			pl.fail(pl.ctx.Err())
+			return
+		case v, ok := <-in:
+			if !ok {
+				return
+			}
+
+			o, err := fn(pl.ctx, v)
+			if err != nil {
+				pl.fail(err)
+				return
+			}
+
+			select {
+			case out <- o:
+			case <-pl.ctx.Done():
+				pl.fail(pl.ctx.Err())
+				return
+			}
+		}
+	}
+}
+
+// feed submits the values of in to p until in is closed or p stops, holding one of the buffer slots for each
+// value until its result is sent to out.
+func feed[I, O any](p *Pool, in <-chan I, buffer int, out chan<- O, fn func(context.Context, I) (O, error)) {
+	ctx := p.Context()
+	slots := make(chan struct{}, buffer)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case slots <- struct{}{}:
+		}
+
+		var v I
+		select {
+		case <-ctx.Done():
+			return
+		case item, ok := <-in:
+			if !ok {
+				return
+			}
+			v = item
+		}
+
+		err := p.Submit(func() error {
+			defer func() { <-slots }()
+
+			o, err := fn(ctx, v)
+			if err != nil {
+				return err
+			}
+
+			select {
+			case out <- o:
+			case <-ctx.Done(): // the pipeline stopped, nobody might be receiving anymore.
+			}
+
+			return nil
+		})
+		if err != nil {
+			return // the pool stopped.
+		}
+	}
+}
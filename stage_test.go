@@ -0,0 +1,120 @@
+package gowp
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sort"
+	"strconv"
+	"testing"
+)
+
+func testStageInput(n int) <-chan int {
+	in := make(chan int)
+	go func() {
+		defer close(in)
+		for i := 0; i < n; i++ {
+			in <- i
+		}
+	}()
+
+	return in
+}
+
+func TestStage_Run(t *testing.T) {
+	double := NewStage(2, func(_ context.Context, i int) (int, error) { return 2 * i, nil })
+	format := NewStage(3, func(_ context.Context, i int) (string, error) { return strconv.Itoa(i), nil })
+
+	out, errs := Then(double, format).Run(context.Background(), testStageInput(5))
+
+	var got []string
+	for s := range out {
+		got = append(got, s)
+	}
+	sort.Strings(got)
+
+	if err := <-errs; err != nil {
+		t.Fatalf("Stage.Run() error = %v", err)
+	}
+
+	if want := []string{"0", "2", "4", "6", "8"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("Stage.Run() = %v, want %v", got, want)
+	}
+}
+
+func TestStage_Run_error(t *testing.T) {
+	fail := NewStage(2, func(_ context.Context, i int) (int, error) {
+		if i == 3 {
+			return 0, testErr
+		}
+		return i, nil
+	})
+
+	out, errs := fail.Run(context.Background(), testStageInput(100))
+	for range out {
+	}
+
+	if err := <-errs; !errors.Is(err, testErr) {
+		t.Errorf("Stage.Run() error = %v, want %v", err, testErr)
+	}
+
+	if _, ok := <-errs; ok {
+		t.Error("error channel is not closed")
+	}
+}
+
+func TestNewPoolStage(t *testing.T) {
+	failures := map[int]error{3: errors.New("3 failed"), 5: errors.New("5 failed"), 7: errors.New("7 failed")}
+	transform := func(_ context.Context, i int) (int, error) {
+		if err := failures[i]; err != nil {
+			return 0, err
+		}
+		return 2 * i, nil
+	}
+
+	tests := []struct {
+		name     string
+		opts     []Option
+		wantErrs int // failures reported by the pipeline.
+		wantOut  int // values output, -1 if the pipeline stops early.
+	}{
+		{name: "exits on error", opts: []Option{WithNumWorkers(2)}, wantErrs: 1, wantOut: -1},
+		{name: "keeps going on error", opts: []Option{WithNumWorkers(2), WithExitOnError(false)}, wantErrs: 3, wantOut: 7},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pooled, err := NewPoolStage(2, transform, tt.opts...)
+			if err != nil {
+				t.Fatalf("NewPoolStage() error = %v", err)
+			}
+			format := NewStage(1, func(_ context.Context, i int) (string, error) { return strconv.Itoa(i), nil })
+
+			out, errs := Then(pooled, format).Run(context.Background(), testStageInput(10))
+
+			n := 0
+			for range out {
+				n++
+			}
+
+			err = <-errs
+			reported := 0
+			for _, ferr := range failures {
+				if errors.Is(err, ferr) {
+					reported++
+				}
+			}
+			if reported != tt.wantErrs {
+				t.Errorf("Stage.Run() error = %v, reports %d of the failures, want %d", err, reported, tt.wantErrs)
+			}
+
+			if tt.wantOut >= 0 && n != tt.wantOut {
+				t.Errorf("Stage.Run() output %d values, want %d", n, tt.wantOut)
+			}
+		})
+	}
+
+	if _, err := NewPoolStage(0, transform); !errors.Is(err, ErrInvalidBuffer) {
+		t.Errorf("NewPoolStage() error = %v, want %v", err, ErrInvalidBuffer)
+	}
+}
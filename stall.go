@@ -0,0 +1,86 @@
+package gowp
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+type (
+	// Stall describes a pool that doesn't make progress, see WithStallDetector.
+	Stall struct {
+		Since   time.Time  // when the pool last made progress, i.e. when a task last finished.
+		Queued  int        // tasks waiting for a worker.
+		Running []TaskInfo // tasks being executed, see Snapshot. They are likely the ones stuck.
+	}
+
+	stallDetector struct {
+		timeout time.Duration
+		report  func(Stall)
+	}
+)
+
+// WithStallDetector returns an Option that watches the pool for a lack of progress: if tasks are queued but
+// none has finished for timeout, e.g. because all the workers are blocked on a lock held by a queued task,
+// report is called with the tasks being executed. It is called once per stall, again only once the pool
+// has made progress and stalls anew. The pool is checked every timeout/2. Paused pools are not watched.
+//
+// report is called from a goroutine of the pool, a panic in report is recovered, see WithHookErrorHandler.
+// timeout should be greater than zero and report should not be nil, otherwise ErrInvalidStall will be
+// returned on Pool initialization.
+func WithStallDetector(timeout time.Duration, report func(Stall)) Option {
+	return func(o *config) {
+		o.stall = &stallDetector{timeout: timeout, report: report}
+	}
+}
+
+// detectStalls checks the pool for progress as per sd, until the pool stops.
+func (p *Pool) detectStalls(sd stallDetector) {
+	interval := sd.timeout / 2
+	if interval <= 0 {
+		interval = sd.timeout
+	}
+
+	t := p.clock.NewTicker(interval)
+	defer t.Stop()
+
+	last, since, reported := p.finished(), p.clock.Now(), false
+
+	for {
+		select {
+		case <-p.quit:
+			return
+		case <-p.exitFromErrG:
+			return
+		case now := <-t.C():
+			n := p.finished()
+			if n != last {
+				last, since, reported = n, now, false
+				continue
+			}
+
+			p.mu.Lock()
+			paused := p.paused || p.throttled
+			p.mu.Unlock()
+
+			queued := p.Pending()
+			if paused || queued == 0 {
+				since, reported = now, false // waiting for tasks isn't stalling.
+				continue
+			}
+
+			if reported || now.Sub(since) < sd.timeout {
+				continue
+			}
+
+			reported = true
+
+			s := Stall{Since: since, Queued: queued, Running: p.Snapshot()}
+			p.guard("StallDetector", TaskInfo{}, func() { sd.report(s) })
+		}
+	}
+}
+
+// finished returns the number of tasks that have finished executing.
+func (p *Pool) finished() int64 {
+	return atomic.LoadInt64(&p.counts.succeeded) + atomic.LoadInt64(&p.counts.failed) + atomic.LoadInt64(&p.counts.ignored)
+}
@@ -0,0 +1,85 @@
+package gowp
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestWithStallDetector(t *testing.T) {
+	const timeout = 20 * time.Millisecond
+
+	stalls := make(chan Stall, 4)
+	p := testPool(context.Background(), 1, testDefaultNumTasks, false,
+		WithStallDetector(timeout, func(s Stall) { stalls <- s }))
+
+	release := make(chan struct{})
+	f, _ := p.SubmitFuture(func() error { <-release; return nil }, TaskName("wedged"))
+	_ = p.Submit(testNoOpFunc)
+
+	select {
+	case s := <-stalls:
+		if s.Queued != 1 || len(s.Running) != 1 || s.Running[0].ID != f.ID() || s.Running[0].Name != "wedged" {
+			t.Errorf("Stall = %+v, want 1 queued and the wedged task running", s)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("stall not reported")
+	}
+
+	// reported once per stall.
+	select {
+	case s := <-stalls:
+		t.Errorf("Stall %+v reported twice", s)
+	case <-time.After(3 * timeout):
+	}
+
+	close(release)
+
+	if err := p.Wait(); err != nil {
+		t.Fatalf("Pool.Wait() error = %v", err)
+	}
+}
+
+func TestWithStallDetector_idle(t *testing.T) {
+	const timeout = 10 * time.Millisecond
+
+	stalls := make(chan Stall, 1)
+	p := testPool(context.Background(), 1, testDefaultNumTasks, false,
+		WithStallDetector(timeout, func(s Stall) { stalls <- s }))
+
+	// an empty pool and a paused one wait for tasks, they don't stall.
+	time.Sleep(3 * timeout)
+	p.Pause()
+	_ = p.Submit(testNoOpFunc)
+	time.Sleep(3 * timeout)
+	p.Resume()
+
+	if err := p.Wait(); err != nil {
+		t.Fatalf("Pool.Wait() error = %v", err)
+	}
+
+	select {
+	case s := <-stalls:
+		t.Errorf("Stall %+v reported for a pool waiting for tasks", s)
+	default:
+	}
+}
+
+func TestWithStallDetector_validation(t *testing.T) {
+	tests := []struct {
+		name    string
+		timeout time.Duration
+		report  func(Stall)
+	}{
+		{name: "zero timeout", report: func(Stall) {}},
+		{name: "nil report", timeout: time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := New(testDefaultNumTasks, WithStallDetector(tt.timeout, tt.report)); !errors.Is(err, ErrInvalidStall) {
+				t.Errorf("New() = %v, want %v", err, ErrInvalidStall)
+			}
+		})
+	}
+}
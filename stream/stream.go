@@ -0,0 +1,82 @@
+// Package stream moves items from a source channel to a sink channel through a gowp pool, whose workers
+// transform them on the way.
+//
+// At most buffer items are in flight at once, queued or being transformed or waiting to be delivered. Once
+// the limit is hit, the stream stops reading from the source until a slot frees up, so a slow consumer of the
+// sink slows down the producer of the source instead of letting items pile up. By default the first error
+// stops the stream: the source is no longer read, the items in flight are dropped, and the sink is closed.
+//
+// A stream is a pipeline of a single gowp.NewPoolStage, see gowp.Stage to chain more steps.
+//
+// Example:
+//	s, _ := stream.Run(ctx, urls, 16, fetch, gowp.WithNumWorkers(4))
+//	for page := range s.Out() {
+//		index(page)
+//	}
+//
+//	if err := s.Wait(); err != nil {
+//		log.Println(err)
+//	}
+package stream // import "github.com/akshaybharambe14/gowp/stream"
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/akshaybharambe14/gowp"
+)
+
+// Stream is a running stream, see Run.
+//
+// Zero value is not usable. Use Run() to start a new Stream.
+type Stream[Out any] struct {
+	out  <-chan Out
+	done chan struct{} // closed once the stream is over, then err is set.
+	err  error
+}
+
+// Run starts a stream that reads items from src until it is closed, transforms them with fn, using a pool
+// created with opts, and sends the results to the sink, see Stream.Out. Results are sent as they come, not
+// in the order of src. buffer bounds the items in flight, it is the size of the queue of the pool and of
+// the sink. The pool exits on the first error by default, pass gowp.WithExitOnError(false) to transform all
+// the items and get the errors joined from Wait instead. The pool is bound to ctx, any WithContext option is
+// overridden. fn receives a context that is cancelled once the stream stops.
+func Run[In, Out any](ctx context.Context, src <-chan In, buffer int, fn func(context.Context, In) (Out, error), opts ...gowp.Option) (*Stream[Out], error) {
+	stage, err := gowp.NewPoolStage(buffer, fn, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("stream.Run(): %w", err)
+	}
+
+	out, errs := stage.Run(ctx, src)
+
+	s := &Stream[Out]{
+		out:  out,
+		done: make(chan struct{}),
+	}
+
+	go func() {
+		s.err = <-errs
+		for range errs { // closed once the pool has completed.
+		}
+		close(s.done)
+	}()
+
+	return s, nil
+}
+
+// Out returns the sink of the stream, which is closed once the stream is over. The caller should keep
+// receiving from it until it is closed, or cancel the context of the stream.
+func (s *Stream[Out]) Out() <-chan Out {
+	return s.out
+}
+
+// Wait waits for the stream to be over and returns its error, if any.
+func (s *Stream[Out]) Wait() error {
+	<-s.done
+
+	if s.err != nil {
+		return fmt.Errorf("stream.Stream.Wait(): %w", s.err)
+	}
+
+	return nil
+}
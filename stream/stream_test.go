@@ -0,0 +1,190 @@
+package stream
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/akshaybharambe14/gowp"
+)
+
+func source(n int) <-chan int {
+	src := make(chan int)
+	go func() {
+		defer close(src)
+		for i := 0; i < n; i++ {
+			src <- i
+		}
+	}()
+
+	return src
+}
+
+func TestRun(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	tests := []struct {
+		name    string
+		items   int
+		fn      func(context.Context, int) (int, error)
+		wantOut []int
+		wantErr error
+	}{
+		{
+			name:    "transforms every item",
+			items:   5,
+			fn:      func(_ context.Context, v int) (int, error) { return v * 2, nil },
+			wantOut: []int{0, 2, 4, 6, 8},
+		},
+		{
+			name:    "empty source",
+			items:   0,
+			fn:      func(_ context.Context, v int) (int, error) { return v, nil },
+			wantOut: []int{},
+		},
+		{
+			name:  "error stops the stream",
+			items: 100,
+			fn: func(_ context.Context, v int) (int, error) {
+				if v == 3 {
+					return 0, errBoom
+				}
+				return v, nil
+			},
+			wantErr: errBoom,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := Run(context.Background(), source(tt.items), 2, tt.fn, gowp.WithNumWorkers(2))
+			if err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+
+			got := []int{}
+			for v := range s.Out() {
+				got = append(got, v)
+			}
+
+			err = s.Wait()
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil && err != nil) {
+				t.Fatalf("Wait() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if tt.wantOut == nil {
+				if len(got) >= tt.items {
+					t.Errorf("Out() got %d items, want the stream to stop early", len(got))
+				}
+				return
+			}
+
+			sort.Ints(got)
+			if len(got) != len(tt.wantOut) {
+				t.Fatalf("Out() = %v, want %v", got, tt.wantOut)
+			}
+			for i := range got {
+				if got[i] != tt.wantOut[i] {
+					t.Fatalf("Out() = %v, want %v", got, tt.wantOut)
+				}
+			}
+		})
+	}
+}
+
+func TestRun_keepGoing(t *testing.T) {
+	failures := map[int]error{3: errors.New("3 failed"), 5: errors.New("5 failed"), 7: errors.New("7 failed")}
+
+	s, err := Run(context.Background(), source(10), 2, func(_ context.Context, v int) (int, error) {
+		if err := failures[v]; err != nil {
+			return 0, err
+		}
+		return v, nil
+	}, gowp.WithNumWorkers(2), gowp.WithExitOnError(false))
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	n := 0
+	for range s.Out() {
+		n++
+	}
+
+	err = s.Wait()
+	for v, ferr := range failures {
+		if !errors.Is(err, ferr) {
+			t.Errorf("Wait() error = %v, want the failure of %d", err, v)
+		}
+	}
+
+	if n != 7 {
+		t.Errorf("Out() got %d items, want 7", n)
+	}
+}
+
+func TestRun_invalid(t *testing.T) {
+	fn := func(_ context.Context, v int) (int, error) { return v, nil }
+	if _, err := Run(context.Background(), source(0), 0, fn); !errors.Is(err, gowp.ErrInvalidBuffer) {
+		t.Errorf("Run() error = %v, want %v", err, gowp.ErrInvalidBuffer)
+	}
+}
+
+func TestRun_backpressure(t *testing.T) {
+	const buffer = 2
+
+	var read int64
+	src := make(chan int)
+	go func() {
+		defer close(src)
+		for i := 0; i < 20; i++ {
+			src <- i
+			atomic.AddInt64(&read, 1)
+		}
+	}()
+
+	s, err := Run(context.Background(), src, buffer, func(_ context.Context, v int) (int, error) { return v, nil })
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	time.Sleep(50 * time.Millisecond) // nobody receives from the sink.
+
+	// buffer slots plus the item the feeder is blocked on, the sink holds buffer more.
+	if got := atomic.LoadInt64(&read); got > 2*buffer+1 {
+		t.Errorf("source read %d items with a stalled sink, want at most %d", got, 2*buffer+1)
+	}
+
+	n := 0
+	for range s.Out() {
+		n++
+	}
+
+	if err := s.Wait(); err != nil {
+		t.Fatalf("Wait() error = %v", err)
+	}
+	if n != 20 {
+		t.Errorf("Out() got %d items, want 20", n)
+	}
+}
+
+func TestRun_cancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	src := make(chan int) // never closed.
+	s, err := Run(ctx, src, 1, func(_ context.Context, v int) (int, error) { return v, nil })
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	cancel()
+
+	for range s.Out() {
+	}
+
+	if err := s.Wait(); !errors.Is(err, context.Canceled) {
+		t.Errorf("Wait() error = %v, want %v", err, context.Canceled)
+	}
+}
@@ -0,0 +1,44 @@
+package gowp
+
+import "strconv"
+
+// TaskError is the error reported by the pool when a task fails, e.g. by Wait. It tells which task failed
+// and when it was queued, started and finished. The error returned by the task is available through errors.Is and
+// errors.As, Futures report it as is.
+//
+//	var te *gowp.TaskError
+//	if errors.As(wp.Wait(), &te) {
+//		log.Printf("%s failed after %v: %v", te.Name, te.Took(), te.Err)
+//	}
+type TaskError struct {
+	ID    uint64 // see TaskInfo.
+	Name  string // see TaskName.
+	Label string // see TaskLabel.
+	Err   error  // error returned by the task.
+
+	Timing // when the task was queued, started and finished, see Timing.Waited and Timing.Took.
+}
+
+func (e *TaskError) Error() string {
+	task := "task " + strconv.FormatUint(e.ID, 10)
+	if e.Name != "" {
+		task += " " + strconv.Quote(e.Name)
+	}
+
+	return task + " failed: " + e.Err.Error()
+}
+
+func (e *TaskError) Unwrap() error {
+	return e.Err
+}
+
+// failed wraps err, returned by j, in a TaskError.
+func (j *job) failed(err error) *TaskError {
+	return &TaskError{
+		ID:     j.id,
+		Name:   j.name,
+		Label:  j.label,
+		Err:    err,
+		Timing: j.timing(),
+	}
+}
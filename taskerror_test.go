@@ -0,0 +1,53 @@
+package gowp
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestTaskError(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     []TaskOption
+		wantName string
+		wantMsg  string
+	}{
+		{name: "unnamed", wantMsg: "task 1 failed: test error"},
+		{name: "named", opts: []TaskOption{TaskName("item-7")}, wantName: "item-7", wantMsg: `task 1 "item-7" failed: test error`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := testPool(context.Background(), 1, testDefaultNumTasks, true)
+
+			f, _ := p.SubmitFuture(func() error {
+				time.Sleep(time.Millisecond)
+				return testErr
+			}, tt.opts...)
+
+			err := p.Wait()
+
+			var te *TaskError
+			if !errors.As(err, &te) {
+				t.Fatalf("Pool.Wait() = %v, want a TaskError", err)
+			}
+
+			if te.ID != 1 || te.Name != tt.wantName || te.Took() < time.Millisecond || te.Waited() < 0 {
+				t.Errorf("TaskError = %+v, want ID 1, name %q and durations", te, tt.wantName)
+			}
+
+			if !errors.Is(err, testErr) {
+				t.Errorf("Pool.Wait() = %v, want %v", err, testErr)
+			}
+
+			if got := te.Error(); got != tt.wantMsg {
+				t.Errorf("TaskError.Error() = %q, want %q", got, tt.wantMsg)
+			}
+
+			if err := f.Err(); err != testErr {
+				t.Errorf("Future.Err() = %v, want the error of the task as is", err)
+			}
+		})
+	}
+}
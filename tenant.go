@@ -0,0 +1,189 @@
+package gowp
+
+import "fmt"
+
+type (
+	// tenantQueue keeps a sub-queue per tenant and serves the tenants with pending jobs in turn,
+	// skipping the ones that already have as many running jobs as allowed. See WithTenantQuotas.
+	tenantQueue struct {
+		maxRunning int // zero means no limit.
+		maxQueued  int // zero means no limit.
+		newSub     func() queue
+
+		tenants map[string]*tenant
+		order   []string // tenants in turn order, the next turn starts at cursor.
+		cursor  int
+		n       int
+	}
+
+	tenant struct {
+		q       queue
+		running int
+	}
+)
+
+// interface guard
+var _ queue = (*tenantQueue)(nil)
+
+// WithTenantQuotas returns an Option that shares the pool fairly among tenants, see SubmitFor.
+// Each tenant gets its own queue and workers take jobs from the tenants in turn, so that a burst
+// from one tenant cannot starve the others. A tenant can't have more than maxRunning tasks running
+// and maxQueued tasks pending at once, submitting more fails with ErrTenantQuota. Zero means no limit.
+// Tasks submitted without a tenant share the empty tenant.
+//
+// Along with WithWeightedRandomDispatch or WithSchedulingPolicy, they apply within each tenant.
+// Negative quotas result in ErrInvalidQuota on Pool initialization.
+func WithTenantQuotas(maxRunning, maxQueued int) Option {
+	return func(o *config) {
+		o.tenants = &tenantQueue{maxRunning: maxRunning, maxQueued: maxQueued}
+	}
+}
+
+// TaskTenant returns a TaskOption that sets the tenant the task is submitted for, see WithTenantQuotas.
+func TaskTenant(tenant string) TaskOption {
+	return func(j *job) {
+		j.tenant = tenant
+	}
+}
+
+// SubmitFor submits t on behalf of tenant, see WithTenantQuotas.
+func (p *Pool) SubmitFor(tenant string, t Task) error {
+	if err := p.submit(t, nil, []TaskOption{TaskTenant(tenant)}); err != nil {
+		return fmt.Errorf("gowp.Pool.SubmitFor(): %w", err)
+	}
+
+	return nil
+}
+
+func newTenantQueue(quotas tenantQueue, newSub func() queue) *tenantQueue {
+	q := quotas
+	q.newSub = newSub
+	q.tenants = make(map[string]*tenant)
+
+	return &q
+}
+
+func (q *tenantQueue) push(j *job) error {
+	t, ok := q.tenants[j.tenant]
+	if !ok {
+		t = &tenant{q: q.newSub()}
+		q.tenants[j.tenant] = t
+		q.order = append(q.order, j.tenant)
+	}
+
+	if q.maxQueued > 0 && t.q.len() >= q.maxQueued {
+		return ErrTenantQuota
+	}
+
+	if err := t.q.push(j); err != nil {
+		return err
+	}
+
+	q.n++
+
+	return nil
+}
+
+func (q *tenantQueue) pop() *job {
+	return q.popMatching(nil)
+}
+
+func (q *tenantQueue) popIf(match func(*job) bool) *job {
+	return q.popMatching(match)
+}
+
+// popMatching pops from the next tenant in turn that is within its quota and has a pending job for which
+// match returns true, any pending job if match is nil.
+func (q *tenantQueue) popMatching(match func(*job) bool) *job {
+	for i := 0; i < len(q.order); i++ {
+		k := (q.cursor + i) % len(q.order)
+		t := q.tenants[q.order[k]]
+
+		if t.q.len() == 0 || (q.maxRunning > 0 && t.running >= q.maxRunning) {
+			continue
+		}
+
+		var j *job
+		if match == nil {
+			j = t.q.pop()
+		} else if j = t.q.popIf(match); j == nil {
+			continue
+		}
+
+		t.running++
+		q.n--
+		q.cursor = k + 1
+
+		return j
+	}
+
+	return nil // empty, or all the tenants with pending jobs are at their quota.
+}
+
+// finished accounts for a job returned by pop that is done.
+func (q *tenantQueue) finished(j *job) {
+	t := q.tenants[j.tenant]
+	t.running--
+
+	if t.running == 0 && t.q.len() == 0 {
+		q.forget(j.tenant)
+	}
+}
+
+// forget removes an idle tenant, so that tenants that come and go don't pile up.
+func (q *tenantQueue) forget(name string) {
+	delete(q.tenants, name)
+
+	for i, n := range q.order {
+		if n != name {
+			continue
+		}
+
+		q.order = append(q.order[:i], q.order[i+1:]...)
+		if q.cursor > i {
+			q.cursor--
+		}
+
+		return
+	}
+}
+
+func (q *tenantQueue) len() int {
+	return q.n
+}
+
+func (q *tenantQueue) oldest() *job {
+	var oldest *job
+	for _, t := range q.tenants {
+		if j := t.q.oldest(); j != nil && (oldest == nil || j.id < oldest.id) {
+			oldest = j
+		}
+	}
+
+	return oldest
+}
+
+func (q *tenantQueue) removeIf(drop func(*job) bool) []*job {
+	var removed []*job
+	for _, name := range append([]string(nil), q.order...) {
+		t := q.tenants[name]
+		removed = append(removed, t.q.removeIf(drop)...)
+
+		if t.running == 0 && t.q.len() == 0 {
+			q.forget(name)
+		}
+	}
+
+	q.n -= len(removed)
+
+	return removed
+}
+
+// leave accounts for a job that a worker is done with, releasing its tenant's quota.
+func (p *Pool) leave(j *job) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.tenants.finished(j)
+	p.ready.Broadcast() // workers may have skipped the tenant because of its quota.
+}
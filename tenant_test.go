@@ -0,0 +1,125 @@
+package gowp
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestTenantQueue(t *testing.T) {
+	q := newTenantQueue(tenantQueue{maxRunning: 1}, func() queue { return &fifo{} })
+
+	for i, name := range []string{"a", "a", "a", "b", "c"} {
+		_ = q.push(&job{id: uint64(i + 1), tenant: name})
+	}
+
+	// one job per tenant, each is at its quota afterwards.
+	var got []uint64
+	for j := q.pop(); j != nil; j = q.pop() {
+		got = append(got, j.id)
+	}
+
+	if want := []uint64{1, 4, 5}; !reflect.DeepEqual(got, want) {
+		t.Errorf("tenantQueue.pop() = %v, want %v", got, want)
+	}
+
+	q.finished(&job{tenant: "b"}) // b has nothing left, it is forgotten.
+	q.finished(&job{tenant: "a"})
+
+	if _, ok := q.tenants["b"]; ok {
+		t.Error("idle tenant b is still tracked")
+	}
+
+	if j := q.pop(); j == nil || j.id != 2 {
+		t.Errorf("tenantQueue.pop() = %v, want job 2", j)
+	}
+
+	if q.len() != 1 {
+		t.Errorf("tenantQueue.len() = %d, want 1", q.len())
+	}
+}
+
+func TestWithTenantQuotas(t *testing.T) {
+	p := testPool(context.Background(), 1, testDefaultNumTasks, false, WithTenantQuotas(1, 3))
+
+	var (
+		mu    sync.Mutex
+		order []string
+	)
+	task := func(tenant string) Task {
+		return func() error {
+			mu.Lock()
+			order = append(order, tenant)
+			mu.Unlock()
+			return nil
+		}
+	}
+
+	// hold the only worker, so that the noisy tenant queues up before the quiet one.
+	started, release := make(chan struct{}), make(chan struct{})
+	_ = p.SubmitFor("noisy", func() error { close(started); <-release; return nil })
+	<-started
+
+	for i := 0; i < 3; i++ {
+		_ = p.SubmitFor("noisy", task("noisy"))
+	}
+
+	if err := p.SubmitFor("noisy", task("noisy")); !errors.Is(err, ErrTenantQuota) {
+		t.Errorf("Pool.SubmitFor() over quota = %v, want %v", err, ErrTenantQuota)
+	}
+
+	_ = p.SubmitFor("quiet", task("quiet"))
+	close(release)
+
+	if err := p.Wait(); err != nil {
+		t.Fatalf("Pool.Wait() error = %v", err)
+	}
+
+	if len(order) != 4 || order[0] != "quiet" {
+		t.Errorf("execution order = %v, want the quiet tenant first", order)
+	}
+
+	if _, err := New(testDefaultNumTasks, WithTenantQuotas(-1, 0)); !errors.Is(err, ErrInvalidQuota) {
+		t.Errorf("New() = %v, want %v", err, ErrInvalidQuota)
+	}
+}
+
+func TestWithTenantQuotas_stopped(t *testing.T) {
+	p, _ := New(10, WithNumWorkers(1), WithTenantQuotas(1, 0))
+
+	started, release := make(chan struct{}), make(chan struct{})
+	_ = p.SubmitFor("a", func() error { close(started); <-release; return nil })
+	<-started
+
+	var futures []*Future
+	for i := 0; i < 3; i++ {
+		f, err := p.SubmitFuture(func() error { return nil }, TaskTenant("b"))
+		if err != nil {
+			t.Fatalf("Pool.SubmitFuture() error = %v", err)
+		}
+		futures = append(futures, f)
+	}
+
+	p.CloseWithError(nil)
+	<-p.Context().Done()
+	close(release)
+	_ = p.Wait()
+
+	// the tasks beyond the quota of b are discarded too.
+	for i, f := range futures {
+		select {
+		case <-f.Done():
+			if err := f.Err(); !errors.Is(err, ErrTaskDiscarded) {
+				t.Errorf("Future(%d).Err() = %v, want %v", i, err, ErrTaskDiscarded)
+			}
+		default:
+			t.Errorf("Future(%d) not done after Wait", i)
+		}
+	}
+
+	if n := p.Stats().Queued; n != 0 {
+		t.Errorf("Stats().Queued = %d, want 0", n)
+	}
+}
@@ -0,0 +1,172 @@
+package testutil
+
+import (
+	"sync"
+	"time"
+
+	"github.com/akshaybharambe14/gowp"
+)
+
+// interface guard
+var _ gowp.Clock = (*FakeClock)(nil)
+
+type (
+	// FakeClock is a gowp.Clock whose time only moves forward when Advance is called, so that tests of
+	// time-dependent behavior, e.g. delayed tasks, are fast and deterministic. See gowp.WithClock.
+	//
+	// Zero value is not usable. Use NewFakeClock() to create a FakeClock.
+	FakeClock struct {
+		mu     sync.Mutex
+		armed  sync.Cond    // signalled when a timer is armed.
+		now    time.Time    // guarded by mu.
+		timers []*fakeTimer // armed timers and tickers. Guarded by mu.
+	}
+
+	fakeTimer struct {
+		c      *FakeClock
+		at     time.Time     // when fn is due. Guarded by c.mu.
+		period time.Duration // zero for timers.
+		fn     func(now time.Time)
+	}
+
+	fakeTicker struct {
+		t  *fakeTimer
+		ch chan time.Time
+	}
+)
+
+// NewFakeClock creates a FakeClock telling the given time.
+func NewFakeClock(now time.Time) *FakeClock {
+	c := &FakeClock{now: now}
+	c.armed.L = &c.mu
+
+	return c
+}
+
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+// AfterFunc arms a timer calling fn once the clock has been advanced by d. If d isn't positive,
+// fn is called right away, in its own goroutine.
+func (c *FakeClock) AfterFunc(d time.Duration, fn func()) gowp.Timer {
+	t := &fakeTimer{c: c, fn: func(time.Time) { fn() }}
+	if d <= 0 {
+		go fn()
+		return t // not armed, Stop returns false.
+	}
+
+	c.arm(t, d)
+
+	return t
+}
+
+// NewTicker returns a Ticker delivering the time every d the clock is advanced by. Like time.Ticker,
+// it drops ticks for slow receivers. d should be greater than zero.
+func (c *FakeClock) NewTicker(d time.Duration) gowp.Ticker {
+	if d <= 0 {
+		panic("testutil.FakeClock.NewTicker(): non-positive interval")
+	}
+
+	tk := fakeTicker{ch: make(chan time.Time, 1)}
+	tk.t = &fakeTimer{c: c, period: d, fn: func(now time.Time) {
+		select {
+		case tk.ch <- now:
+		default:
+		}
+	}}
+
+	c.arm(tk.t, d)
+
+	return tk
+}
+
+// Advance moves the clock forward by d. The timers due meanwhile fire in chronological order,
+// their functions are called on the calling goroutine, with the clock set to the time they were due at.
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	end := c.now.Add(d)
+
+	for {
+		t := c.due(end)
+		if t == nil {
+			break
+		}
+
+		at := t.at
+		c.now = at
+		if t.period > 0 {
+			t.at = t.at.Add(t.period)
+		} else {
+			c.remove(t)
+		}
+
+		c.mu.Unlock()
+		t.fn(at)
+		c.mu.Lock()
+	}
+
+	c.now = end
+	c.mu.Unlock()
+}
+
+// WaitForTimers blocks until at least n timers and tickers are armed, e.g. by goroutines the test doesn't control.
+func (c *FakeClock) WaitForTimers(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for len(c.timers) < n {
+		c.armed.Wait()
+	}
+}
+
+func (c *FakeClock) arm(t *fakeTimer, d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	t.at = c.now.Add(d)
+	c.timers = append(c.timers, t)
+	c.armed.Broadcast()
+}
+
+// due returns the earliest timer due by end, nil if there is none. c.mu must be held.
+func (c *FakeClock) due(end time.Time) *fakeTimer {
+	var first *fakeTimer
+	for _, t := range c.timers {
+		if !t.at.After(end) && (first == nil || t.at.Before(first.at)) {
+			first = t
+		}
+	}
+
+	return first
+}
+
+// remove disarms t, it returns false if t wasn't armed. c.mu must be held.
+func (c *FakeClock) remove(t *fakeTimer) bool {
+	for i, other := range c.timers {
+		if other == t {
+			c.timers = append(c.timers[:i], c.timers[i+1:]...)
+			return true
+		}
+	}
+
+	return false
+}
+
+func (t *fakeTimer) Stop() bool {
+	t.c.mu.Lock()
+	defer t.c.mu.Unlock()
+
+	return t.c.remove(t)
+}
+
+func (tk fakeTicker) C() <-chan time.Time {
+	return tk.ch
+}
+
+func (tk fakeTicker) Stop() {
+	tk.t.Stop()
+}
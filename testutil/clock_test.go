@@ -0,0 +1,86 @@
+package testutil
+
+import (
+	"testing"
+	"time"
+
+	"github.com/akshaybharambe14/gowp"
+	"github.com/akshaybharambe14/gowp/schedule"
+)
+
+func TestFakeClock(t *testing.T) {
+	start := time.Date(2021, 9, 5, 0, 0, 0, 0, time.UTC)
+	c := NewFakeClock(start)
+
+	var fired []time.Time
+	c.AfterFunc(2*time.Second, func() { fired = append(fired, c.Now()) })
+	c.AfterFunc(time.Second, func() { fired = append(fired, c.Now()) })
+	stopped := c.AfterFunc(time.Second, func() { t.Error("stopped timer fired") })
+	if !stopped.Stop() || stopped.Stop() {
+		t.Error("Timer.Stop() didn't report the timer as armed exactly once")
+	}
+
+	tk := c.NewTicker(time.Second)
+
+	c.Advance(500 * time.Millisecond)
+	if len(fired) != 0 {
+		t.Errorf("%d timers fired before they were due", len(fired))
+	}
+
+	c.Advance(2 * time.Second)
+	if want := []time.Time{start.Add(time.Second), start.Add(2 * time.Second)}; len(fired) != 2 || !fired[0].Equal(want[0]) || !fired[1].Equal(want[1]) {
+		t.Errorf("timers fired at %v, want %v", fired, want)
+	}
+
+	if now := c.Now(); !now.Equal(start.Add(2500 * time.Millisecond)) {
+		t.Errorf("Now() = %v, want %v", now, start.Add(2500*time.Millisecond))
+	}
+
+	// the second tick is dropped, nobody received the first one.
+	if tick := <-tk.C(); !tick.Equal(start.Add(time.Second)) {
+		t.Errorf("tick at %v, want %v", tick, start.Add(time.Second))
+	}
+	tk.Stop()
+
+	done := make(chan struct{})
+	c.AfterFunc(0, func() { close(done) })
+	<-done
+}
+
+func TestFakeClock_pool(t *testing.T) {
+	c := NewFakeClock(time.Now())
+	p, _ := NewSyncPool(gowp.WithClock(c))
+
+	ran := 0
+	task := func() error {
+		ran++
+		return nil
+	}
+
+	_, _ = p.SubmitAfter(time.Hour, task)
+	_, _ = p.SubmitAt(c.Now().Add(2*time.Hour), task)
+
+	s := schedule.New(p)
+	_, _ = s.Add(schedule.Every(time.Minute), task, schedule.Skip)
+	// the scheduler arms its timer in its own goroutine, once done with the previous activation.
+	for i := 0; i < 60; i++ {
+		c.WaitForTimers(3)
+		c.Advance(time.Minute)
+	}
+	c.WaitForTimers(2) // the task delayed by an hour ran.
+
+	if ran != 61 {
+		t.Errorf("%d tasks ran after an hour, want 61", ran)
+	}
+
+	s.Stop()
+	c.Advance(time.Hour)
+
+	if err := p.Wait(); err != nil {
+		t.Errorf("Pool.Wait() = %v, want nil", err)
+	}
+
+	if ran != 62 {
+		t.Errorf("%d tasks ran after two hours, want 62", ran)
+	}
+}
@@ -0,0 +1,80 @@
+package testutil
+
+import (
+	"bytes"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/akshaybharambe14/gowp"
+)
+
+// shutdownTimeout bounds how long VerifyShutdown waits for the pool to complete and its goroutines to exit.
+var shutdownTimeout = time.Second
+
+// poolFrame prefixes the stack frames of the gowp package, but not of its sub-packages.
+const poolFrame = "github.com/akshaybharambe14/gowp."
+
+// VerifyShutdown fails the test unless p has completed, i.e. Wait has returned, and the goroutines running
+// pool code, e.g. workers, the monitor or callbacks, have exited. Goroutines exit shortly after Wait returns,
+// VerifyShutdown gives them up to a second. It is typically deferred right after creating the pool.
+//
+// Goroutines are recognised by the code they run, not by the pool they belong to: goroutines of other pools
+// still running are reported as well. Tests calling VerifyShutdown shouldn't run in parallel with tests using pools.
+func VerifyShutdown(t testing.TB, p *gowp.Pool) {
+	t.Helper()
+
+	deadline := time.Now().Add(shutdownTimeout)
+
+	done := make(chan struct{})
+	stop := p.AfterFunc(func(gowp.Report) { close(done) })
+
+	select {
+	case <-done:
+	case <-time.After(shutdownTimeout):
+		stop()
+		t.Error("testutil.VerifyShutdown(): the pool hasn't completed, Wait hasn't been called or hasn't returned")
+		return
+	}
+
+	leaked := poolGoroutines()
+	for len(leaked) > 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+		leaked = poolGoroutines()
+	}
+
+	if len(leaked) > 0 {
+		t.Errorf("testutil.VerifyShutdown(): %d goroutines running pool code are left:\n\n%s", len(leaked), strings.Join(leaked, "\n\n"))
+	}
+}
+
+// poolGoroutines returns the stacks of the goroutines, other than the calling one, running or created by gowp code.
+func poolGoroutines() []string {
+	buf := make([]byte, 64<<10)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			buf = buf[:n]
+			break
+		}
+
+		buf = make([]byte, 2*len(buf))
+	}
+
+	var leaked []string
+	for i, g := range bytes.Split(buf, []byte("\n\n")) {
+		if i == 0 {
+			continue // the calling goroutine comes first.
+		}
+
+		for _, line := range strings.Split(string(g), "\n") {
+			if strings.HasPrefix(line, poolFrame) || strings.HasPrefix(line, "created by "+poolFrame) {
+				leaked = append(leaked, string(g))
+				break
+			}
+		}
+	}
+
+	return leaked
+}
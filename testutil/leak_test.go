@@ -0,0 +1,87 @@
+package testutil
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/akshaybharambe14/gowp"
+)
+
+// recorder is a testing.TB recording failures instead of failing the test.
+type recorder struct {
+	testing.TB
+	errs []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Error(args ...interface{}) {
+	r.errs = append(r.errs, fmt.Sprint(args...))
+}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.errs = append(r.errs, fmt.Sprintf(format, args...))
+}
+
+func TestVerifyShutdown(t *testing.T) {
+	defer func(d time.Duration) { shutdownTimeout = d }(shutdownTimeout)
+	shutdownTimeout = 100 * time.Millisecond
+
+	failing := func() error { return errors.New("failed") }
+
+	tests := []struct {
+		name   string
+		run    func() (p *gowp.Pool, cleanup func())
+		wantOK bool
+	}{
+		{name: "waited", wantOK: true, run: func() (*gowp.Pool, func()) {
+			p, _ := gowp.New(10, gowp.WithNumWorkers(4))
+			_ = p.Submit(func() error { return nil })
+			_ = p.Wait()
+			return p, func() {}
+		}},
+		{name: "failed without exit on error", wantOK: true, run: func() (*gowp.Pool, func()) {
+			p, _ := gowp.New(10, gowp.WithNumWorkers(4), gowp.WithExitOnError(false))
+			_ = p.Submit(failing)
+			_ = p.Wait()
+			return p, func() {}
+		}},
+		{name: "delayed and boosted", wantOK: true, run: func() (*gowp.Pool, func()) {
+			p, _ := gowp.New(10, gowp.WithNumWorkers(1), gowp.WithWorkerBoost(time.Millisecond, 2))
+			_, _ = p.SubmitAfter(time.Millisecond, failing)
+			_ = p.Wait()
+			return p, func() {}
+		}},
+		{name: "inline", wantOK: true, run: func() (*gowp.Pool, func()) {
+			p, _ := NewSyncPool()
+			_ = p.Submit(failing)
+			_ = p.Wait()
+			return p, func() {}
+		}},
+		{name: "not waited", run: func() (*gowp.Pool, func()) {
+			p, _ := gowp.New(10, gowp.WithNumWorkers(1))
+			return p, func() { _ = p.Wait() }
+		}},
+		{name: "other pool running", run: func() (*gowp.Pool, func()) {
+			p, _ := gowp.New(10, gowp.WithNumWorkers(1))
+			_ = p.Wait()
+			other, _ := gowp.New(10, gowp.WithNumWorkers(1))
+			return p, func() { _ = other.Wait() }
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, cleanup := tt.run()
+			defer cleanup()
+
+			r := &recorder{TB: t}
+			VerifyShutdown(r, p)
+
+			if ok := len(r.errs) == 0; ok != tt.wantOK {
+				t.Errorf("VerifyShutdown() reported %q, want ok = %v", r.errs, tt.wantOK)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package testutil
+
+import (
+	"sync"
+
+	"github.com/akshaybharambe14/gowp"
+)
+
+// interface guard
+var _ gowp.Pooler = (*MockPool)(nil)
+
+// MockPool is a gowp.Pooler whose behavior can be replaced method by method. Methods call the matching
+// function field if it is set, otherwise a pool created by NewSyncPool, so that a test only overrides
+// what it is about, e.g. SubmitFunc returning gowp.ErrNoBuffer.
+//
+// Zero value is not usable. Use NewMockPool() to create a MockPool. The function fields should be set before use.
+type MockPool struct {
+	SubmitFunc       func(t gowp.Task) error
+	SubmitFutureFunc func(t gowp.Task, opts ...gowp.TaskOption) (*gowp.Future, error)
+	CloseFunc        func()
+	WaitFunc         func() error
+	StatsFunc        func() gowp.Stats
+
+	p *gowp.Pool
+
+	mu        sync.Mutex
+	submitted int // see Submitted. Guarded by mu.
+}
+
+// NewMockPool creates a MockPool executing tasks inline, see NewSyncPool.
+func NewMockPool() *MockPool {
+	p, _ := NewSyncPool() // can't fail without options.
+
+	return &MockPool{p: p}
+}
+
+// Submitted returns the number of calls to Submit and SubmitFuture, whether they failed or not.
+func (m *MockPool) Submitted() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.submitted
+}
+
+func (m *MockPool) Submit(t gowp.Task) error {
+	m.count()
+
+	if m.SubmitFunc != nil {
+		return m.SubmitFunc(t)
+	}
+
+	return m.p.Submit(t)
+}
+
+func (m *MockPool) SubmitFuture(t gowp.Task, opts ...gowp.TaskOption) (*gowp.Future, error) {
+	m.count()
+
+	if m.SubmitFutureFunc != nil {
+		return m.SubmitFutureFunc(t, opts...)
+	}
+
+	return m.p.SubmitFuture(t, opts...)
+}
+
+func (m *MockPool) Close() {
+	if m.CloseFunc != nil {
+		m.CloseFunc()
+		return
+	}
+
+	m.p.Close()
+}
+
+func (m *MockPool) Wait() error {
+	if m.WaitFunc != nil {
+		return m.WaitFunc()
+	}
+
+	return m.p.Wait()
+}
+
+func (m *MockPool) Stats() gowp.Stats {
+	if m.StatsFunc != nil {
+		return m.StatsFunc()
+	}
+
+	return m.p.Stats()
+}
+
+func (m *MockPool) count() {
+	m.mu.Lock()
+	m.submitted++
+	m.mu.Unlock()
+}
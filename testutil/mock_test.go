@@ -0,0 +1,97 @@
+package testutil
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/akshaybharambe14/gowp"
+)
+
+// submitAll is code under test, it depends on the interface.
+func submitAll(p gowp.Pooler, tasks []gowp.Task) error {
+	for _, t := range tasks {
+		if err := p.Submit(t); err != nil {
+			return err
+		}
+	}
+
+	return p.Wait()
+}
+
+func TestMockPool(t *testing.T) {
+	errTask := errors.New("task failed")
+
+	tests := []struct {
+		name          string
+		setup         func(m *MockPool)
+		wantErr       error
+		wantRan       int
+		wantSubmitted int
+	}{
+		{name: "delegates to a sync pool", wantRan: 3, wantSubmitted: 3},
+		{
+			name: "queue full",
+			setup: func(m *MockPool) {
+				m.SubmitFunc = func(gowp.Task) error { return gowp.ErrNoBuffer }
+			},
+			wantErr:       gowp.ErrNoBuffer,
+			wantSubmitted: 1,
+		},
+		{
+			name: "wait error",
+			setup: func(m *MockPool) {
+				m.WaitFunc = func() error { return errTask }
+			},
+			wantErr:       errTask,
+			wantRan:       3,
+			wantSubmitted: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewMockPool()
+			defer func() { _ = m.p.Wait() }() // the overridden Wait doesn't release the pool.
+
+			if tt.setup != nil {
+				tt.setup(m)
+			}
+
+			ran := 0
+			task := func() error {
+				ran++
+				return nil
+			}
+
+			err := submitAll(m, []gowp.Task{task, task, task})
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("submitAll() = %v, want %v", err, tt.wantErr)
+			}
+
+			if ran != tt.wantRan || m.Submitted() != tt.wantSubmitted {
+				t.Errorf("ran %d tasks out of %d submitted, want %d out of %d", ran, m.Submitted(), tt.wantRan, tt.wantSubmitted)
+			}
+		})
+	}
+
+	m := NewMockPool()
+	f, err := m.SubmitFuture(func() error { return errTask })
+	if err != nil {
+		t.Fatalf("MockPool.SubmitFuture() error = %v", err)
+	}
+
+	if err := f.Err(); !errors.Is(err, errTask) {
+		t.Errorf("Future.Err() = %v, want %v", err, errTask)
+	}
+
+	m.Close()
+	if err := m.Submit(func() error { return nil }); !errors.Is(err, gowp.ErrPoolClosed) {
+		t.Errorf("MockPool.Submit() after Close = %v, want %v", err, gowp.ErrPoolClosed)
+	}
+
+	if s := m.Stats(); !s.Closed || s.Failed != 1 {
+		t.Errorf("MockPool.Stats() = %+v, want closed with 1 failed task", s)
+	}
+
+	_ = m.Wait()
+}
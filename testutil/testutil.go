@@ -0,0 +1,33 @@
+// Package testutil helps testing code that uses gowp pools.
+//
+// Example:
+//	func TestImport(t *testing.T) {
+//		wp, _ := testutil.NewSyncPool()
+//
+//		imp := NewImporter(wp)
+//		imp.Import(rows) // tasks have run once Import returns, in the order they were submitted.
+//
+//		if err := wp.Wait(); err != nil {
+//			t.Fatal(err)
+//		}
+//	}
+package testutil // import "github.com/akshaybharambe14/gowp/testutil"
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/akshaybharambe14/gowp"
+)
+
+// NewSyncPool returns a pool executing each task on the goroutine submitting it, before Submit returns,
+// see gowp.WithInlineExecution. Tasks run one at a time, in the order they are queued, so that tests of code
+// using the pool are reproducible. Its queue has no practical bound, opts can configure the rest of the pool.
+func NewSyncPool(opts ...gowp.Option) (*gowp.Pool, error) {
+	p, err := gowp.New(math.MaxInt32, append(opts, gowp.WithInlineExecution())...)
+	if err != nil {
+		return nil, fmt.Errorf("testutil.NewSyncPool(): %w", err)
+	}
+
+	return p, nil
+}
@@ -0,0 +1,49 @@
+package testutil
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/akshaybharambe14/gowp"
+)
+
+func TestNewSyncPool(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    []gowp.Option
+		wantErr error
+	}{
+		{name: "default"},
+		{name: "exit on error", opts: []gowp.Option{gowp.WithExitOnError(true)}},
+		{name: "invalid option", opts: []gowp.Option{gowp.WithContext(nil)}, wantErr: gowp.ErrNilContext},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := NewSyncPool(tt.opts...)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("NewSyncPool() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if err != nil {
+				return
+			}
+
+			ran := 0
+			for i := 0; i < 3; i++ {
+				_ = p.Submit(func() error {
+					ran++
+					return nil
+				})
+
+				if ran != i+1 {
+					t.Fatalf("%d tasks executed after %d submissions", ran, i+1)
+				}
+			}
+
+			if err := p.Wait(); err != nil {
+				t.Errorf("Pool.Wait() = %v, want nil", err)
+			}
+		})
+	}
+}
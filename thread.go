@@ -0,0 +1,12 @@
+package gowp
+
+// WithLockedOSThreads returns an Option that wires each worker to an operating system thread, with
+// runtime.LockOSThread, for as long as the worker runs. Tasks that call into cgo libraries or graphics and
+// FFI contexts that demand thread affinity then always run on the thread of their worker. The OnWorkerStart
+// hooks run on the locked thread too, so they can set up the per-thread state of such libraries.
+// It has no effect along with WithInlineExecution, tasks run on the goroutine submitting them.
+func WithLockedOSThreads() Option {
+	return func(o *config) {
+		o.lockThreads = true
+	}
+}
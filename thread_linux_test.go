@@ -0,0 +1,40 @@
+package gowp
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"syscall"
+	"testing"
+)
+
+func TestWithLockedOSThreads(t *testing.T) {
+	var (
+		mu   sync.Mutex
+		tids = make(map[int]bool)
+	)
+	record := func() {
+		mu.Lock()
+		tids[syscall.Gettid()] = true
+		mu.Unlock()
+	}
+
+	p := testPool(context.Background(), 1, testDefaultNumTasks, false,
+		WithLockedOSThreads(), WithHooks(Hooks{OnWorkerStart: record}))
+
+	for i := 0; i < testDefaultNumTasks; i++ {
+		_ = p.Submit(func() error {
+			runtime.Gosched() // the goroutine would be free to move to another thread here.
+			record()
+			return nil
+		})
+	}
+
+	if err := p.Wait(); err != nil {
+		t.Fatalf("Pool.Wait() error = %v", err)
+	}
+
+	if len(tids) != 1 {
+		t.Errorf("the worker ran on %d threads, want 1", len(tids))
+	}
+}
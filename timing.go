@@ -0,0 +1,32 @@
+package gowp
+
+import "time"
+
+// Timing tells when a task went through the steps of its execution, as per the clock of the pool, see WithClock.
+// It is reported by Future.Timing, along with the results of a TypedPool and in TaskError. It is zero for the
+// tasks that were not executed, e.g. cancelled while queued.
+type Timing struct {
+	QueuedAt   time.Time // when the pool accepted the task.
+	StartedAt  time.Time // when a worker started the task.
+	FinishedAt time.Time // when the task returned.
+}
+
+// Waited returns the time the task spent queued.
+func (t Timing) Waited() time.Duration {
+	return t.StartedAt.Sub(t.QueuedAt)
+}
+
+// Took returns the time the task ran for.
+func (t Timing) Took() time.Duration {
+	return t.FinishedAt.Sub(t.StartedAt)
+}
+
+// Timing blocks until the task has finished and returns when it was queued, started and finished.
+func (f *Future) Timing() Timing {
+	<-f.done
+	return f.timing
+}
+
+func (j *job) timing() Timing {
+	return Timing{QueuedAt: j.submittedAt, StartedAt: j.startedAt, FinishedAt: j.finishedAt}
+}
@@ -0,0 +1,88 @@
+package gowp
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestFuture_Timing(t *testing.T) {
+	errTask := errors.New("task failed")
+
+	tests := []struct {
+		name       string
+		err        error
+		cancel     bool
+		wantWaited time.Duration
+		wantTook   time.Duration
+	}{
+		{name: "succeeded", wantWaited: time.Second, wantTook: 2 * time.Second},
+		{name: "failed", err: errTask, wantWaited: time.Second, wantTook: 2 * time.Second},
+		{name: "canceled", cancel: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &testClock{now: time.Now()}
+			p, _ := New(1, WithNumWorkers(1), WithClock(c))
+			p.Pause()
+
+			f, err := p.SubmitFuture(func() error {
+				c.advance(2 * time.Second)
+				return tt.err
+			})
+			if err != nil {
+				t.Fatalf("Pool.SubmitFuture() error = %v", err)
+			}
+
+			queuedAt := c.Now()
+			c.advance(time.Second)
+			if tt.cancel {
+				f.Cancel()
+			}
+			p.Resume()
+
+			werr := p.Wait()
+
+			got := f.Timing()
+			if tt.cancel {
+				if got != (Timing{}) {
+					t.Errorf("Future.Timing() = %+v, want zero for a cancelled task", got)
+				}
+				return
+			}
+
+			if !got.QueuedAt.Equal(queuedAt) || got.Waited() != tt.wantWaited || got.Took() != tt.wantTook {
+				t.Errorf("Future.Timing() = %+v, want queued at %v, waited %v, took %v", got, queuedAt, tt.wantWaited, tt.wantTook)
+			}
+
+			var te *TaskError
+			if tt.err != nil && (!errors.As(werr, &te) || te.Timing != got) {
+				t.Errorf("Pool.Wait() error = %v, want a TaskError with timing %+v", werr, got)
+			}
+		})
+	}
+}
+
+func TestResult_Timing(t *testing.T) {
+	c := &testClock{now: time.Now()}
+	tp, _ := NewTyped[int](1, WithNumWorkers(1), WithClock(c))
+	results := tp.Results()
+
+	tf, err := tp.Submit(func() (int, error) {
+		c.advance(time.Second)
+		return 1, nil
+	})
+	if err != nil {
+		t.Fatalf("TypedPool.Submit() error = %v", err)
+	}
+
+	r := <-results
+	if r.Timing != tf.Timing() || r.Timing.Took() != time.Second {
+		t.Errorf("Result.Timing = %+v, want %+v, took %v", r.Timing, tf.Timing(), time.Second)
+	}
+
+	if err := tp.Wait(); err != nil {
+		t.Errorf("TypedPool.Wait() error = %v", err)
+	}
+}
@@ -0,0 +1,158 @@
+package gowp
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+type (
+	// TypedPool is a Pool whose tasks produce a value of type T.
+	// Use NewTyped() to create a new TypedPool.
+	TypedPool[T any] struct {
+		seq uint64 // last sequence number assigned to a task, kept first for 64-bit alignment. Should be manipulated by sync/atomic.
+
+		p *Pool
+
+		results   chan Result[T]    // results of finished tasks, see Results.
+		streaming uint32            // set to 1 once Results is called. Should be manipulated by sync/atomic.
+		ordered   *reorderBuffer[T] // nil, unless WithOrderedResults is used.
+	}
+
+	// Result is the outcome of a task submitted to a TypedPool.
+	Result[T any] struct {
+		Value  T
+		Err    error
+		Timing Timing // see Future.Timing.
+	}
+
+	// reorderBuffer holds back results that finished before the ones submitted earlier.
+	reorderBuffer[T any] struct {
+		mu     sync.Mutex
+		next   uint64                // sequence number of the next result to deliver.
+		parked map[uint64]*Result[T] // nil marks a task that was rejected, there is nothing to deliver.
+	}
+)
+
+// NewTyped creates a TypedPool. It accepts the same arguments as New.
+func NewTyped[T any](numTasks int, opts ...Option) (*TypedPool[T], error) {
+	cfg, err := newConfig(numTasks, opts)
+	if err != nil {
+		return nil, fmt.Errorf("gowp.NewTyped(): %w", err)
+	}
+
+	tp := &TypedPool[T]{
+		p:       newPool(cfg, numTasks),
+		results: make(chan Result[T]),
+	}
+
+	if cfg.ordered {
+		tp.ordered = &reorderBuffer[T]{next: 1, parked: make(map[uint64]*Result[T])}
+	}
+
+	// all the tasks have finished by the time the pool completes, nobody sends on results anymore.
+	tp.p.AfterFunc(func(Report) { close(tp.results) })
+
+	return tp, nil
+}
+
+func (tp *TypedPool[T]) IsClosed() bool {
+	return tp.p.IsClosed()
+}
+
+// Submit submits fn to the pool and returns a TypedFuture to retrieve its result.
+func (tp *TypedPool[T]) Submit(fn func() (T, error), opts ...TaskOption) (*TypedFuture[T], error) {
+	if fn == nil {
+		return nil, fmt.Errorf("gowp.TypedPool.Submit(): %w", ErrNilTask)
+	}
+
+	seq := atomic.AddUint64(&tp.seq, 1)
+	tf := &TypedFuture[T]{Future: newFuture()}
+	t := func() error {
+		v, err := fn()
+		tf.val = v
+		return err
+	}
+
+	finish := func(err error) {
+		tp.deliver(seq, &Result[T]{Value: tf.val, Err: err, Timing: tf.timing})
+	}
+
+	m := memo{
+		value: func() any { return tf.val },
+		restore: func(v any) bool {
+			val, ok := v.(T)
+			tf.val = val
+			return ok
+		},
+	}
+
+	// don't modify the backing array of the caller's options.
+	opts = append(opts[:len(opts):len(opts)], func(j *job) { j.finish, j.memo = finish, m })
+
+	if err := tp.p.submit(t, tf.Future, opts); err != nil {
+		tp.deliver(seq, nil)
+		return nil, fmt.Errorf("gowp.TypedPool.Submit(): %w", err)
+	}
+
+	return tf, nil
+}
+
+// Results returns a channel that receives the result of every task as soon as it has finished.
+// Tasks that were cancelled or discarded produce a result with ErrTaskCanceled or ErrTaskDiscarded.
+// Results of tasks that finish before the first call to Results are not delivered, so it should be
+// called before submitting tasks. See WithOrderedResults to receive results in submission order.
+//
+// The channel is unbuffered and workers block until their result is received, a slow consumer
+// slows down the pool instead of losing results. The channel is closed once the pool completes,
+// so the consumer should keep receiving until then, typically in its own goroutine while Wait is called.
+func (tp *TypedPool[T]) Results() <-chan Result[T] {
+	atomic.StoreUint32(&tp.streaming, 1)
+	return tp.results
+}
+
+func (tp *TypedPool[T]) Wait() error {
+	return tp.p.Wait()
+}
+
+// deliver sends the result of the task with sequence number seq. r is nil for rejected tasks.
+func (tp *TypedPool[T]) deliver(seq uint64, r *Result[T]) {
+	if tp.ordered == nil {
+		if r != nil {
+			tp.send(*r)
+		}
+
+		return
+	}
+
+	tp.ordered.put(seq, r, tp.send)
+}
+
+func (tp *TypedPool[T]) send(r Result[T]) {
+	if atomic.LoadUint32(&tp.streaming) == 1 {
+		tp.results <- r
+	}
+}
+
+// put parks r and sends all the results that are in order. Sending happens with the lock held,
+// so that the results are sent in order. It also makes other workers wait, which is the backpressure we want.
+func (b *reorderBuffer[T]) put(seq uint64, r *Result[T], send func(Result[T])) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.parked[seq] = r
+
+	for {
+		r, ok := b.parked[b.next]
+		if !ok {
+			return
+		}
+
+		delete(b.parked, b.next)
+		b.next++
+
+		if r != nil {
+			send(*r)
+		}
+	}
+}
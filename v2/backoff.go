@@ -0,0 +1,28 @@
+package gowp
+
+import (
+	"time"
+
+	v1 "github.com/akshaybharambe14/gowp"
+)
+
+// ConstantBackoff returns a Backoff waiting d before every redelivery.
+func ConstantBackoff(d time.Duration) Backoff {
+	return v1.ConstantBackoff(d)
+}
+
+// ExponentialBackoff returns a Backoff doubling the delay from base for every redelivery, up to max.
+// max <= 0 means no limit.
+func ExponentialBackoff(base, max time.Duration) Backoff {
+	return v1.ExponentialBackoff(base, max)
+}
+
+// ExponentialJitterBackoff returns a Backoff waiting a random delay between zero and the one of ExponentialBackoff.
+func ExponentialJitterBackoff(base, max time.Duration) Backoff {
+	return v1.ExponentialJitterBackoff(base, max)
+}
+
+// FibonacciBackoff returns a Backoff waiting base times the Fibonacci numbers, up to max. max <= 0 means no limit.
+func FibonacciBackoff(base, max time.Duration) Backoff {
+	return v1.FibonacciBackoff(base, max)
+}
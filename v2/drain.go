@@ -0,0 +1,23 @@
+package gowp
+
+import (
+	"os"
+	"time"
+
+	v1 "github.com/akshaybharambe14/gowp"
+)
+
+// DrainOnSignal shuts p down gracefully once sig is received: the pool stops accepting tasks and gets timeout
+// to execute the queued ones, then it is stopped and Wait reports ErrDrainTimeout. The returned channel
+// receives the error returned by Wait once the pool is done.
+func DrainOnSignal(p *Pool, sig os.Signal, timeout time.Duration) <-chan error {
+	out := make(chan error, 1)
+	drained := v1.DrainOnSignal(p.p, sig, timeout)
+
+	go func() {
+		<-drained
+		out <- p.Wait() // the same error, Wait also completes the life cycle of p.
+	}()
+
+	return out
+}
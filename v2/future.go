@@ -0,0 +1,49 @@
+package gowp
+
+import (
+	"context"
+	"fmt"
+
+	v1 "github.com/akshaybharambe14/gowp"
+)
+
+type (
+	// Future is a handle to a single submitted task. Err blocks until the task has finished,
+	// Cancel retracts the task if it hasn't started yet.
+	Future = v1.Future
+
+	// TypedFuture is a Future for a task that produces a value of type T.
+	TypedFuture[T any] struct {
+		*Future
+		val T // written by the task before the Future completes.
+	}
+)
+
+// SubmitTyped submits fn to p and returns a TypedFuture to retrieve its value.
+// It is a function rather than a method, because methods can't have type parameters.
+func SubmitTyped[T any](p *Pool, fn func(ctx context.Context) (T, error), opts ...TaskOption) (*TypedFuture[T], error) {
+	if fn == nil {
+		return nil, fmt.Errorf("gowp.SubmitTyped(): %w", ErrNilTask)
+	}
+
+	tf := &TypedFuture[T]{}
+	f, err := p.Submit(func(ctx context.Context) error {
+		v, err := fn(ctx)
+		tf.val = v
+		return err
+	}, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("gowp.SubmitTyped(): %w", err)
+	}
+
+	tf.Future = f
+
+	return tf, nil
+}
+
+// Result blocks until the task has finished and returns its value and error.
+// The value is the zero value of T if the task wasn't executed.
+func (f *TypedFuture[T]) Result() (T, error) {
+	err := f.Err()
+	return f.val, err
+}
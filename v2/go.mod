@@ -0,0 +1,9 @@
+module github.com/akshaybharambe14/gowp/v2
+
+go 1.20
+
+require github.com/akshaybharambe14/gowp v0.0.0-00010101000000-000000000000
+
+// the core module is built from this repository until a release of it with the APIs used here is tagged,
+// the required version is a placeholder.
+replace github.com/akshaybharambe14/gowp => ./..
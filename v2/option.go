@@ -0,0 +1,346 @@
+package gowp
+
+import (
+	"context"
+	"time"
+
+	v1 "github.com/akshaybharambe14/gowp"
+)
+
+type (
+	// Option configures a Pool, see New.
+	Option func(o *config)
+
+	// TaskOption configures a single task, see Pool.Submit.
+	TaskOption = v1.TaskOption
+
+	config struct {
+		ctx  context.Context
+		opts []v1.Option
+	}
+)
+
+// WithContext returns an Option that sets the parent context of the pool.
+// If it is cancelled, the pool stops and the context passed to running tasks is cancelled.
+func WithContext(ctx context.Context) Option {
+	return func(o *config) {
+		o.ctx = ctx
+	}
+}
+
+// WithWorkers returns an Option that sets the number of workers, runtime.NumCPU() by default.
+func WithWorkers(n int) Option {
+	return wrap(v1.WithNumWorkers(n))
+}
+
+// WithExitOnError returns an Option that stops the pool on the first error.
+func WithExitOnError() Option {
+	return wrap(v1.WithExitOnError(true))
+}
+
+// WithMaxErrors returns an Option that stops the pool once n tasks have failed.
+func WithMaxErrors(n int) Option {
+	return wrap(v1.WithMaxErrors(n))
+}
+
+// WithErrorClassifier returns an Option that sets the function deciding the Severity of task errors.
+func WithErrorClassifier(classify func(error) Severity) Option {
+	return wrap(v1.WithErrorClassifier(classify))
+}
+
+// WithFirstSuccess returns an Option that stops the pool as soon as a task succeeds.
+func WithFirstSuccess() Option {
+	return wrap(v1.WithFirstSuccess())
+}
+
+// WithHooks returns an Option that registers hooks with the pool.
+func WithHooks(h Hooks) Option {
+	return wrap(v1.WithHooks(h))
+}
+
+// WithHookErrorHandler returns an Option that sets the handler of panics recovered from hooks.
+func WithHookErrorHandler(handler func(error)) Option {
+	return wrap(v1.WithHookErrorHandler(handler))
+}
+
+// WithSchedulingPolicy returns an Option that sets the order in which queued tasks are run.
+func WithSchedulingPolicy(policy SchedulingPolicy) Option {
+	return wrap(v1.WithSchedulingPolicy(policy))
+}
+
+// WithWeightedRandomDispatch returns an Option that splits the queue into weighted, labeled sub-queues.
+func WithWeightedRandomDispatch(weights map[string]int) Option {
+	return wrap(v1.WithWeightedRandomDispatch(weights))
+}
+
+// WithWeightedFairQueuing returns an Option that splits the queue into weighted, labeled sub-queues that
+// workers pick from in turn, proportionally to their weights.
+func WithWeightedFairQueuing(weights map[string]int) Option {
+	return wrap(v1.WithWeightedFairQueuing(weights))
+}
+
+// WithWorkerBoost returns an Option that adds temporary workers, up to ceiling, while tasks wait longer than threshold.
+func WithWorkerBoost(threshold time.Duration, ceiling int) Option {
+	return wrap(v1.WithWorkerBoost(threshold, ceiling))
+}
+
+// WithBurst returns an Option that lets the pool run up to ceiling workers during spikes,
+// one extra worker per token, regaining a token every refill.
+func WithBurst(ceiling int, refill time.Duration) Option {
+	return wrap(v1.WithBurst(ceiling, refill))
+}
+
+// WithAdaptiveConcurrency returns an Option that lowers the number of tasks running at once, down to min,
+// while tasks are slower than tolerance times their usual latency, and raises it back once they recover.
+func WithAdaptiveConcurrency(min int, tolerance float64) Option {
+	return wrap(v1.WithAdaptiveConcurrency(min, tolerance))
+}
+
+// WithGCPressureLimit returns an Option that steps the number of tasks running at once down, not below min, when the
+// garbage collector uses more than threshold of the CPU, and back up once it recovers.
+func WithGCPressureLimit(min int, threshold float64) Option {
+	return wrap(v1.WithGCPressureLimit(min, threshold))
+}
+
+// WithLatencyAutoscaler returns an Option that resizes the pool between min and max workers to keep the given
+// percentile of the latency of the tasks within target.
+func WithLatencyAutoscaler(target time.Duration, percentile float64, min, max int) Option {
+	return wrap(v1.WithLatencyAutoscaler(target, percentile, min, max))
+}
+
+// WithSlowTaskLog returns an Option that logs a sampleRate share of the tasks running for longer than threshold,
+// along with where they were submitted from.
+func WithSlowTaskLog(threshold time.Duration, sampleRate float64) Option {
+	return wrap(v1.WithSlowTaskLog(threshold, sampleRate))
+}
+
+// WithCapacity returns an Option that bounds the total weight of the tasks running at once, see TaskWeight.
+func WithCapacity(size int64) Option {
+	return wrap(v1.WithCapacity(size))
+}
+
+// WithClassLimits returns an Option that bounds the number of tasks of each class running at once, see TaskClass.
+func WithClassLimits(limits map[string]int) Option {
+	return wrap(v1.WithClassLimits(limits))
+}
+
+// WithTenantQuotas returns an Option that serves tenants in turn, each with its own quotas, see TaskTenant.
+func WithTenantQuotas(maxRunning, maxQueued int) Option {
+	return wrap(v1.WithTenantQuotas(maxRunning, maxQueued))
+}
+
+// WithCircuitBreaker returns an Option that fails tasks fast with ErrCircuitOpen for cooldown
+// once threshold tasks in a row have failed.
+func WithCircuitBreaker(threshold int, cooldown time.Duration) Option {
+	return wrap(v1.WithCircuitBreaker(threshold, cooldown))
+}
+
+// WithSaturationLimit returns an Option that makes Healthy report ErrSaturated once the queue has stayed
+// at least threshold full for longer than window.
+func WithSaturationLimit(threshold float64, window time.Duration) Option {
+	return wrap(v1.WithSaturationLimit(threshold, window))
+}
+
+// WithStallDetector returns an Option that calls report once tasks are queued but none has finished for timeout.
+func WithStallDetector(timeout time.Duration, report func(Stall)) Option {
+	return wrap(v1.WithStallDetector(timeout, report))
+}
+
+// WithPanicRecovery returns an Option that recovers panics of tasks, they fail with a *PanicError.
+// The worker that ran the task is replaced by a new one.
+func WithPanicRecovery() Option {
+	return wrap(v1.WithPanicRecovery())
+}
+
+// WithEventSink returns an Option that emits the events of the pool to sink, e.g. paused, task failed or completed.
+func WithEventSink(sink func(Event)) Option {
+	return wrap(v1.WithEventSink(sink))
+}
+
+// WithOnProgress returns an Option that calls fn with the progress of the pool every time a task is done.
+func WithOnProgress(fn func(done, total int)) Option {
+	return wrap(v1.WithOnProgress(fn))
+}
+
+// WithQueueSize returns an Option that bounds the queue to n tasks, instead of the task count the pool is created with.
+func WithQueueSize(n int) Option {
+	return wrap(v1.WithQueueSize(n))
+}
+
+// WithLIFO returns an Option that runs the most recently submitted tasks first.
+func WithLIFO() Option {
+	return wrap(v1.WithLIFO())
+}
+
+// WithAging returns an Option that runs a task queued for maxAge or longer before the others, whatever the
+// scheduling policy, so that the policy doesn't starve it.
+func WithAging(maxAge time.Duration) Option {
+	return wrap(v1.WithAging(maxAge))
+}
+
+// WithRequeue returns an Option that keeps the tasks that fail, so that Pool.RequeueFailed can submit them again.
+func WithRequeue() Option {
+	return wrap(v1.WithRequeue())
+}
+
+// WithRetryBudget returns an Option that bounds the redeliveries of the tasks submitted with SubmitAcked
+// to perSecond per second, plus ratio of the first deliveries of the same second.
+func WithRetryBudget(perSecond, ratio float64) Option {
+	return wrap(v1.WithRetryBudget(perSecond, ratio))
+}
+
+// WithBackoff returns an Option that sets the Backoff of the redeliveries of the tasks submitted with
+// SubmitAcked, TaskBackoff overrides it for a single task.
+func WithBackoff(b Backoff) Option {
+	return wrap(v1.WithBackoff(b))
+}
+
+// WithIdempotencyStore returns an Option that skips the tasks s reports done, by the key set with
+// TaskIdempotencyKey, and records the ones that succeed.
+func WithIdempotencyStore(s IdempotencyStore) Option {
+	return wrap(v1.WithIdempotencyStore(s))
+}
+
+// WithCache returns an Option that skips the tasks that succeeded with the same key, see TaskCacheKey,
+// within ttl.
+func WithCache(c Cache, ttl time.Duration) Option {
+	return wrap(v1.WithCache(c, ttl))
+}
+
+// WithLockedOSThreads returns an Option that wires each worker to an operating system thread, for tasks
+// that call into libraries demanding thread affinity.
+func WithLockedOSThreads() Option {
+	return wrap(v1.WithLockedOSThreads())
+}
+
+// WithMemoryThrottle returns an Option that stops the workers from picking new tasks once the memory used by
+// the Go runtime reaches high bytes, until it goes back below low bytes. It is sampled every interval.
+func WithMemoryThrottle(high, low uint64, interval time.Duration) Option {
+	return wrap(v1.WithMemoryThrottle(high, low, interval))
+}
+
+// WithRedelivery returns an Option that sets the policy of SubmitAcked: unacknowledged deliveries are
+// delivered again after timeout, up to maxAttempts deliveries. Zero means no timeout and no limit.
+func WithRedelivery(timeout time.Duration, maxAttempts int) Option {
+	return wrap(v1.WithRedelivery(timeout, maxAttempts))
+}
+
+// WithInlineExecution returns an Option that executes tasks on the goroutine submitting them, for tests.
+func WithInlineExecution() Option {
+	return wrap(v1.WithInlineExecution())
+}
+
+// WithClock returns an Option that sets the clock the pool measures durations and arms timers with.
+func WithClock(c Clock) Option {
+	return wrap(v1.WithClock(c))
+}
+
+// WithLazyWorkers returns an Option that starts workers as tasks are queued, instead of all of them upfront.
+func WithLazyWorkers() Option {
+	return wrap(v1.WithLazyWorkers())
+}
+
+// WithPrestart returns an Option that makes New wait until n workers are running and initialized.
+func WithPrestart(n int) Option {
+	return wrap(v1.WithPrestart(n))
+}
+
+// WithProfile returns an Option that sets the number of workers for the given workload shape.
+func WithProfile(pr Profile) Option {
+	return wrap(v1.WithProfile(pr))
+}
+
+// WithShards returns an Option that spreads submissions over n intake shards, each with its own lock.
+func WithShards(n int) Option {
+	return wrap(v1.WithShards(n))
+}
+
+// WithCoalesceWindow returns an Option that sets the window within which SubmitCoalesced merges submissions.
+func WithCoalesceWindow(window time.Duration) Option {
+	return wrap(v1.WithCoalesceWindow(window))
+}
+
+// WithRateLimit returns an Option that makes workers wait for l before running each task.
+func WithRateLimit(l Limiter) Option {
+	return wrap(v1.WithRateLimit(l))
+}
+
+// WithTracePropagation returns an Option that applies the pprof labels and trace regions
+// of the submitting goroutine to the worker running the task.
+func WithTracePropagation() Option {
+	return wrap(v1.WithTracePropagation())
+}
+
+// TaskLabel returns a TaskOption that puts the task in the sub-queue with the given label.
+func TaskLabel(label string) TaskOption {
+	return v1.TaskLabel(label)
+}
+
+// TaskName returns a TaskOption that names the task, the name is reported along with its errors, see TaskError.
+func TaskName(name string) TaskOption {
+	return v1.TaskName(name)
+}
+
+// TaskDeadline returns a TaskOption that sets the time by which the task should start, it is dropped with
+// ErrDeadlineExceeded if it is still queued by then.
+func TaskDeadline(deadline time.Time) TaskOption {
+	return v1.TaskDeadline(deadline)
+}
+
+// TaskPriority returns a TaskOption that sets the priority of the task, see HighestPriority and Future.SetPriority.
+func TaskPriority(priority int) TaskOption {
+	return v1.TaskPriority(priority)
+}
+
+// TaskBackoff returns a TaskOption that sets the Backoff of the redeliveries of the task, see WithBackoff.
+func TaskBackoff(b Backoff) TaskOption {
+	return v1.TaskBackoff(b)
+}
+
+// TaskIdempotencyKey returns a TaskOption that sets the key identifying the work done by the task, see WithIdempotencyStore.
+func TaskIdempotencyKey(key string) TaskOption {
+	return v1.TaskIdempotencyKey(key)
+}
+
+// TaskCacheKey returns a TaskOption that sets the key the result of the task is cached under, see WithCache.
+func TaskCacheKey(key string) TaskOption {
+	return v1.TaskCacheKey(key)
+}
+
+// TaskClass returns a TaskOption that tags the task with a class, see WithClassLimits.
+func TaskClass(class string) TaskOption {
+	return v1.TaskClass(class)
+}
+
+// TaskAffinity returns a TaskOption that sets the affinity key of the task, tasks with the same key prefer the
+// same worker.
+func TaskAffinity(key string) TaskOption {
+	return v1.TaskAffinity(key)
+}
+
+// TaskWeight returns a TaskOption that sets the share of the capacity the task holds while running.
+func TaskWeight(weight int64) TaskOption {
+	return v1.TaskWeight(weight)
+}
+
+// TaskTenant returns a TaskOption that sets the tenant the task is submitted for.
+func TaskTenant(tenant string) TaskOption {
+	return v1.TaskTenant(tenant)
+}
+
+// TaskDuration returns a TaskOption that sets the expected run time of the task.
+func TaskDuration(d time.Duration) TaskOption {
+	return v1.TaskDuration(d)
+}
+
+// After returns a TaskOption that holds the task back until the tasks of the given Futures have succeeded.
+// If any of them fails, the task is not executed and its Future reports ErrDependencyFailed.
+func After(fs ...*Future) TaskOption {
+	return v1.After(fs...)
+}
+
+func wrap(opt v1.Option) Option {
+	return func(o *config) {
+		o.opts = append(o.opts, opt)
+	}
+}
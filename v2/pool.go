@@ -0,0 +1,380 @@
+// Package gowp is the stable API of gowp, a pool of workers with limited concurrency.
+//
+// Compared to v1, tasks receive a context, the life cycle of a pool has explicit states,
+// closing a pool is separate from waiting for it and typed results are first class.
+// It is built on top of v1, so both can be used side by side while migrating, see Adapt.
+//
+// Example:
+//	wp, _ := gowp.New(10, gowp.WithWorkers(4), gowp.WithExitOnError())
+//
+//	for _, u := range urls {
+//		u := u
+//		_, _ = wp.Submit(func(ctx context.Context) error {
+//			return fetch(ctx, u)
+//		})
+//	}
+//
+//	wp.Close() // no more tasks, the pool drains in the background.
+//
+//	if err := wp.Wait(); err != nil {
+//		// handle error
+//	}
+package gowp // import "github.com/akshaybharambe14/gowp/v2"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	v1 "github.com/akshaybharambe14/gowp"
+)
+
+// states of a pool, in the order a pool goes through them.
+const (
+	// StateRunning is the state of a new pool, it accepts tasks.
+	StateRunning State = iota
+	// StateClosed is the state of a pool that doesn't accept tasks anymore, queued ones are still executed.
+	StateClosed
+	// StateDone is the state of a pool that has completed, all the tasks have finished.
+	StateDone
+)
+
+type (
+	// Task is a unit of work. ctx is cancelled when the context of the pool is, see WithContext,
+	// when the pool stops early, e.g. see WithExitOnError and CloseWithError, or once the pool is done.
+	Task func(ctx context.Context) error
+
+	// AckTask is a Task that acknowledges its delivery explicitly, see SubmitAcked.
+	AckTask func(ctx context.Context, d *Delivery)
+
+	// State is a stage in the life cycle of a pool.
+	State uint32
+
+	// Pool is a pool of workers that limits concurrency as per the configured worker count.
+	//
+	// Zero value is not usable. Use New() to create a new Pool.
+	Pool struct {
+		state uint32 // one of the pool states. Should be manipulated by sync/atomic.
+
+		p      *v1.Pool
+		ctx    context.Context // passed to tasks, the context of the v1 pool.
+		cancel context.CancelFunc
+	}
+)
+
+// New creates a pool that can hold up to numTasks queued tasks.
+// It runs as many workers as CPUs, unless WithWorkers is used.
+func New(numTasks int, opts ...Option) (*Pool, error) {
+	return newPool(opts, func(opts ...v1.Option) (*v1.Pool, error) { return v1.New(numTasks, opts...) })
+}
+
+// NewLongLived creates a pool for an unknown number of tasks, e.g. for a server, see WithQueueSize.
+func NewLongLived(opts ...Option) (*Pool, error) {
+	return newPool(opts, v1.NewLongLived)
+}
+
+// newPool creates a pool on top of the v1 pool created by create.
+func newPool(opts []Option, create func(opts ...v1.Option) (*v1.Pool, error)) (*Pool, error) {
+	cfg := config{ctx: context.Background()}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	if cfg.ctx == nil {
+		// let v1 report it along with the other invalid settings, if any.
+		_, err := create(append(cfg.opts, v1.WithContext(nil))...)
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(cfg.ctx)
+
+	p, err := create(append(cfg.opts, v1.WithContext(ctx))...)
+	if err != nil {
+		cancel()
+		return nil, err // already decorated by v1.
+	}
+
+	return &Pool{p: p, ctx: p.Context(), cancel: cancel}, nil
+}
+
+// Submit queues t and returns a Future to follow it. It fails with ErrPoolClosed once the pool is closed
+// and with ErrNoBuffer if the queue is full.
+func (p *Pool) Submit(t Task, opts ...TaskOption) (*Future, error) {
+	if t == nil {
+		return nil, fmt.Errorf("gowp.Pool.Submit(): %w", ErrNilTask)
+	}
+
+	f, err := p.p.SubmitFuture(func() error { return t(p.ctx) }, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("gowp.Pool.Submit(): %w", err)
+	}
+
+	return f, nil
+}
+
+// SubmitContext is Submit on behalf of a caller whose context is ctx. t receives a context that carries the
+// values of ctx, e.g. trace IDs, and that is cancelled along with the pool, not along with ctx.
+func (p *Pool) SubmitContext(ctx context.Context, t Task, opts ...TaskOption) (*Future, error) {
+	if t == nil {
+		return nil, fmt.Errorf("gowp.Pool.SubmitContext(): %w", ErrNilTask)
+	}
+
+	f, err := p.p.SubmitContext(ctx, t, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("gowp.Pool.SubmitContext(): %w", err)
+	}
+
+	return f, nil
+}
+
+// SubmitAfter queues t once d has elapsed. Cancelling the Future before prevents the submission.
+// Wait waits for pending submissions.
+func (p *Pool) SubmitAfter(d time.Duration, t Task, opts ...TaskOption) (*Future, error) {
+	if t == nil {
+		return nil, fmt.Errorf("gowp.Pool.SubmitAfter(): %w", ErrNilTask)
+	}
+
+	f, err := p.p.SubmitAfter(d, func() error { return t(p.ctx) }, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("gowp.Pool.SubmitAfter(): %w", err)
+	}
+
+	return f, nil
+}
+
+// SubmitShared queues t under key, unless a task with the same key is still in flight. In that case
+// the Future of that task is returned, so that concurrent callers share one execution and its error.
+func (p *Pool) SubmitShared(key string, t Task, opts ...TaskOption) (*Future, error) {
+	if t == nil {
+		return nil, fmt.Errorf("gowp.Pool.SubmitShared(): %w", ErrNilTask)
+	}
+
+	f, err := p.p.SubmitShared(key, func() error { return t(p.ctx) }, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("gowp.Pool.SubmitShared(): %w", err)
+	}
+
+	return f, nil
+}
+
+// SubmitCoalesced queues t under key once the window set by WithCoalesceWindow has elapsed.
+// Submissions of the key within the window replace t and share its Future, only the latest one is executed.
+func (p *Pool) SubmitCoalesced(key string, t Task, opts ...TaskOption) (*Future, error) {
+	if t == nil {
+		return nil, fmt.Errorf("gowp.Pool.SubmitCoalesced(): %w", ErrNilTask)
+	}
+
+	f, err := p.p.SubmitCoalesced(key, func() error { return t(p.ctx) }, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("gowp.Pool.SubmitCoalesced(): %w", err)
+	}
+
+	return f, nil
+}
+
+// SubmitHedged queues t and, if it hasn't completed after delay, a duplicate of it. The Future reports
+// the first attempt to complete, the other one is retracted or its context cancelled. t should be idempotent.
+func (p *Pool) SubmitHedged(t Task, delay time.Duration, opts ...TaskOption) (*Future, error) {
+	if t == nil {
+		return nil, fmt.Errorf("gowp.Pool.SubmitHedged(): %w", ErrNilTask)
+	}
+
+	f, err := p.p.SubmitHedged(v1.TaskCtx(t), delay, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("gowp.Pool.SubmitHedged(): %w", err)
+	}
+
+	return f, nil
+}
+
+// SubmitAcked queues t for at-least-once processing: t has to call Delivery.Ack once done, otherwise it is
+// delivered again as per WithRedelivery. The Future reports nil once acknowledged, or ErrNotAcked.
+func (p *Pool) SubmitAcked(t AckTask, opts ...TaskOption) (*Future, error) {
+	if t == nil {
+		return nil, fmt.Errorf("gowp.Pool.SubmitAcked(): %w", ErrNilTask)
+	}
+
+	f, err := p.p.SubmitAcked(func(d *Delivery) { t(p.ctx, d) }, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("gowp.Pool.SubmitAcked(): %w", err)
+	}
+
+	return f, nil
+}
+
+// SubmitWithDeadline queues t like Submit, with the given deadline, see TaskDeadline.
+func (p *Pool) SubmitWithDeadline(deadline time.Time, t Task, opts ...TaskOption) (*Future, error) {
+	if t == nil {
+		return nil, fmt.Errorf("gowp.Pool.SubmitWithDeadline(): %w", ErrNilTask)
+	}
+
+	f, err := p.p.SubmitWithDeadline(deadline, func() error { return t(p.ctx) }, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("gowp.Pool.SubmitWithDeadline(): %w", err)
+	}
+
+	return f, nil
+}
+
+// SubmitAt queues t at the given time as per the clock of the pool, see SubmitAfter and WithClock.
+func (p *Pool) SubmitAt(at time.Time, t Task, opts ...TaskOption) (*Future, error) {
+	return p.SubmitAfter(at.Sub(p.p.Clock().Now()), t, opts...)
+}
+
+// Close stops the pool from accepting tasks and returns immediately, queued tasks are still executed.
+// Calling Close more than once has no effect.
+func (p *Pool) Close() {
+	if atomic.CompareAndSwapUint32(&p.state, uint32(StateRunning), uint32(StateClosed)) {
+		p.p.Close()
+	}
+}
+
+// CloseWithError closes the pool and stops it with err: queued tasks are discarded and Wait reports err,
+// unless a task failed before. A nil err is replaced by ErrPoolStopped.
+func (p *Pool) CloseWithError(err error) {
+	atomic.CompareAndSwapUint32(&p.state, uint32(StateRunning), uint32(StateClosed))
+	p.p.CloseWithError(err)
+}
+
+// Wait closes the pool, waits for all the tasks to finish and returns the first error, if any.
+// It can be called multiple times, it returns the same error.
+func (p *Pool) Wait() error {
+	p.Close()
+
+	err := p.p.Wait()
+	p.cancel()
+	atomic.StoreUint32(&p.state, uint32(StateDone))
+
+	return err
+}
+
+// WaitContext is Wait bounded by ctx. If ctx is done first, it returns an error matching ErrWaitAbandoned and
+// the error of ctx while the tasks keep running in the background, the pool stays closed.
+func (p *Pool) WaitContext(ctx context.Context) error {
+	p.Close()
+
+	err := p.p.WaitContext(ctx)
+	if errors.Is(err, ErrWaitAbandoned) {
+		return err
+	}
+
+	p.cancel()
+	atomic.StoreUint32(&p.state, uint32(StateDone))
+
+	return err
+}
+
+// Done returns a channel that is closed once the pool has completed, closing the pool is then enough for it
+// to complete. Wait returns right away afterwards, see the v1 Pool.Done.
+func (p *Pool) Done() <-chan struct{} {
+	return p.p.Done()
+}
+
+// TryWait reports whether the pool has completed without blocking, along with the error Wait returns, if so.
+func (p *Pool) TryWait() (done bool, err error) {
+	return p.p.TryWait()
+}
+
+// Context returns a context cancelled once the pool stops early or completes, context.Cause tells why,
+// see the v1 Pool.Context.
+func (p *Pool) Context() context.Context {
+	return p.p.Context()
+}
+
+// State returns the current state of the pool. It is StateDone as soon as the pool has completed,
+// in line with Done and TryWait, whether Wait was called or not.
+func (p *Pool) State() State {
+	if done, _ := p.p.TryWait(); done {
+		return StateDone
+	}
+
+	return State(atomic.LoadUint32(&p.state))
+}
+
+// AfterFunc arranges to call fn in its own goroutine once the pool is done, see Report.
+func (p *Pool) AfterFunc(fn func(Report)) (stop func() bool) {
+	return p.p.AfterFunc(fn)
+}
+
+// Pending returns the number of tasks waiting for a worker.
+func (p *Pool) Pending() int {
+	return p.p.Pending()
+}
+
+// Running returns the number of tasks being executed.
+func (p *Pool) Running() int {
+	return p.p.Running()
+}
+
+// Workers returns the number of workers running, including temporary ones.
+func (p *Pool) Workers() int {
+	return p.p.Workers()
+}
+
+// Cap returns the number of tasks the pool can hold waiting for a worker.
+func (p *Pool) Cap() int {
+	return p.p.Cap()
+}
+
+// Purge discards the tasks waiting for a worker and returns how many there were, running tasks finish normally.
+func (p *Pool) Purge() int {
+	return p.p.Purge()
+}
+
+// RequeueFailed submits again the tasks that failed since the last call, see WithRequeue, and returns how
+// many were requeued. It stops at the first task that can't be submitted and returns the error.
+func (p *Pool) RequeueFailed() (int, error) {
+	return p.p.RequeueFailed()
+}
+
+// Snapshot returns the tasks being executed, in the order of submission.
+func (p *Pool) Snapshot() []TaskInfo {
+	return p.p.Snapshot()
+}
+
+// Progress returns the number of tasks that are done out of the task count the pool was created with,
+// or out of the number of tasks submitted if more were.
+func (p *Pool) Progress() (done, total int) {
+	return p.p.Progress()
+}
+
+// Stats returns a snapshot of the workers, the queue and the task counters of the pool.
+func (p *Pool) Stats() Stats {
+	return p.p.Stats()
+}
+
+// Healthy reports whether the pool is able to process its tasks, e.g. for liveness or readiness probes.
+// It returns ErrPoolStopped, ErrWorkersLost or ErrSaturated otherwise.
+func (p *Pool) Healthy() error {
+	return p.p.Healthy()
+}
+
+// Pause stops the workers from picking new tasks until Resume is called. Running tasks are not interrupted.
+func (p *Pool) Pause() {
+	p.p.Pause()
+}
+
+// Resume lets the workers pick tasks again after Pause.
+func (p *Pool) Resume() {
+	p.p.Resume()
+}
+
+// Resize changes the number of workers of the pool to n, surplus workers exit once done with their current task.
+func (p *Pool) Resize(n int) error {
+	return p.p.Resize(n) // already decorated by v1.
+}
+
+func (s State) String() string {
+	switch s {
+	case StateRunning:
+		return "running"
+	case StateClosed:
+		return "closed"
+	case StateDone:
+		return "done"
+	default:
+		return fmt.Sprintf("State(%d)", uint32(s))
+	}
+}
@@ -0,0 +1,196 @@
+package gowp
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+var testErr = errors.New("test error")
+
+func TestPool_lifecycle(t *testing.T) {
+	p, err := New(4, WithWorkers(1))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	if got := p.State(); got != StateRunning {
+		t.Errorf("Pool.State() = %v, want %v", got, StateRunning)
+	}
+
+	release := make(chan struct{})
+	_, _ = p.Submit(func(context.Context) error { <-release; return nil })
+	f, _ := p.Submit(Adapt(func() error { return testErr }))
+
+	p.Close()
+
+	if got := p.State(); got != StateClosed {
+		t.Errorf("Pool.State() = %v, want %v", got, StateClosed)
+	}
+
+	if _, err := p.Submit(Adapt(func() error { return nil })); !errors.Is(err, ErrPoolClosed) {
+		t.Errorf("Pool.Submit() after Close = %v, want %v", err, ErrPoolClosed)
+	}
+
+	close(release)
+
+	if err := f.Err(); !errors.Is(err, testErr) {
+		t.Errorf("Future.Err() = %v, want %v", err, testErr)
+	}
+
+	if err := p.Wait(); !errors.Is(err, testErr) {
+		t.Errorf("Pool.Wait() = %v, want %v", err, testErr)
+	}
+
+	if got := p.State(); got != StateDone {
+		t.Errorf("Pool.State() = %v, want %v", got, StateDone)
+	}
+}
+
+func TestNewLongLived(t *testing.T) {
+	p, err := NewLongLived(WithWorkers(1), WithQueueSize(2))
+	if err != nil {
+		t.Fatalf("NewLongLived() error = %v", err)
+	}
+
+	if got := p.Cap(); got != 2 {
+		t.Errorf("Pool.Cap() = %d, want 2", got)
+	}
+
+	p.Close()
+	if err := p.Wait(); err != nil {
+		t.Errorf("Pool.Wait() = %v", err)
+	}
+}
+
+func TestPool_context(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	p, _ := New(2, WithWorkers(1), WithContext(ctx))
+
+	started := make(chan struct{})
+	f, _ := p.Submit(func(ctx context.Context) error {
+		close(started)
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	<-started
+	cancel()
+
+	if err := f.Err(); !errors.Is(err, context.Canceled) {
+		t.Errorf("Future.Err() = %v, want %v", err, context.Canceled)
+	}
+
+	if err := p.Wait(); !errors.Is(err, context.Canceled) {
+		t.Errorf("Pool.Wait() = %v, want %v", err, context.Canceled)
+	}
+
+	if _, err := New(1, WithContext(nil)); !errors.Is(err, ErrNilContext) {
+		t.Errorf("New() error = %v, want %v", err, ErrNilContext)
+	}
+
+	if _, err := New(1, WithContext(nil), WithClock(nil)); !errors.Is(err, ErrNilContext) || !errors.Is(err, ErrNilClock) {
+		t.Errorf("New() error = %v, want both %v and %v", err, ErrNilContext, ErrNilClock)
+	}
+}
+
+func TestPool_WaitContext(t *testing.T) {
+	p, _ := New(2, WithWorkers(1))
+
+	release := make(chan struct{})
+	_, _ = p.Submit(func(context.Context) error { <-release; return nil })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := p.WaitContext(ctx); !errors.Is(err, ErrWaitAbandoned) || !errors.Is(err, context.Canceled) {
+		t.Errorf("Pool.WaitContext() = %v, want %v and %v", err, ErrWaitAbandoned, context.Canceled)
+	}
+
+	if got := p.State(); got != StateClosed {
+		t.Errorf("Pool.State() = %v, want %v", got, StateClosed)
+	}
+
+	close(release)
+
+	if err := p.WaitContext(context.Background()); err != nil {
+		t.Errorf("Pool.WaitContext() = %v, want nil", err)
+	}
+
+	if got := p.State(); got != StateDone {
+		t.Errorf("Pool.State() = %v, want %v", got, StateDone)
+	}
+}
+
+func TestPool_Done(t *testing.T) {
+	p, _ := New(2, WithWorkers(1))
+	_, _ = p.Submit(Adapt(func() error { return testErr }))
+
+	done := p.Done()
+	p.Close()
+	<-done
+
+	if err := p.Wait(); !errors.Is(err, testErr) {
+		t.Errorf("Pool.Wait() = %v, want %v", err, testErr)
+	}
+
+	if got := p.State(); got != StateDone {
+		t.Errorf("Pool.State() = %v, want %v", got, StateDone)
+	}
+}
+
+func TestSubmitTyped(t *testing.T) {
+	p, _ := New(2, WithWorkers(2))
+
+	f, err := SubmitTyped(p, func(context.Context) (int, error) { return 42, nil })
+	if err != nil {
+		t.Fatalf("SubmitTyped() error = %v", err)
+	}
+
+	if v, err := f.Result(); v != 42 || err != nil {
+		t.Errorf("TypedFuture.Result() = %v, %v, want 42, nil", v, err)
+	}
+
+	if _, err := SubmitTyped[int](p, nil); !errors.Is(err, ErrNilTask) {
+		t.Errorf("SubmitTyped() error = %v, want %v", err, ErrNilTask)
+	}
+
+	if err := p.Wait(); err != nil {
+		t.Errorf("Pool.Wait() = %v, want nil", err)
+	}
+}
+
+func TestPool_stopCancelsTasks(t *testing.T) {
+	p, _ := New(2, WithWorkers(2), WithExitOnError())
+
+	started := make(chan struct{})
+	f, _ := p.Submit(func(ctx context.Context) error {
+		close(started)
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	<-started
+	_, _ = p.Submit(Adapt(func() error { return testErr }))
+
+	if err := f.Err(); !errors.Is(err, context.Canceled) {
+		t.Errorf("Future.Err() = %v, want %v", err, context.Canceled)
+	}
+
+	if err := p.Wait(); !errors.Is(err, testErr) {
+		t.Errorf("Pool.Wait() = %v, want %v", err, testErr)
+	}
+}
+
+func TestPool_StateFollowsDone(t *testing.T) {
+	p, _ := New(1, WithWorkers(1))
+	_, _ = p.Submit(Adapt(func() error { return nil }))
+
+	done := p.Done()
+	p.Close()
+	<-done
+
+	if got := p.State(); got != StateDone {
+		t.Errorf("Pool.State() = %v, want %v", got, StateDone)
+	}
+}
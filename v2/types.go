@@ -0,0 +1,115 @@
+package gowp
+
+import (
+	"context"
+
+	v1 "github.com/akshaybharambe14/gowp"
+)
+
+// types shared with v1, so that hooks and integrations work with both.
+type (
+	Hooks            = v1.Hooks
+	TaskInfo         = v1.TaskInfo
+	HookError        = v1.HookError
+	TaskError        = v1.TaskError
+	Stall            = v1.Stall
+	PanicError       = v1.PanicError
+	Event            = v1.Event
+	EventKind        = v1.EventKind
+	Report           = v1.Report
+	Stats            = v1.Stats
+	Histogram        = v1.Histogram
+	Timing           = v1.Timing
+	Delivery         = v1.Delivery
+	Severity         = v1.Severity
+	SchedulingPolicy = v1.SchedulingPolicy
+	Profile          = v1.Profile
+	Error            = v1.Error
+	Limiter          = v1.Limiter
+	Backoff          = v1.Backoff
+	IdempotencyStore = v1.IdempotencyStore
+	Cache            = v1.Cache
+	Clock            = v1.Clock
+	Timer            = v1.Timer
+	Ticker           = v1.Ticker
+)
+
+// severities, see WithErrorClassifier.
+const (
+	SeverityError  = v1.SeverityError
+	SeverityIgnore = v1.SeverityIgnore
+	SeverityFatal  = v1.SeverityFatal
+)
+
+// scheduling policies, see WithSchedulingPolicy.
+const (
+	FIFO             = v1.FIFO
+	LongestFirst     = v1.LongestFirst
+	ShortestFirst    = v1.ShortestFirst
+	LIFO             = v1.LIFO
+	EarliestDeadline = v1.EarliestDeadline
+	HighestPriority  = v1.HighestPriority
+)
+
+// workload profiles, see WithProfile.
+const (
+	CPUBound = v1.CPUBound
+	IOBound  = v1.IOBound
+)
+
+// kinds of events, see WithEventSink.
+const (
+	EventCreated      = v1.EventCreated
+	EventPaused       = v1.EventPaused
+	EventResumed      = v1.EventResumed
+	EventResized      = v1.EventResized
+	EventTaskRejected = v1.EventTaskRejected
+	EventTaskFailed   = v1.EventTaskFailed
+	EventWorkerExited = v1.EventWorkerExited
+	EventClosed       = v1.EventClosed
+	EventStopped      = v1.EventStopped
+	EventCompleted    = v1.EventCompleted
+	EventTaskExpired  = v1.EventTaskExpired
+)
+
+// errors, the same values as in v1, so errors.Is works across versions.
+const (
+	ErrPoolClosed    = v1.ErrPoolClosed
+	ErrNoBuffer      = v1.ErrNoBuffer
+	ErrNilTask       = v1.ErrNilTask
+	ErrNilContext    = v1.ErrNilContext
+	ErrNilClock      = v1.ErrNilClock
+	ErrTaskCanceled  = v1.ErrTaskCanceled
+	ErrTaskDiscarded = v1.ErrTaskDiscarded
+	ErrUnknownLabel  = v1.ErrUnknownLabel
+	ErrInvalidWeight = v1.ErrInvalidWeight
+	ErrTenantQuota   = v1.ErrTenantQuota
+
+	ErrDependencyFailed = v1.ErrDependencyFailed
+	ErrCircuitOpen      = v1.ErrCircuitOpen
+	ErrNotAcked         = v1.ErrNotAcked
+	ErrDeadlineExceeded = v1.ErrDeadlineExceeded
+	ErrNotRequeueable   = v1.ErrNotRequeueable
+
+	ErrRetryBudgetExhausted = v1.ErrRetryBudgetExhausted
+
+	ErrPoolStopped = v1.ErrPoolStopped
+	ErrSaturated   = v1.ErrSaturated
+	ErrWorkersLost = v1.ErrWorkersLost
+
+	ErrDrainTimeout = v1.ErrDrainTimeout
+
+	ErrWaitAbandoned = v1.ErrWaitAbandoned
+)
+
+// Adapt turns a v1 task into a Task that ignores its context. It eases the migration of v1 call sites:
+//
+//	wp.Submit(func() error { ... })             // v1
+//	wp.Submit(gowp.Adapt(func() error { ... })) // v2
+func Adapt(t v1.Task) Task {
+	if t == nil {
+		return nil
+	}
+
+	return func(context.Context) error { return t() }
+}
@@ -0,0 +1,387 @@
+// Package wal makes gowp tasks durable: they are recorded in a write-ahead log before being submitted
+// to the pool and replayed when the log is opened again, so that they survive process crashes.
+//
+// Closures can't be written to disk, so durable tasks are made of a kind and a payload. The kind names
+// a Handler registered when the log is opened, the payload is whatever the handler needs, e.g. JSON.
+// A task is marked done in the log once its handler has returned, whether it failed or not, unless WithAcks is used.
+// Tasks that didn't run to completion, because the process crashed or the pool stopped early,
+// are submitted again by Open. Delivery is therefore at least once, handlers should be idempotent.
+//
+// Example:
+//	wp, _ := gowp.New(100)
+//	q, err := wal.Open("jobs.wal", wp, wal.Handlers{
+//		"email": func(payload []byte) error { return sendEmail(payload) },
+//	})
+//	if err != nil {
+//		// handle the error.
+//	}
+//	defer q.Close()
+//
+//	_, _ = q.Submit("email", []byte(`{"to":"someone@example.com"}`))
+package wal // import "github.com/akshaybharambe14/gowp/wal"
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"sort"
+	"sync"
+
+	"github.com/akshaybharambe14/gowp"
+)
+
+// errors reported by the log.
+const (
+	ErrUnknownKind = gowp.Error("no handler registered for the task kind")
+	ErrClosed      = gowp.Error("log is closed")
+)
+
+// types of records.
+const (
+	recSubmit byte = iota + 1 // a task was submitted.
+	recDone                   // a task has been executed, or was never submitted.
+)
+
+// maxRecord bounds the size of a record, a larger length means the log is corrupted.
+const maxRecord = 64 << 20
+
+type (
+	// Handler executes a durable task of a given kind.
+	Handler func(payload []byte) error
+
+	// Handlers maps task kinds to their handler.
+	Handlers map[string]Handler
+
+	// Option configures a Queue.
+	Option func(q *Queue)
+
+	// Queue submits durable tasks to a pool. It is safe for concurrent use.
+	//
+	// Zero value is not usable. Use Open() to create a Queue.
+	Queue struct {
+		p        *gowp.Pool
+		handlers Handlers
+		acks     bool // see WithAcks.
+
+		mu     sync.Mutex
+		f      *os.File // guarded by mu, nil once closed.
+		lastID uint64   // guarded by mu.
+	}
+
+	record struct {
+		typ     byte
+		id      uint64
+		kind    string
+		payload []byte
+	}
+)
+
+// WithAcks returns an Option that submits the tasks with gowp.Pool.SubmitAcked: a handler error doesn't
+// mark the task done, the task is delivered again as per gowp.WithRedelivery. A task whose deliveries are
+// exhausted stays in the log and is replayed by the next Open.
+func WithAcks() Option {
+	return func(q *Queue) {
+		q.acks = true
+	}
+}
+
+// Open opens the log at path, creating it if needed, and submits the tasks it holds that are not done to p.
+// The log is compacted along the way, only the pending tasks are kept. A record torn by a crash
+// at the end of the log is dropped. Open fails with ErrUnknownKind if a pending task has no handler.
+func Open(path string, p *gowp.Pool, handlers Handlers, opts ...Option) (*Queue, error) {
+	pending, lastID, err := load(path)
+	if err != nil {
+		return nil, fmt.Errorf("wal.Open(): %w", err)
+	}
+
+	for _, r := range pending {
+		if _, ok := handlers[r.kind]; !ok {
+			return nil, fmt.Errorf("wal.Open(): %w: %q", ErrUnknownKind, r.kind)
+		}
+	}
+
+	f, err := rewrite(path, pending)
+	if err != nil {
+		return nil, fmt.Errorf("wal.Open(): %w", err)
+	}
+
+	q := &Queue{p: p, handlers: handlers, f: f, lastID: lastID}
+	for _, opt := range opts {
+		opt(q)
+	}
+
+	var replayed []*gowp.Future
+	for _, r := range pending {
+		fut, err := q.submit(r, true)
+		for errors.Is(err, gowp.ErrNoBuffer) && len(replayed) > 0 {
+			_ = replayed[0].Err() // acknowledged tasks don't wait for room, wait for the oldest to settle.
+			replayed = replayed[1:]
+			fut, err = q.submit(r, true)
+		}
+
+		if err != nil {
+			_ = f.Close()
+			return nil, fmt.Errorf("wal.Open(): %w", err)
+		}
+
+		replayed = append(replayed, fut)
+	}
+
+	return q, nil
+}
+
+// Submit records a task of the given kind in the log, then submits it to the pool.
+// It returns once the record is on disk. If the pool refuses the task, it is marked done
+// and the error of the pool is returned.
+//
+// Cancelling the returned Future doesn't remove the task from the log, it is replayed by the next Open.
+func (q *Queue) Submit(kind string, payload []byte) (*gowp.Future, error) {
+	if _, ok := q.handlers[kind]; !ok {
+		return nil, fmt.Errorf("wal.Queue.Submit(): %w: %q", ErrUnknownKind, kind)
+	}
+
+	q.mu.Lock()
+	if q.f == nil {
+		q.mu.Unlock()
+		return nil, fmt.Errorf("wal.Queue.Submit(): %w", ErrClosed)
+	}
+
+	q.lastID++
+	r := record{typ: recSubmit, id: q.lastID, kind: kind, payload: payload}
+
+	err := q.append(r)
+	if err == nil {
+		err = q.f.Sync()
+	}
+	q.mu.Unlock()
+
+	if err != nil {
+		return nil, fmt.Errorf("wal.Queue.Submit(): %w", err)
+	}
+
+	f, err := q.submit(r, false)
+	if err != nil {
+		q.done(r.id)
+		return nil, fmt.Errorf("wal.Queue.Submit(): %w", err)
+	}
+
+	return f, nil
+}
+
+// Close closes the log. Tasks that complete afterwards are not marked done, they are replayed by the next Open.
+// Close the log once the pool has completed to avoid it.
+func (q *Queue) Close() error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.f == nil {
+		return nil
+	}
+
+	err := q.f.Close()
+	q.f = nil
+
+	if err != nil {
+		return fmt.Errorf("wal.Queue.Close(): %w", err)
+	}
+
+	return nil
+}
+
+// submit submits the task of r to the pool. replay is true if r is replayed by Open.
+func (q *Queue) submit(r record, replay bool) (*gowp.Future, error) {
+	switch {
+	case q.acks:
+		return q.p.SubmitAcked(q.ackTask(r))
+	case replay:
+		return q.p.SubmitAfter(0, q.task(r)) // the log may hold more tasks than the queue, SubmitAfter waits for room.
+	default:
+		return q.p.SubmitFuture(q.task(r))
+	}
+}
+
+// ackTask returns the pool task executing r, which is marked done once acknowledged.
+func (q *Queue) ackTask(r record) gowp.AckTask {
+	h := q.handlers[r.kind]
+
+	return func(d *gowp.Delivery) {
+		if err := h(r.payload); err != nil {
+			d.Nack()
+			return
+		}
+
+		if d.Ack() {
+			q.done(r.id)
+		}
+	}
+}
+
+// task returns the pool task executing r.
+func (q *Queue) task(r record) gowp.Task {
+	h := q.handlers[r.kind]
+
+	return func() error {
+		err := h(r.payload)
+		q.done(r.id)
+
+		return err
+	}
+}
+
+// done marks a task as done. The record isn't synced, losing it only causes the task to be replayed.
+func (q *Queue) done(id uint64) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.f != nil {
+		_ = q.append(record{typ: recDone, id: id})
+	}
+}
+
+// append writes r to the log. q.mu must be held.
+func (q *Queue) append(r record) error {
+	_, err := q.f.Write(encode(r))
+	return err
+}
+
+// encode frames r as its length, its checksum and its body.
+func encode(r record) []byte {
+	body := make([]byte, 0, 1+8+4+len(r.kind)+len(r.payload))
+	body = append(body, r.typ)
+	body = binary.BigEndian.AppendUint64(body, r.id)
+
+	if r.typ == recSubmit {
+		body = binary.BigEndian.AppendUint32(body, uint32(len(r.kind)))
+		body = append(body, r.kind...)
+		body = append(body, r.payload...)
+	}
+
+	buf := make([]byte, 8, 8+len(body))
+	binary.BigEndian.PutUint32(buf, uint32(len(body)))
+	binary.BigEndian.PutUint32(buf[4:], crc32.ChecksumIEEE(body))
+
+	return append(buf, body...)
+}
+
+// decode reads the next record. It returns io.EOF at the end of the log, including at a torn record.
+func decode(r *bufio.Reader) (record, error) {
+	var head [8]byte
+	if _, err := io.ReadFull(r, head[:]); err != nil {
+		return record{}, io.EOF
+	}
+
+	size := binary.BigEndian.Uint32(head[:])
+	if size < 9 || size > maxRecord {
+		return record{}, io.EOF
+	}
+
+	body := make([]byte, size)
+	if _, err := io.ReadFull(r, body); err != nil || crc32.ChecksumIEEE(body) != binary.BigEndian.Uint32(head[4:]) {
+		return record{}, io.EOF
+	}
+
+	rec := record{typ: body[0], id: binary.BigEndian.Uint64(body[1:9])}
+	if rec.typ != recSubmit {
+		return rec, nil
+	}
+
+	if len(body) < 13 {
+		return record{}, io.EOF
+	}
+
+	n := binary.BigEndian.Uint32(body[9:13])
+	if uint64(n) > uint64(len(body)-13) {
+		return record{}, io.EOF
+	}
+
+	rec.kind = string(body[13 : 13+n])
+	rec.payload = body[13+n:]
+
+	return rec, nil
+}
+
+// load returns the tasks of the log at path that are not done, in submission order, and the last id in use.
+func load(path string) ([]record, uint64, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, 0, nil
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+	defer f.Close()
+
+	var lastID uint64
+	pending := make(map[uint64]record)
+
+	br := bufio.NewReader(f)
+	for {
+		r, err := decode(br)
+		if err != nil {
+			break
+		}
+
+		if r.id > lastID {
+			lastID = r.id
+		}
+
+		switch r.typ {
+		case recSubmit:
+			pending[r.id] = r
+		case recDone:
+			delete(pending, r.id)
+		}
+	}
+
+	records := make([]record, 0, len(pending))
+	for _, r := range pending {
+		records = append(records, r)
+	}
+
+	sort.Slice(records, func(i, j int) bool { return records[i].id < records[j].id })
+
+	return records, lastID, nil
+}
+
+// rewrite replaces the log at path with the given records and returns it open for appending.
+// The new log is written aside and renamed, so that a crash leaves either the old or the new log.
+func rewrite(path string, records []record) (*os.File, error) {
+	tmp := path + ".tmp"
+
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, err
+	}
+
+	w := bufio.NewWriter(f)
+	for _, r := range records {
+		if _, err := w.Write(encode(r)); err != nil {
+			_ = f.Close()
+			return nil, err
+		}
+	}
+
+	if err := w.Flush(); err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+
+	if err := f.Sync(); err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+
+	if err := f.Close(); err != nil {
+		return nil, err
+	}
+
+	if err := os.Rename(tmp, path); err != nil {
+		return nil, err
+	}
+
+	return os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o644)
+}
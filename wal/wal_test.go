@@ -0,0 +1,184 @@
+package wal
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/akshaybharambe14/gowp"
+)
+
+const errFlaky = gowp.Error("flaky")
+
+func TestQueue(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tasks.wal")
+
+	var (
+		mu  sync.Mutex
+		ran []string
+	)
+	handlers := Handlers{
+		"echo": func(payload []byte) error {
+			mu.Lock()
+			ran = append(ran, string(payload))
+			mu.Unlock()
+			return nil
+		},
+	}
+
+	// first run: a task is executed, two are left behind by a crash.
+	ctx, cancel := context.WithCancel(context.Background())
+	p, _ := gowp.New(10, gowp.WithContext(ctx), gowp.WithNumWorkers(1))
+
+	q, err := Open(path, p, handlers)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+
+	f, _ := q.Submit("echo", []byte("a"))
+	if err := f.Err(); err != nil {
+		t.Fatalf("Future.Err() = %v", err)
+	}
+
+	p.Pause()
+	_, _ = q.Submit("echo", []byte("b"))
+	_, _ = q.Submit("echo", []byte("c"))
+
+	if _, err := q.Submit("unknown", nil); !errors.Is(err, ErrUnknownKind) {
+		t.Errorf("Queue.Submit() error = %v, want %v", err, ErrUnknownKind)
+	}
+
+	_ = q.Close()
+	cancel()
+	_ = p.Wait()
+
+	// a record torn by the crash.
+	if fd, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0); err == nil {
+		_, _ = fd.Write([]byte{0, 0, 0, 42, 1})
+		_ = fd.Close()
+	}
+
+	// second run: the pending tasks are replayed.
+	ran = nil
+	p, _ = gowp.New(1, gowp.WithNumWorkers(1)) // fewer slots than pending tasks.
+
+	q, err = Open(path, p, handlers)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+
+	if err := p.Wait(); err != nil {
+		t.Fatalf("Pool.Wait() error = %v", err)
+	}
+	_ = q.Close()
+
+	sort.Strings(ran)
+	if len(ran) != 2 || ran[0] != "b" || ran[1] != "c" {
+		t.Errorf("replayed %v, want [b c]", ran)
+	}
+
+	// third run: nothing is left.
+	ran = nil
+	p, _ = gowp.New(1)
+
+	if q, err = Open(path, p, handlers); err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+
+	_ = p.Wait()
+	_ = q.Close()
+
+	if len(ran) != 0 {
+		t.Errorf("replayed %v, want nothing", ran)
+	}
+
+	if _, err := q.Submit("echo", nil); !errors.Is(err, ErrClosed) {
+		t.Errorf("Queue.Submit() after Close = %v, want %v", err, ErrClosed)
+	}
+}
+
+func TestOpen_unknownKind(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tasks.wal")
+
+	p, _ := gowp.New(10)
+	p.Pause()
+
+	q, _ := Open(path, p, Handlers{"old": func([]byte) error { return nil }})
+	_, _ = q.Submit("old", nil)
+	_ = q.Close()
+
+	if _, err := Open(path, p, Handlers{}); !errors.Is(err, ErrUnknownKind) {
+		t.Errorf("Open() error = %v, want %v", err, ErrUnknownKind)
+	}
+}
+
+func TestEncode(t *testing.T) {
+	tests := []record{
+		{typ: recSubmit, id: 1, kind: "k", payload: []byte("payload")},
+		{typ: recSubmit, id: 2, kind: "", payload: nil},
+		{typ: recDone, id: 3},
+	}
+
+	for _, want := range tests {
+		got, err := decode(bufioReader(encode(want)))
+		if err != nil {
+			t.Fatalf("decode() error = %v", err)
+		}
+
+		if got.typ != want.typ || got.id != want.id || got.kind != want.kind || string(got.payload) != string(want.payload) {
+			t.Errorf("decode(encode(%+v)) = %+v", want, got)
+		}
+	}
+}
+
+func bufioReader(b []byte) *bufio.Reader {
+	return bufio.NewReader(bytes.NewReader(b))
+}
+
+func TestWithAcks(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tasks.wal")
+
+	var calls int32
+	handlers := Handlers{
+		"flaky": func([]byte) error {
+			if atomic.AddInt32(&calls, 1)%2 == 1 {
+				return errFlaky
+			}
+			return nil
+		},
+		"broken": func([]byte) error { return errFlaky },
+	}
+
+	p, _ := gowp.New(10, gowp.WithRedelivery(0, 2))
+	q, err := Open(path, p, handlers, WithAcks())
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+
+	flaky, _ := q.Submit("flaky", nil)
+	broken, _ := q.Submit("broken", nil)
+
+	_ = p.Wait()
+	_ = q.Close()
+
+	if err := flaky.Err(); err != nil {
+		t.Errorf("Future.Err() = %v, want nil", err)
+	}
+
+	if err := broken.Err(); !errors.Is(err, gowp.ErrNotAcked) {
+		t.Errorf("Future.Err() = %v, want %v", err, gowp.ErrNotAcked)
+	}
+
+	// the task that was never acknowledged is replayed.
+	pending, _, err := load(path)
+	if err != nil || len(pending) != 1 || pending[0].kind != "broken" {
+		t.Errorf("load() = %+v, %v, want the broken task", pending, err)
+	}
+}
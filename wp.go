@@ -2,30 +2,134 @@ package gowp
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"runtime"
 	"sync"
 	"sync/atomic"
+	"time"
 )
 
 // closed represents the closed state of the pool.
 const closed uint32 = 1
 
+// defaultQueueSize is the size of the queue of a pool created with NewLongLived, unless WithQueueSize is used.
+const defaultQueueSize = 1024
+
+// compactMin is the number of cancelled jobs that need to pile up in the queue before it is compacted.
+const compactMin = 64
+
 type (
 	// Pool represents a pool of workers that limits concurency as per the provided worker count.
 	//
 	// Zero value is not usable. Use New() to create a new Pool.
 	Pool struct {
+		counts counters // kept first to guarantee 64-bit alignment of its fields on 32-bit platforms.
+
 		wg sync.WaitGroup
 
-		err          error         // the first error that occurred in the execution.
+		err          error         // the first error that occurred in the execution. Set along with completed, guarded by mu.
 		errs         chan error    // workers report errors through this channel.
+		fatal        chan error    // workers report errors classified as SeverityFatal through this channel.
+		aborts       chan error    // stops the pool with the given error, see DrainOnSignal.
+		outcome      chan error    // the error handling goroutine reports the final error through this channel.
+		success      chan struct{} // closed on the first successful task, see WithFirstSuccess. nil otherwise.
+		successOnce  sync.Once
+		watching     sync.Once     // starts the error handling goroutine once it is needed, see watch.
 		quit         chan struct{} // quit signal to close the pool. This will be closed on error or after successful execution.
 		exitFromErrG chan struct{} // exit signal to close the error handling goroutine, in case if not closed already.
-		in           chan Task     // works as a queue of work that workers listen to.
 		closeOnce    sync.Once     // ensures that we perform exit formalities only once.
 		closed       uint32        // set to closed(1) when the pool is closed. Should be manipulated by sync/atomic.
 
+		mu        sync.Mutex
+		ready     sync.Cond       // signalled when a job is queued, the intake is closed or quit is signalled.
+		room      sync.Cond       // signalled when a job leaves the queue, the intake is closed or quit is signalled.
+		queue     queue           // pending jobs that workers pick from. Guarded by mu.
+		hooks     []Hooks         // read-only after initialization.
+		hookErrs  func(error)     // see WithHookErrorHandler. Read-only after initialization.
+		ctx       context.Context // context of the pool. Read-only after initialization.
+		clock     Clock           // see WithClock. Read-only after initialization.
+		propagate bool            // see WithTracePropagation. Read-only after initialization.
+		boosted   int             // number of temporary workers started by the booster or the burst bucket. Guarded by mu.
+		idle      int             // number of workers waiting for a job. Guarded by mu.
+		burst     *burstBucket    // see WithBurst. nil, if not set. Guarded by mu.
+		adaptive  *adaptiveLimit  // see WithAdaptiveConcurrency. nil, if not set. Guarded by mu.
+		gc        *gcLimit        // see WithGCPressureLimit. nil, if not set. Guarded by mu.
+		capacity  *capacity       // see WithCapacity. nil, if not set. Has its own lock.
+		breaker   *circuitBreaker // see WithCircuitBreaker. nil, if not set. Has its own lock.
+		retries   *retryBudget    // see WithRetryBudget. nil, if not set. Has its own lock.
+		tenants   *tenantQueue    // the queue, if WithTenantQuotas is used. nil otherwise. Guarded by mu.
+		shards    *shards         // see WithShards. nil, if not set. Has its own locks.
+		inflight  inflight        // jobs being executed, see Snapshot. Has its own lock.
+		scheduled int             // number of held jobs waiting to be queued, see SubmitAfter and After. Guarded by mu.
+		running   int             // number of jobs handed to workers and not processed yet, tracked for the adaptive and GC limits. Guarded by mu.
+		size      int             // maximum number of pending jobs.
+		intakeOff bool            // set when the pool stops accepting jobs. Guarded by mu.
+		closing   chan struct{}   // closed along with setting intakeOff.
+
+		inline   bool // see WithInlineExecution. Read-only after initialization.
+		recovers bool // see WithPanicRecovery. Read-only after initialization.
+		locked   bool // see WithLockedOSThreads. Read-only after initialization.
+		requeue  bool // see WithRequeue. Read-only after initialization.
+		lazy     bool // see WithLazyWorkers. Read-only after initialization.
+		inlining bool // set while a goroutine executes the queue of an inline pool. Guarded by mu.
+
+		workers int  // number of regular workers running. Guarded by mu.
+		target  int  // number of regular workers the pool should run, see Resize. Guarded by mu.
+		paused  bool // see Pause. Guarded by mu.
+
+		throttled bool // set while memory is under pressure, see WithMemoryThrottle. Guarded by mu.
+
+		saturation     *saturationLimit // see WithSaturationLimit. nil, if not set. Read-only after initialization.
+		saturatedSince time.Time        // when the queue went over the saturation threshold. Zero, if below. Guarded by mu.
+
+		held   map[*Future]func() // jobs submitted later, with the function preventing their submission. Guarded by mu.
+		shared map[string]*Future // in-flight tasks by key, see SubmitShared. Guarded by mu.
+
+		retained []*job // failed jobs kept for RequeueFailed, see WithRequeue. Guarded by mu.
+
+		redelivery redelivery // see WithRedelivery. Read-only after initialization.
+		backoff    Backoff    // see WithBackoff. nil, if not set. Read-only after initialization.
+		exitOnErr  bool       // see WithExitOnError. Read-only after initialization.
+		maxErrors  int        // see WithMaxErrors. Read-only after initialization.
+
+		idempotency IdempotencyStore // see WithIdempotencyStore. nil, if not set. Read-only after initialization.
+		cache       *cacheSetting    // see WithCache. nil, if not set. Read-only after initialization.
+
+		classes map[string]*classLimit // see WithClassLimits. nil, if not set. The map is read-only after initialization, the counts are guarded by mu.
+
+		scaler *autoscaler // see WithLatencyAutoscaler. nil, if not set. Has its own lock.
+
+		queueWait *histogram // time jobs waited between their submission and their start, see Stats. Lock-free.
+		duration  *histogram // time jobs took to execute, see Stats. Lock-free.
+		slowLog   *slowLog   // see WithSlowTaskLog. nil, if not set. Lock-free.
+
+		maxAge time.Duration // see WithAging. Zero, if tasks don't age. Read-only after initialization.
+
+		middleware []func(Task) Task // see WithTaskMiddleware. Read-only after initialization.
+		sinks      []func(Event)     // see WithEventSink. Read-only after initialization.
+		onProgress func(int, int)    // see WithOnProgress. nil, if not set. Read-only after initialization.
+		expected   int               // task count the pool was created with, see Progress.
+
+		window    time.Duration         // see WithCoalesceWindow. Read-only after initialization.
+		coalesced map[string]*coalesced // tasks waiting for their window to elapse, by key. Guarded by mu.
+
+		classify func(error) Severity // see WithErrorClassifier. nil, if not set. Read-only after initialization.
+		policy   SchedulingPolicy     // see WithSchedulingPolicy. Read-only after initialization.
+
+		limiter Limiter                 // see WithRateLimit. nil, if not set. Read-only after initialization.
+		halt    context.Context         // done once the pool stops or completes, see Context. Read-only if set along with limiter, set lazily and guarded by mu otherwise.
+		unhalt  context.CancelCauseFunc // cancels halt.
+		halted  bool                    // set once the pool stops or completes. Guarded by mu.
+		cause   error                   // why the pool stopped or completed, see Context. Set along with halted.
+
+		done       chan struct{}           // closed when Wait has finished all the exit formalities.
+		settling   sync.Once               // completes the pool in the background once it is closed, see Done.
+		afterFuncs map[*afterFunc]struct{} // callbacks to run on completion, see AfterFunc. Guarded by mu.
+		completed  bool                    // set along with closing done. Guarded by mu.
+		children   map[*Pool]struct{}      // pools created with Child that have not completed yet. Guarded by mu.
+		parent     *Pool                   // the pool this one was created from with Child, nil otherwise.
+
 		// Initially, it was thought that not to export this type
 		// as we want to force users to use New() to create a new pool
 		// and limit the scope of initialized pool to the same function
@@ -35,27 +139,96 @@ type (
 
 	// Task is a unit of work that is submitted to the pool by consumers.
 	Task func() error
+
+	// TaskCtx is a Task that receives a context, cancelled once its outcome is no longer needed. See SubmitHedged.
+	TaskCtx func(ctx context.Context) error
+
+	// job is a Task along with the bookkeeping the pool needs to execute it.
+	job struct {
+		fn    Task
+		fut   *Future // nil, if the task was submitted without a handle.
+		label string  // sub-queue the job belongs to, see WithWeightedRandomDispatch.
+		name  string  // see TaskName.
+		class string  // see TaskClass.
+		key   string  // see TaskIdempotencyKey. Empty, if not set.
+
+		cacheKey string // see TaskCacheKey. Empty, if not set.
+		affinity string // see TaskAffinity. Empty, if not set.
+		memo     memo   // see WithCache.
+
+		duration time.Duration // expected run time, see TaskDuration. Zero, if unknown.
+		deadline time.Time     // see TaskDeadline. Zero, if none.
+		priority int           // see TaskPriority. Guarded by the mutex of the pool once the job is submitted.
+		weight   int64         // share of the capacity of the pool the job holds while running, see TaskWeight.
+		tenant   string        // tenant the job is submitted for, see WithTenantQuotas.
+		delayed  bool          // held before being submitted, the pool accepted it before its intake was closed.
+		panicked *PanicError   // set by the worker if the task panicked, see WithPanicRecovery.
+		after    []*Future     // tasks that have to succeed before this one runs, see After.
+		backoff  Backoff       // see TaskBackoff. nil, if not set.
+
+		ctx context.Context // context of the submitter, see TaskContext. nil, if not set.
+
+		// finish, if set, is called exactly once when the job has finished, whether it was executed or not.
+		// It is not called for jobs rejected by the pool.
+		finish func(err error)
+
+		task     Task         // as submitted, before middleware, see Future.Requeue.
+		opts     []TaskOption // as submitted, see Future.Requeue.
+		requeued uint32       // set to 1 once the job is requeued. Should be manipulated by sync/atomic.
+
+		id          uint64
+		submittedAt time.Time
+		startedAt   time.Time // zero until a worker starts the job.
+		finishedAt  time.Time // zero until the task returns.
+		callers     []uintptr // call stack of the submitter, see WithSlowTaskLog. nil, if not set.
+	}
 )
 
+// New creates a pool that can hold up to numTasks queued tasks, configured with opts. If some settings are
+// invalid, the error joins the errors of all of them, e.g. both ErrInvalidWorkerCnt and ErrNilContext.
 func New(numTasks int, opts ...Option) (*Pool, error) {
-	if numTasks <= 0 {
-		return nil, fmt.Errorf("gowp.New(): %w", ErrInvalidBuffer)
+	p, err := newFromOptions(numTasks, opts)
+	if err != nil {
+		return nil, fmt.Errorf("gowp.New(): %w", err)
 	}
 
-	cfg := config{
-		ctx:        context.TODO(),
-		numWorkers: runtime.NumCPU(),
+	return p, nil
+}
+
+// NewLongLived creates a pool for an unknown number of tasks, e.g. for a server that submits tasks as requests
+// come, until it shuts down. Its queue holds up to 1024 tasks, unless WithQueueSize is used. Progress reports
+// the tasks submitted so far as the total.
+func NewLongLived(opts ...Option) (*Pool, error) {
+	cfg, err := newConfig(defaultQueueSize, opts)
+	if err != nil {
+		return nil, fmt.Errorf("gowp.NewLongLived(): %w", err)
 	}
 
-	for _, opt := range opts {
-		opt(&cfg)
+	if cfg.queueSize == 0 {
+		cfg.queueSize = defaultQueueSize
 	}
 
-	if err := cfg.validate(); err != nil {
-		return nil, fmt.Errorf("gowp.New(): %w", err)
+	return newPool(cfg, 0), nil
+}
+
+// Close stops the pool from accepting tasks, without waiting for the submitted ones.
+// Queued tasks are still executed, Wait needs to be called to wait for them and to get the error, if any.
+// Calling Close more than once has no effect.
+func (p *Pool) Close() {
+	p.closeIntake()
+	atomic.StoreUint32(&p.closed, closed)
+}
+
+// CloseWithError closes the pool and stops it with err, as if a task failed with it, e.g. for a supervisor to
+// shut the pool down with a meaningful error. Queued tasks are discarded and Wait reports err, unless a task
+// failed before. Running tasks are not interrupted, they can watch Context. A nil err is replaced by ErrPoolStopped.
+func (p *Pool) CloseWithError(err error) {
+	if err == nil {
+		err = ErrPoolStopped
 	}
 
-	return newPool(cfg.ctx, cfg.numWorkers, numTasks, cfg.exitOnErr), nil
+	p.abort(err)
+	p.Close()
 }
 
 func (p *Pool) IsClosed() bool {
@@ -63,115 +236,1016 @@ func (p *Pool) IsClosed() bool {
 }
 
 func (p *Pool) Submit(t Task) error {
-	if err := p.submit(t); err != nil {
+	if err := p.submit(t, nil, nil); err != nil {
 		return fmt.Errorf("gowp.Pool.Submit(): %w", err)
 	}
 
 	return nil
 }
 
+// SubmitFuture submits a task to the pool and returns a Future that can be used
+// to wait for this particular task. It fails for the same reasons as Submit.
+func (p *Pool) SubmitFuture(t Task, opts ...TaskOption) (*Future, error) {
+	f := newFuture()
+	f.requeueable = true
+
+	if err := p.submit(t, f, opts); err != nil {
+		return nil, fmt.Errorf("gowp.Pool.SubmitFuture(): %w", err)
+	}
+
+	return f, nil
+}
+
+// Wait closes the pool and blocks until its tasks have been executed or discarded. It returns the first error
+// that occurred in the execution, if any. Wait can be called from several goroutines and repeatedly, each call
+// returns once the pool has completed and reports the same error.
 func (p *Pool) Wait() error {
+	if err := p.wait(); err != nil {
+		return fmt.Errorf("gowp.Pool.Wait(): %w", err)
+	}
+
+	return nil
+}
+
+// WaitContext is Wait bounded by ctx, e.g. to honour the deadline of a request. If ctx is done before the pool
+// has completed, it returns an error matching both ErrWaitAbandoned and the error of ctx. The pool is closed
+// anyway and keeps executing its queued tasks in the background, Wait or WaitContext report its outcome later on.
+//
+//	ctx, cancel := context.WithTimeout(ctx, time.Second)
+//	defer cancel()
+//	err := wp.WaitContext(ctx)
+func (p *Pool) WaitContext(ctx context.Context) error {
+	select {
+	case <-p.done:
+	default:
+		go func() { _ = p.wait() }()
+
+		select {
+		case <-p.done:
+		case <-ctx.Done():
+			return fmt.Errorf("gowp.Pool.WaitContext(): %w: %w", ErrWaitAbandoned, ctx.Err())
+		}
+	}
+
+	if err := p.wait(); err != nil {
+		return fmt.Errorf("gowp.Pool.WaitContext(): %w", err)
+	}
+
+	return nil
+}
+
+// TryWait reports whether the pool has completed without blocking, along with the error Wait returns, if so.
+// Unlike Wait, it doesn't close the pool, see Done for a pool to complete once it is closed.
+func (p *Pool) TryWait() (done bool, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !p.completed {
+		return false, nil
+	}
+
+	if p.err != nil {
+		return true, fmt.Errorf("gowp.Pool.TryWait(): %w", p.err)
+	}
+
+	return true, nil
+}
+
+// wait is Wait without decorating the error.
+func (p *Pool) wait() error {
 	p.closeOnce.Do(func() {
-		close(p.in)
+		p.closeIntake()
 		atomic.StoreUint32(&p.closed, closed)
 
+		if p.inline {
+			p.awaitInline()
+		}
+
 		p.wg.Wait() // here, all workers are returned and no worker is writing to p.errs Only error handling go routine will write an error, if any.
 
 		close(p.exitFromErrG) // signal to the error handling go routine to exit (if not initiated by error occurrence OR context cancellation).
 
-		p.err = <-p.errs // wait for the error handling go routine to exit and write an error, if any.
+		lazy := false
+		p.watching.Do(func() { lazy = true })
+		if lazy {
+			p.monitor() // nothing was reported, the goroutine was never needed. It returns right away.
+		}
+
+		err := <-p.outcome // wait for the error handling go routine to exit and write an error, if any.
+
+		// jobs left in the queue will never run, release anyone waiting on them.
+		// they are removed rather than popped, which would leave those of tenants at quota behind.
+		p.mu.Lock()
+		left := p.queue.removeIf(func(*job) bool { return true })
+		p.mu.Unlock()
+
+		if p.shards != nil {
+			left = append(left, p.shards.drain()...)
+		}
+
+		for _, j := range left {
+			p.drop(j)
+		}
+
+		if q, ok := p.queue.(*fifo); ok {
+			p.mu.Lock()
+			q.release()
+			p.mu.Unlock()
+		}
+
+		p.complete(err)
 	})
 
-	if p.err != nil {
-		return fmt.Errorf("gowp.Pool.Wait(): %w", p.err)
+	// every caller gets here once the first one has completed the pool, the error doesn't change anymore.
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.err
+}
+
+func newFromOptions(numTasks int, opts []Option) (*Pool, error) {
+	cfg, err := newConfig(numTasks, opts)
+	if err != nil {
+		return nil, err
 	}
 
-	return nil
+	return newPool(cfg, numTasks), nil
 }
 
-func newPool(ctx context.Context, numWorkers, numTasks int, exitOnErr bool) *Pool {
+func newConfig(numTasks int, opts []Option) (config, error) {
+	cfg := config{
+		ctx:        context.TODO(),
+		numWorkers: runtime.NumCPU(),
+		clock:      systemClock{},
+	}
+
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	err := cfg.validate()
+	if numTasks <= 0 {
+		err = errors.Join(ErrInvalidBuffer, err)
+	}
+
+	if err != nil {
+		return config{}, err
+	}
+
+	return cfg, nil
+}
+
+// newPool creates a pool expecting numTasks tasks, which is also the size of its queue unless WithQueueSize is used.
+func newPool(cfg config, numTasks int) *Pool {
+	size := numTasks
+	if cfg.queueSize > 0 {
+		size = cfg.queueSize
+	}
+
 	p := &Pool{
 		wg:           sync.WaitGroup{},
-		in:           make(chan Task, numTasks),
 		closeOnce:    sync.Once{},
 		errs:         make(chan error, 1),
+		fatal:        make(chan error, 1),
+		outcome:      make(chan error, 1),
 		quit:         make(chan struct{}, 1),
 		exitFromErrG: make(chan struct{}, 1),
+		aborts:       make(chan error, 1),
+		done:         make(chan struct{}),
+		closing:      make(chan struct{}),
+		queue:        cfg.newQueue(),
+		size:         size,
+		hooks:        cfg.hooks,
+		hookErrs:     cfg.hookErrs,
+		classify:     cfg.classify,
+		policy:       cfg.policy,
+		ctx:          cfg.ctx,
+		clock:        cfg.clock,
+		propagate:    cfg.propagate,
+		breaker:      cfg.breaker,
+		saturation:   cfg.saturation,
+		redelivery:   cfg.redelivery,
+		retries:      cfg.retries,
+		backoff:      cfg.backoff,
+		idempotency:  cfg.idempotency,
+		cache:        cfg.cache,
+		exitOnErr:    cfg.exitOnErr,
+		maxErrors:    cfg.maxErrors,
+		middleware:   cfg.middleware,
+		sinks:        cfg.sinks,
+		onProgress:   cfg.onProgress,
+		expected:     numTasks,
+		window:       cfg.window,
+		inline:       cfg.inline,
+		recovers:     cfg.recovers,
+		locked:       cfg.lockThreads,
+		requeue:      cfg.requeue,
+		workers:      cfg.numWorkers,
+		target:       cfg.numWorkers,
+		queueWait:    &histogram{},
+		duration:     &histogram{},
+		slowLog:      cfg.slowLog,
 	}
+	p.ready.L = &p.mu
+	p.room.L = &p.mu
 
-	go func() {
-		var err error
+	if cfg.firstSuccess {
+		p.success = make(chan struct{})
+	}
+
+	if cfg.policy != FIFO {
+		p.maxAge = cfg.maxAge
+	}
+
+	if tq, ok := p.queue.(*tenantQueue); ok {
+		p.tenants = tq
+	}
+
+	if cfg.capacity != nil {
+		p.capacity = &capacity{size: cfg.capacity.size}
+	}
+
+	if len(cfg.classes) > 0 {
+		p.classes = newClassLimits(cfg.classes)
+	}
+
+	if cfg.shards > 1 && !cfg.inline && !cfg.lazy && cfg.weights == nil && cfg.tenants == nil {
+		p.shards = newShards(cfg.shards, size)
+	}
+
+	if cfg.inline {
+		p.workers, p.target = 0, 0
+		cfg.numWorkers, cfg.boost, cfg.burst, cfg.adaptive, cfg.gc, cfg.autoscale = 0, nil, nil, nil, nil, nil
+	}
+
+	p.lazy = cfg.lazy && !cfg.inline
+	if p.lazy {
+		p.workers = cfg.prestart
+	}
+
+	if cfg.adaptive != nil {
+		p.adaptive = newAdaptiveLimit(*cfg.adaptive, cfg.numWorkers)
+	}
+
+	if cfg.gc != nil {
+		p.gc = newGCLimit(*cfg.gc, cfg.numWorkers)
+	}
+
+	if cfg.autoscale != nil {
+		p.scaler = newAutoscaler(cfg.autoscale)
+	}
+
+	if cfg.burst != nil {
+		p.burst = newBurstBucket(*cfg.burst, cfg.numWorkers, cfg.clock.Now())
+	}
+
+	if cfg.limiter != nil {
+		p.limiter = cfg.limiter
+		p.halt, p.unhalt = context.WithCancelCause(cfg.ctx)
+	}
+
+	if cfg.ctx.Done() != nil {
+		p.watch() // the context can be cancelled at any time.
+	}
+
+	p.startWorkers(cfg.prestart)
+
+	if cfg.boost != nil {
+		go p.boost(*cfg.boost, cfg.numWorkers)
+	}
+
+	if cfg.stall != nil {
+		go p.detectStalls(*cfg.stall)
+	}
+
+	if cfg.memory != nil && !cfg.inline {
+		go p.throttleMemory(*cfg.memory)
+	}
+
+	if p.gc != nil {
+		go p.limitGC()
+	}
+
+	if p.scaler != nil {
+		go p.autoscale()
+	}
+
+	p.emit(Event{Kind: EventCreated, Workers: cfg.numWorkers})
+
+	return p
+}
+
+// watch starts the error handling goroutine, unless it is started already. The goroutine is started
+// as soon as something may stop the pool, so that pools running without incident don't pay for it.
+func (p *Pool) watch() {
+	p.watching.Do(func() { go p.monitor() })
+}
+
+// monitor is the error handling goroutine. It decides when the pool should stop and with which error.
+func (p *Pool) monitor() {
+	var err error
+
+	for {
 		select {
-		case <-ctx.Done():
-			err = ctx.Err()
-			close(p.quit)
+		case <-p.ctx.Done():
+			err = p.ctxErr()
+			p.stop(err)
+
+		case e := <-p.errs:
+			if p.success != nil {
+				if err == nil {
+					err = e // reported only if no task succeeds.
+				}
+
+				continue
+			}
+
+			if err == nil {
+				err = e
+			}
+
+			// workers count the error before reporting it, the count is up to date even if some errors were dropped.
+			if !p.exitOnErr && (p.maxErrors == 0 || atomic.LoadInt64(&p.counts.failed) < int64(p.maxErrors)) {
+				continue // keep watching the context and the error count.
+			}
 
-		case err = <-p.errs:
-			if exitOnErr {
-				close(p.quit)
+			p.stop(err)
+
+		case e := <-p.fatal:
+			err = e
+			p.stop(err)
+
+		case e := <-p.aborts:
+			if err == nil {
+				err = e
 			}
 
+			p.stop(err)
+
+		case <-p.success:
+			err = nil
+			p.stop(nil)
+
 		case <-p.exitFromErrG:
 			// p.Wait() will be close p.exitFromErrG to signal the exit.
 			// this helps to avoid goroutine leak, in case if we don't encounter any errors.
+			// All the workers have returned, pick up what they reported last, if it was not received yet.
+			select {
+			case <-p.success:
+				p.outcome <- nil
+				return
+			default:
+			}
+
+			select {
+			case e := <-p.fatal:
+				err = e
+			case e := <-p.errs:
+				if err == nil {
+					err = e
+				}
+			default:
+			}
+
+			select {
+			case e := <-p.aborts:
+				if err == nil {
+					err = e // the pool was aborted as its last tasks finished.
+				}
+			default:
+			}
+
+			if err == nil {
+				err = p.ctxErr() // the context might be done, select picks randomly among ready cases.
+			}
 		}
 
-		p.errs <- err
+		p.outcome <- err
+		return
+	}
+}
+
+// spawn starts a worker. A temporary worker exits as soon as it finds the queue empty.
+func (p *Pool) spawn(temporary bool) {
+	p.start(temporary, nil)
+}
+
+// start starts a worker that calls started, if not nil, once it has called the OnWorkerStart hooks.
+func (p *Pool) start(temporary bool, started func()) {
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+
+		if p.locked {
+			runtime.LockOSThread()
+			defer runtime.UnlockOSThread()
+		}
+
+		p.onWorkerStart()
+		if started != nil {
+			started()
+		}
+
+		p.work(temporary)
 	}()
+}
 
-	for i := 0; i < numWorkers; i++ {
-		p.wg.Add(1)
-		go func() {
-			defer p.wg.Done()
-			work(p.in, p.quit, p.errs)
-		}()
-	}
+func (p *Pool) submit(t Task, f *Future, opts []TaskOption) error {
+	return p.submitJob(t, f, opts, false)
+}
 
-	return p
+// nextID returns the identifier of a new task, see Future.ID.
+func (p *Pool) nextID() uint64 {
+	return atomic.AddUint64(&p.counts.seq, 1)
 }
 
-func (p *Pool) submit(t Task) (err error) {
+// submitJob submits a task. If block is true, it waits for room in a full queue instead of failing with ErrNoBuffer.
+func (p *Pool) submitJob(t Task, f *Future, opts []TaskOption, block bool) error {
 	if t == nil {
 		return ErrNilTask
 	}
 
-	if p.IsClosed() {
-		return ErrPoolClosed
+	if f != nil && f.pool == nil {
+		f.pool = p // set already for delayed tasks, whose Future is shared.
+	}
+
+	j := &job{fn: t, task: t, opts: opts, fut: f, weight: 1}
+	switch {
+	case f == nil:
+		j.id = p.nextID()
+	case f.id == 0:
+		f.id = p.nextID()
+		j.id = f.id
+	default:
+		j.id = f.id // assigned when the task was held, see SubmitAfter and After.
+	}
+
+	if f != nil {
+		f.job.Store(j)
+	}
+
+	for _, opt := range opts {
+		opt(j)
+	}
+
+	if p.IsClosed() && !j.delayed {
+		return p.reject(j, ErrPoolClosed)
+	}
+
+	if len(j.after) > 0 {
+		return p.reject(j, p.await(t, f, opts, j))
+	}
+
+	if p.capacity != nil && (j.weight <= 0 || j.weight > p.capacity.size) {
+		return p.reject(j, ErrInvalidWeight)
+	}
+
+	j.submittedAt = p.clock.Now()
+	if p.slowLog != nil {
+		j.callers = callers()
+	}
+
+	if p.cache != nil && j.cacheKey != "" {
+		j.fn = p.memoize(j)
+	}
+
+	if p.idempotency != nil && j.key != "" {
+		j.fn = p.idempotent(j)
+	}
+
+	j.fn = p.wrap(j.fn)
+
+	p.onSubmit(j)
+
+	removed, err := p.enqueue(j, block)
+	p.skipAll(removed, ErrTaskCanceled)
+
+	if err != nil {
+		p.onFinish(j, err)
+		return p.reject(j, err)
+	}
+
+	if p.inline {
+		p.runInline()
 	}
 
-	defer func() {
-		if p := recover(); p != nil {
-			err = ErrInvalidSend
+	return nil
+}
+
+// reject reports the submission of j failing with err, if not nil, and returns err.
+func (p *Pool) reject(j *job, err error) error {
+	if err != nil && len(p.sinks) > 0 {
+		p.emit(Event{Kind: EventTaskRejected, Task: j.info(), Err: err})
+	}
+
+	return err
+}
+
+// enqueue queues j. It returns the cancelled jobs removed from a full queue to make room for j.
+func (p *Pool) enqueue(j *job, block bool) (removed []*job, err error) {
+	if p.shards != nil && !block && !j.delayed {
+		return nil, p.shard(j)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for {
+		if p.intakeOff && !j.delayed {
+			return removed, ErrInvalidSend
+		}
+
+		if j.delayed {
+			select {
+			case <-p.quit:
+				return removed, ErrPoolClosed // nobody would pick it up, as the intake might be closed.
+			default:
+			}
+		}
+
+		if p.queue.len() < p.size {
+			break
+		}
+
+		if atomic.LoadInt64(&p.counts.tombstones) > 0 {
+			if r := p.compact(); len(r) > 0 {
+				removed = append(removed, r...)
+				continue
+			}
+		}
+
+		if !block {
+			return removed, ErrNoBuffer
+		}
+
+		select {
+		case <-p.quit:
+			return removed, ErrPoolClosed // the queue won't drain anymore.
+		default:
+		}
+
+		p.room.Wait()
+	}
+
+	if err := p.queue.push(j); err != nil {
+		return removed, err
+	}
+
+	atomic.AddInt64(&p.counts.submitted, 1)
+	p.ready.Signal()
+
+	if p.lazy {
+		p.grow()
+	}
+
+	if p.burst != nil {
+		p.checkBurst()
+	}
+
+	if p.saturation != nil {
+		p.noteLoad()
+	}
+
+	return removed, nil
+}
+
+// noteCanceled is called when a queued job is cancelled. Cancelled jobs stay in the queue as tombstones
+// until a worker skips them. Once they make up a good part of the queue, they are removed in one go,
+// so that they don't keep occupying memory, buffer and workers' time.
+func (p *Pool) noteCanceled() {
+	if atomic.AddInt64(&p.counts.tombstones, 1) < compactMin {
+		return
+	}
+
+	var removed []*job
+
+	p.mu.Lock()
+	if 2*atomic.LoadInt64(&p.counts.tombstones) >= int64(p.queue.len()) {
+		removed = p.compact()
+	}
+	p.mu.Unlock()
+
+	p.skipAll(removed, ErrTaskCanceled)
+}
+
+// compact removes cancelled jobs from the queue. p.mu must be held.
+// The removed jobs should be passed to skip once the lock is released.
+func (p *Pool) compact() []*job {
+	removed := p.queue.removeIf((*job).canceled)
+	atomic.AddInt64(&p.counts.tombstones, -int64(len(removed)))
+	p.room.Broadcast()
+
+	return removed
+}
+
+// closeIntake stops the pool from accepting new jobs. Workers exit once the queue is drained.
+func (p *Pool) closeIntake() {
+	if p.shards != nil {
+		p.shards.close() // before the intake is seen closed, so that workers find all the jobs when they drain.
+	}
+
+	p.mu.Lock()
+	first := !p.intakeOff
+	if first {
+		close(p.closing)
+	}
+	p.intakeOff = true
+	p.ready.Broadcast()
+	p.room.Broadcast()
+	children := p.childList()
+	p.mu.Unlock()
+
+	if first {
+		p.emit(Event{Kind: EventClosed})
+	}
+
+	for _, c := range children {
+		c.Close()
+	}
+}
+
+// stop signals workers to quit without picking up pending jobs. cause is the error the pool stops with, if any.
+func (p *Pool) stop(cause error) {
+	close(p.quit)
+
+	p.mu.Lock()
+	p.halts(cause)
+	p.releaseHeld()
+	p.ready.Broadcast()
+	p.room.Broadcast()
+	children := p.childList()
+	p.mu.Unlock()
+
+	p.emit(Event{Kind: EventStopped, Err: cause})
+
+	for _, c := range children {
+		c.CloseWithError(cause)
+	}
+}
+
+func (p *Pool) work(temporary bool) {
+	affinity := "" // of the last job of the worker, see TaskAffinity.
+	for {
+		j, ok := p.next(temporary, affinity)
+		if !ok {
+			p.emit(Event{Kind: EventWorkerExited})
+			return
+		}
+
+		p.handle(j)
+		affinity = j.affinity
+
+		if j.panicked != nil {
+			p.replace(temporary)
+			p.emit(Event{Kind: EventWorkerExited, Task: j.info(), Err: j.panicked})
+			return
+		}
+	}
+}
+
+// handle processes a job taken from the queue and releases what it held.
+func (p *Pool) handle(j *job) {
+	took, ran := p.process(j)
+	if p.limited() {
+		p.adapt(took, ran)
+	}
+
+	if p.scaler != nil && ran {
+		p.scaler.record(j.finishedAt.Sub(j.submittedAt)) // latency as seen by the submitter, queueing included.
+	}
+
+	if p.tenants != nil {
+		p.leave(j)
+	}
+
+	if p.classes[j.class] != nil {
+		p.exit(j)
+	}
+
+	p.progress()
+}
+
+// process executes j, unless it has to be skipped. It reports whether the task was executed
+// and how long it took.
+// A task failed fast by the circuit breaker is not considered executed.
+func (p *Pool) process(j *job) (took time.Duration, ran bool) {
+	if p.capacity != nil {
+		if !p.capacity.acquire(j.weight, p.quit) {
+			p.drop(j) // the pool is stopping.
+			return 0, false
+		}
+
+		defer p.capacity.release(j.weight)
+	}
+
+	if !j.deadline.IsZero() && p.clock.Now().After(j.deadline) && j.expire() {
+		p.skip(j, ErrDeadlineExceeded)
+		if len(p.sinks) > 0 {
+			p.emit(Event{Kind: EventTaskExpired, Task: j.info(), Err: ErrDeadlineExceeded})
+		}
+
+		return 0, false // too late already, see TaskDeadline.
+	}
+
+	// a cancelled task doesn't wait for the limiter, start skips it right after.
+	if p.limiter != nil && !j.canceled() && !p.throttle(j) {
+		return 0, false
+	}
+
+	if !j.start() {
+		atomic.AddInt64(&p.counts.tombstones, -1)
+		p.skip(j, ErrTaskCanceled)
+		return 0, false // cancelled while it was queued.
+	}
+
+	allowed, probe := true, false
+	if p.breaker != nil {
+		if allowed, probe = p.breaker.allow(p.clock.Now()); !allowed {
+			j.fn = func() error { return ErrCircuitOpen }
+		}
+	}
+
+	j.startedAt = p.clock.Now()
+	p.queueWait.observe(j.startedAt.Sub(j.submittedAt))
+
+	p.inflight.add(j)
+	p.onStart(j)
+	err := p.exec(j)
+	p.onFinish(j, err)
+	p.inflight.remove(j)
+
+	took = j.finishedAt.Sub(j.startedAt)
+	p.duration.observe(took)
+	if p.slowLog != nil {
+		p.slowLog.observe(j, took)
+	}
+
+	sev := p.severity(err)
+	if p.breaker != nil && allowed {
+		p.breaker.record(err != nil && sev != SeverityIgnore, probe, p.clock.Now())
+	}
+
+	if err != nil {
+		te := j.failed(err)
+		if sev != SeverityIgnore && len(p.sinks) > 0 {
+			p.emit(Event{Kind: EventTaskFailed, Task: j.info(), Err: te})
+		}
+
+		if p.requeue && j.requeueable() && sev != SeverityIgnore {
+			p.retain(j)
+		}
+
+		p.fail(te, sev)
+		return took, allowed
+	}
+
+	atomic.AddInt64(&p.counts.succeeded, 1)
+
+	if p.success != nil {
+		p.successOnce.Do(func() {
+			p.watch()
+			close(p.success)
+		})
+	}
+
+	return took, true
+}
+
+// severity classifies the error returned by a task, see WithErrorClassifier.
+func (p *Pool) severity(err error) Severity {
+	if err == nil || p.classify == nil {
+		return SeverityError
+	}
+
+	return p.classify(err)
+}
+
+// fail accounts for a task that returned err and reports err to the error handling goroutine as per its severity.
+func (p *Pool) fail(err error, sev Severity) {
+	if sev != SeverityIgnore {
+		p.watch()
+	}
+
+	switch sev {
+	case SeverityIgnore:
+		atomic.AddInt64(&p.counts.ignored, 1)
+
+	case SeverityFatal:
+		atomic.AddInt64(&p.counts.failed, 1)
+
+		select {
+		case p.fatal <- err:
+		default:
+			// drop the error as another fatal error is pending, the pool is stopping anyway.
 		}
-	}()
 
-	select {
-	case p.in <- t:
-		err = nil
 	default:
-		err = ErrNoBuffer
+		atomic.AddInt64(&p.counts.failed, 1)
+
+		select {
+		case p.errs <- err:
+		default:
+			// drop the error as p.errs is full, eventually it will receive quit signal
+		}
+	}
+}
+
+// skip accounts for a job that will not be executed, reason is ErrTaskCanceled, ErrTaskDiscarded or ErrDeadlineExceeded.
+func (p *Pool) skip(j *job, reason error) {
+	switch reason {
+	case ErrTaskDiscarded:
+		atomic.AddInt64(&p.counts.discarded, 1)
+	case ErrDeadlineExceeded:
+		atomic.AddInt64(&p.counts.expired, 1)
+	default:
+		atomic.AddInt64(&p.counts.canceled, 1)
+	}
+
+	p.onFinish(j, reason)
+	j.done(reason)
+}
+
+// drop accounts for a queued job that will never be executed. It returns false if the job had been cancelled already.
+func (p *Pool) drop(j *job) bool {
+	if j.discard() {
+		p.skip(j, ErrTaskDiscarded)
+		return true
 	}
 
-	return
+	atomic.AddInt64(&p.counts.tombstones, -1)
+	p.skip(j, ErrTaskCanceled)
+
+	return false
 }
 
-func work(in <-chan Task, quit <-chan struct{}, errs chan<- error) {
+func (p *Pool) skipAll(jobs []*job, reason error) {
+	for _, j := range jobs {
+		p.skip(j, reason)
+	}
+}
+
+// next blocks until there is a job to execute. It returns false if the worker should exit.
+// A temporary worker doesn't block, it exits as soon as the queue is empty. A job with the
+// given affinity key is preferred, see TaskAffinity.
+func (p *Pool) next(temporary bool, affinity string) (*job, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
 	for {
 		select {
-		case <-quit:
-			return
-		case t, ok := <-in:
-			if !ok {
-				return
+		case <-p.quit:
+			return p.retire(temporary)
+		default:
+		}
+
+		if !temporary && p.workers > p.target {
+			return p.retire(temporary) // the pool has been shrunk.
+		}
+
+		if p.paused || p.throttled {
+			if temporary {
+				return p.retire(temporary)
 			}
 
-			if err := t(); err != nil {
-				select {
-				case errs <- err:
-				default:
-					// drop the error as p.errs is full, eventually it will receive quit signal
-				}
+			p.idle++
+			p.ready.Wait()
+			p.idle--
+
+			continue
+		}
+
+		if p.shards != nil && p.queue.len() == 0 {
+			p.transfer()
+		}
+
+		if !p.limited() || p.running < p.allowed() {
+			if j := p.take(affinity); j != nil {
+				return j, true
+			}
+		} else if p.queue.len() > 0 {
+			p.ready.Wait() // over the adaptive limit, wait for a running task to finish.
+			continue
+		}
+
+		// jobs may be left in the queue while their tenants are at quota or their classes at their limit,
+		// they are picked up later.
+		// held jobs are still to come.
+		if (p.intakeOff && p.queue.len() == 0 && p.scheduled == 0) || temporary {
+			return p.retire(temporary)
+		}
+
+		if p.shards != nil {
+			// count as a sleeper before looking at the shards a last time, see Pool.shard.
+			atomic.AddInt32(&p.shards.sleepers, 1)
+			if p.transfer() > 0 {
+				atomic.AddInt32(&p.shards.sleepers, -1)
+				continue
 			}
 		}
+
+		p.idle++
+		p.ready.Wait()
+		p.idle--
+
+		if p.shards != nil {
+			atomic.AddInt32(&p.shards.sleepers, -1)
+		}
 	}
 }
+
+// take pops the next job from the queue that the class limits admit, nil if there is none.
+// A queued job with the given affinity key is taken first, see TaskAffinity. p.mu must be held.
+func (p *Pool) take(affinity string) *job {
+	j := p.related(affinity)
+	if j == nil {
+		j = p.pop()
+	}
+
+	if j == nil {
+		return nil
+	}
+
+	if c := p.classes[j.class]; c != nil {
+		c.running++
+	}
+
+	p.room.Signal()
+	if p.limited() {
+		p.running++
+	}
+
+	if p.saturation != nil {
+		p.noteLoad()
+	}
+
+	return j
+}
+
+// retire accounts for an exiting worker. p.mu must be held.
+func (p *Pool) retire(temporary bool) (*job, bool) {
+	if temporary {
+		p.boosted--
+	} else {
+		p.workers--
+	}
+
+	return nil, false
+}
+
+// exec runs the job on the current worker.
+func (p *Pool) exec(j *job) error {
+	if p.propagate {
+		return p.runTraced(j)
+	}
+
+	return j.run(p.recovers, p.clock)
+}
+
+func (j *job) canceled() bool {
+	return j.fut != nil && j.fut.canceled()
+}
+
+func (j *job) start() bool {
+	return j.fut == nil || j.fut.start()
+}
+
+// run runs the job and reports its outcome. If recovers is true, a panic of the task is reported as a PanicError.
+// The end of the task is timed with clock.
+func (j *job) run(recovers bool, clock Clock) error {
+	var err error
+	if recovers {
+		err = j.call()
+	} else {
+		err = j.fn()
+	}
+
+	j.finishedAt = clock.Now()
+	if j.fut != nil {
+		j.fut.timing = j.timing()
+	}
+
+	j.done(err)
+
+	if j.fut != nil {
+		j.fut.complete(err)
+	}
+
+	return err
+}
+
+func (j *job) done(err error) {
+	if j.finish != nil {
+		j.finish(err)
+	}
+}
+
+// expire releases the waiters of a job whose deadline has passed. It returns false if the job had been cancelled already.
+func (j *job) expire() bool {
+	return j.fut == nil || j.fut.reject(ErrDeadlineExceeded)
+}
+
+// discard releases the waiters of a job that will never be executed.
+// It returns false if the job had been cancelled already.
+func (j *job) discard() bool {
+	return j.fut == nil || j.fut.discard()
+}
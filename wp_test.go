@@ -18,6 +18,15 @@ var testErr = errors.New("test error")
 
 var testFuncWithErr = func() error { return testErr }
 
+func testPool(ctx context.Context, numWorkers, numTasks int, exitOnErr bool, opts ...Option) *Pool {
+	cfg := config{ctx: ctx, numWorkers: numWorkers, exitOnErr: exitOnErr, clock: systemClock{}}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	return newPool(cfg, numTasks)
+}
+
 func TestNew(t *testing.T) {
 	type args struct {
 		numTasks int
@@ -47,6 +56,12 @@ func TestNew(t *testing.T) {
 			errVal:  ErrInvalidWorkerCnt,
 			wantErr: true,
 		},
+		{
+			name:    "invalid dispatch weights",
+			args:    args{numTasks: testDefaultNumTasks, opts: []Option{WithWeightedRandomDispatch(map[string]int{"a": 0})}},
+			errVal:  ErrInvalidWeights,
+			wantErr: true,
+		},
 		{
 			name:    "valid configuration",
 			args:    args{numTasks: testDefaultNumTasks, opts: []Option{WithExitOnError(true)}},
@@ -68,6 +83,90 @@ func TestNew(t *testing.T) {
 	}
 }
 
+func TestNew_invalidSettings(t *testing.T) {
+	_, err := New(0, WithNumWorkers(0), WithContext(nil), WithMaxErrors(-1))
+
+	for _, want := range []error{ErrInvalidBuffer, ErrInvalidWorkerCnt, ErrNilContext, ErrInvalidMaxErrors} {
+		if !errors.Is(err, want) {
+			t.Errorf("New() error = %v, want it to report %v", err, want)
+		}
+	}
+
+	if errors.Is(err, ErrInvalidShards) {
+		t.Errorf("New() error = %v, reports valid settings", err)
+	}
+}
+
+func TestNewLongLived(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    []Option
+		wantCap int
+		wantErr error
+	}{
+		{name: "default queue size", wantCap: defaultQueueSize},
+		{name: "queue size", opts: []Option{WithQueueSize(3)}, wantCap: 3},
+		{name: "invalid queue size", opts: []Option{WithQueueSize(-1)}, wantErr: ErrInvalidBuffer},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := NewLongLived(append([]Option{WithNumWorkers(1)}, tt.opts...)...)
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil && err != nil) {
+				t.Fatalf("NewLongLived() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			if got := p.Cap(); got != tt.wantCap {
+				t.Errorf("Cap() = %d, want %d", got, tt.wantCap)
+			}
+
+			if err := p.Submit(func() error { return nil }); err != nil {
+				t.Fatalf("Submit() error = %v", err)
+			}
+
+			p.Close()
+			if err := p.Wait(); err != nil {
+				t.Fatalf("Wait() error = %v", err)
+			}
+
+			if done, total := p.Progress(); done != 1 || total != 1 {
+				t.Errorf("Progress() = %d, %d, want 1, 1", done, total)
+			}
+		})
+	}
+}
+
+func TestWithQueueSize(t *testing.T) {
+	p, err := New(100, WithNumWorkers(1), WithQueueSize(1))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	release := make(chan struct{})
+	started := make(chan struct{})
+	_ = p.Submit(func() error { close(started); <-release; return nil })
+	<-started
+
+	if err := p.Submit(func() error { return nil }); err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+
+	if err := p.Submit(func() error { return nil }); !errors.Is(err, ErrNoBuffer) {
+		t.Errorf("Submit() to a full queue error = %v, want %v", err, ErrNoBuffer)
+	}
+
+	if _, total := p.Progress(); total != 100 {
+		t.Errorf("Progress() total = %d, want the 100 expected tasks", total)
+	}
+
+	close(release)
+	p.Close()
+	_ = p.Wait()
+}
+
 func TestPool_Submit(t *testing.T) {
 	type args struct {
 		t Task
@@ -82,14 +181,14 @@ func TestPool_Submit(t *testing.T) {
 	}{
 		{
 			name:    "nil task",
-			p:       newPool(context.Background(), testDefaultNumWorkers, testDefaultNumTasks, true),
+			p:       testPool(context.Background(), testDefaultNumWorkers, testDefaultNumTasks, true),
 			args:    args{t: nil},
 			wantErr: true,
 			errVal:  ErrNilTask,
 		},
 		{
 			name:    "submit task on closed pool",
-			p:       newPool(context.Background(), testDefaultNumWorkers, testDefaultNumTasks, true),
+			p:       testPool(context.Background(), testDefaultNumWorkers, testDefaultNumTasks, true),
 			args:    args{t: testNoOpFunc},
 			wantErr: true,
 			errVal:  ErrPoolClosed,
@@ -99,17 +198,17 @@ func TestPool_Submit(t *testing.T) {
 		},
 		{
 			name:    "submit task while pool is closing",
-			p:       newPool(context.Background(), testDefaultNumWorkers, testDefaultNumTasks, true),
+			p:       testPool(context.Background(), testDefaultNumWorkers, testDefaultNumTasks, true),
 			args:    args{t: testNoOpFunc},
 			wantErr: true,
 			errVal:  ErrInvalidSend,
 			setup: func(p *Pool) {
-				close(p.in)
+				p.closeIntake()
 			},
 		},
 		{
 			name:    "submit task after exhausting buffer",
-			p:       newPool(context.Background(), 1, 1, true),
+			p:       testPool(context.Background(), 1, 1, true),
 			args:    args{t: testNoOpFunc},
 			wantErr: true,
 			errVal:  ErrNoBuffer,
@@ -125,9 +224,16 @@ func TestPool_Submit(t *testing.T) {
 				})
 			},
 		},
+		{
+			name:    "submit task with unknown label",
+			p:       testPool(context.Background(), testDefaultNumWorkers, testDefaultNumTasks, true, WithWeightedRandomDispatch(map[string]int{"a": 1})),
+			args:    args{t: testNoOpFunc},
+			wantErr: true,
+			errVal:  ErrUnknownLabel,
+		},
 		{
 			name:    "submit task with no error",
-			p:       newPool(context.Background(), testDefaultNumWorkers, testDefaultNumTasks, true),
+			p:       testPool(context.Background(), testDefaultNumWorkers, testDefaultNumTasks, true),
 			args:    args{t: testNoOpFunc},
 			wantErr: false,
 			errVal:  nil,
@@ -152,6 +258,68 @@ func TestPool_Submit(t *testing.T) {
 	}
 }
 
+func TestPool_Close(t *testing.T) {
+	p := testPool(context.Background(), 1, testDefaultNumTasks, false)
+
+	f, _ := p.SubmitFuture(testNoOpFunc)
+	p.Close()
+	p.Close()
+
+	if !p.IsClosed() {
+		t.Error("Pool.IsClosed() = false after Close")
+	}
+
+	if err := p.Submit(testNoOpFunc); !errors.Is(err, ErrPoolClosed) {
+		t.Errorf("Pool.Submit() after Close = %v, want %v", err, ErrPoolClosed)
+	}
+
+	if err := f.Err(); err != nil {
+		t.Errorf("Future.Err() = %v, queued task should run after Close", err)
+	}
+
+	if err := p.Wait(); err != nil {
+		t.Errorf("Pool.Wait() = %v, want nil", err)
+	}
+}
+
+func TestPool_CloseWithError(t *testing.T) {
+	errSupervisor := errors.New("supervisor shut down")
+
+	tests := []struct {
+		name    string
+		reason  error
+		wantErr error
+	}{
+		{name: "reason", reason: errSupervisor, wantErr: errSupervisor},
+		{name: "nil reason", wantErr: ErrPoolStopped},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := testPool(context.Background(), 1, testDefaultNumTasks, false)
+
+			release := make(chan struct{})
+			_ = p.Submit(func() error { <-release; return nil })
+			queued, _ := p.SubmitFuture(testNoOpFunc)
+
+			p.CloseWithError(tt.reason)
+			<-p.Context().Done() // the pool stops asynchronously.
+			close(release)
+
+			if err := p.Wait(); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Pool.Wait() = %v, want %v", err, tt.wantErr)
+			}
+
+			if !p.IsClosed() {
+				t.Error("Pool.IsClosed() = false after CloseWithError")
+			}
+
+			if err := queued.Err(); !errors.Is(err, ErrTaskDiscarded) {
+				t.Errorf("Future.Err() = %v, want %v", err, ErrTaskDiscarded)
+			}
+		})
+	}
+}
+
 func TestPool_Wait(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	tests := []struct {
@@ -163,7 +331,7 @@ func TestPool_Wait(t *testing.T) {
 	}{
 		{
 			name:    "error reported by one of the task",
-			p:       newPool(context.Background(), testDefaultNumWorkers, testDefaultNumTasks, true),
+			p:       testPool(context.Background(), testDefaultNumWorkers, testDefaultNumTasks, true),
 			wantErr: true,
 			errVal:  testErr,
 			setup: func(p *Pool) {
@@ -175,7 +343,7 @@ func TestPool_Wait(t *testing.T) {
 		},
 		{
 			name:    "context cancelled",
-			p:       newPool(ctx, testDefaultNumWorkers, testDefaultNumTasks, true),
+			p:       testPool(ctx, testDefaultNumWorkers, testDefaultNumTasks, true),
 			wantErr: true,
 			errVal:  context.Canceled,
 			setup: func(p *Pool) {
@@ -204,3 +372,287 @@ func TestPool_Wait(t *testing.T) {
 		})
 	}
 }
+
+func TestPool_Wait_concurrent(t *testing.T) {
+	tests := []struct {
+		name    string
+		task    Task
+		wantErr error
+	}{
+		{name: "no error", task: testNoOpFunc},
+		{name: "error", task: testFuncWithErr, wantErr: testErr},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			const waiters = 8
+
+			p := testPool(context.Background(), testDefaultNumWorkers, testDefaultNumTasks, true)
+			for i := 0; i < testDefaultNumTasks; i++ {
+				_ = p.Submit(tt.task)
+			}
+
+			errs := make(chan error, waiters)
+			for i := 0; i < waiters; i++ {
+				go func() { errs <- p.Wait() }()
+			}
+
+			first := <-errs
+			if !errors.Is(first, tt.wantErr) || (first == nil) != (tt.wantErr == nil) {
+				t.Fatalf("Pool.Wait() = %v, want %v", first, tt.wantErr)
+			}
+
+			for i := 1; i < waiters; i++ {
+				if err := <-errs; errors.Unwrap(err) != errors.Unwrap(first) {
+					t.Errorf("Pool.Wait() = %v, want %v as returned by another call", err, first)
+				}
+			}
+
+			if err := p.Wait(); errors.Unwrap(err) != errors.Unwrap(first) {
+				t.Errorf("Pool.Wait() = %v after %v", err, first)
+			}
+		})
+	}
+}
+
+func TestPool_WaitContext(t *testing.T) {
+	tests := []struct {
+		name    string
+		task    Task
+		timeout time.Duration
+		wantErr []error
+	}{
+		{name: "completes in time", task: testNoOpFunc, timeout: time.Second},
+		{name: "task error", task: testFuncWithErr, timeout: time.Second, wantErr: []error{testErr}},
+		{
+			name:    "deadline exceeded",
+			task:    func() error { time.Sleep(100 * time.Millisecond); return nil },
+			timeout: 10 * time.Millisecond,
+			wantErr: []error{ErrWaitAbandoned, context.DeadlineExceeded},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := testPool(context.Background(), testDefaultNumWorkers, testDefaultNumTasks, true)
+			_ = p.Submit(tt.task)
+
+			ctx, cancel := context.WithTimeout(context.Background(), tt.timeout)
+			defer cancel()
+
+			err := p.WaitContext(ctx)
+			if (err != nil) != (len(tt.wantErr) > 0) {
+				t.Fatalf("Pool.WaitContext() error = %v, want %v", err, tt.wantErr)
+			}
+
+			for _, want := range tt.wantErr {
+				if !errors.Is(err, want) {
+					t.Errorf("Pool.WaitContext() = %v, want %v", err, want)
+				}
+			}
+
+			if !p.IsClosed() {
+				t.Error("Pool.IsClosed() = false after WaitContext")
+			}
+
+			// the pool completes in the background, Wait reports its outcome.
+			if err := p.Wait(); errors.Is(err, ErrWaitAbandoned) {
+				t.Errorf("Pool.Wait() = %v after WaitContext", err)
+			}
+		})
+	}
+}
+
+func TestPool_TryWait(t *testing.T) {
+	p := testPool(context.Background(), 1, testDefaultNumTasks, false)
+
+	release := make(chan struct{})
+	_ = p.Submit(func() error { <-release; return nil })
+	_ = p.Submit(testFuncWithErr)
+
+	if done, err := p.TryWait(); done || err != nil {
+		t.Errorf("Pool.TryWait() = %v, %v before completion, want false, nil", done, err)
+	}
+
+	close(release)
+	_ = p.Wait()
+
+	if done, err := p.TryWait(); !done || !errors.Is(err, testErr) {
+		t.Errorf("Pool.TryWait() = %v, %v after Wait, want true, %v", done, err, testErr)
+	}
+}
+
+func TestWithFirstSuccess(t *testing.T) {
+	p := testPool(context.Background(), 2, testDefaultNumTasks, true, WithFirstSuccess())
+
+	_ = p.Submit(testFuncWithErr)
+	_ = p.Submit(func() error {
+		time.Sleep(5 * time.Millisecond)
+		return nil
+	})
+
+	var late []*Future
+	for i := 0; i < 5; i++ {
+		f, _ := p.SubmitFuture(func() error {
+			time.Sleep(10 * time.Millisecond)
+			return testErr
+		})
+		late = append(late, f)
+	}
+
+	if err := p.Wait(); err != nil {
+		t.Errorf("Pool.Wait() = %v, want nil", err)
+	}
+
+	discarded := 0
+	for _, f := range late {
+		if errors.Is(f.Err(), ErrTaskDiscarded) {
+			discarded++
+		}
+	}
+
+	if discarded == 0 {
+		t.Error("no task was discarded after the first success")
+	}
+
+	p = testPool(context.Background(), 2, testDefaultNumTasks, false, WithFirstSuccess())
+	_ = p.Submit(testFuncWithErr)
+	_ = p.Submit(testFuncWithErr)
+
+	if err := p.Wait(); !errors.Is(err, testErr) {
+		t.Errorf("Pool.Wait() = %v, want %v", err, testErr)
+	}
+}
+
+func TestWithMaxErrors(t *testing.T) {
+	tests := []struct {
+		name          string
+		maxErrors     int
+		wantDiscarded bool
+	}{
+		{name: "limit reached", maxErrors: 3, wantDiscarded: true},
+		{name: "limit not reached", maxErrors: 20, wantDiscarded: false},
+		{name: "no limit", maxErrors: 0, wantDiscarded: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := testPool(context.Background(), 1, testDefaultNumTasks, false, WithMaxErrors(tt.maxErrors))
+
+			reports := make(chan Report, 1)
+			p.AfterFunc(func(r Report) { reports <- r })
+
+			for i := 0; i < 10; i++ {
+				_ = p.Submit(func() error {
+					time.Sleep(time.Millisecond)
+					return testErr
+				})
+			}
+
+			if err := p.Wait(); !errors.Is(err, testErr) {
+				t.Errorf("Pool.Wait() = %v, want %v", err, testErr)
+			}
+
+			r := <-reports
+			if r.Failed < tt.maxErrors && tt.maxErrors <= 10 {
+				t.Errorf("Report.Failed = %d, want at least %d", r.Failed, tt.maxErrors)
+			}
+
+			if got := r.Discarded > 0; got != tt.wantDiscarded {
+				t.Errorf("Report = %+v, want discarded tasks %v", r, tt.wantDiscarded)
+			}
+		})
+	}
+
+	if _, err := New(1, WithMaxErrors(-1)); !errors.Is(err, ErrInvalidMaxErrors) {
+		t.Errorf("New() error = %v, want %v", err, ErrInvalidMaxErrors)
+	}
+}
+
+func TestWithErrorClassifier(t *testing.T) {
+	var (
+		errIgnore = errors.New("ignore")
+		errFatal  = errors.New("fatal")
+	)
+
+	classify := func(err error) Severity {
+		switch {
+		case errors.Is(err, errIgnore):
+			return SeverityIgnore
+		case errors.Is(err, errFatal):
+			return SeverityFatal
+		default:
+			return SeverityError
+		}
+	}
+
+	tests := []struct {
+		name          string
+		errs          []error // returned by the tasks, in order.
+		wantErr       error
+		wantDiscarded bool
+	}{
+		{name: "ignored", errs: []error{errIgnore, errIgnore, nil}, wantErr: nil},
+		{name: "error", errs: []error{errIgnore, testErr, nil}, wantErr: testErr},
+		{name: "fatal", errs: []error{testErr, errFatal, nil, nil, nil, nil}, wantErr: errFatal, wantDiscarded: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := testPool(context.Background(), 1, testDefaultNumTasks, false, WithErrorClassifier(classify))
+
+			reports := make(chan Report, 1)
+			p.AfterFunc(func(r Report) { reports <- r })
+
+			for _, err := range tt.errs {
+				err := err
+				_ = p.Submit(func() error {
+					time.Sleep(time.Millisecond)
+					return err
+				})
+			}
+
+			if err := p.Wait(); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Pool.Wait() = %v, want %v", err, tt.wantErr)
+			}
+
+			r := <-reports
+			if got := r.Discarded > 0; got != tt.wantDiscarded {
+				t.Errorf("Report = %+v, want discarded tasks %v", r, tt.wantDiscarded)
+			}
+
+			if tt.name == "ignored" && (r.Ignored != 2 || r.Failed != 0) {
+				t.Errorf("Report = %+v, want 2 ignored and 0 failed", r)
+			}
+		})
+	}
+}
+
+func BenchmarkNew(b *testing.B) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	tests := []struct {
+		name  string
+		tasks int
+		err   error
+		opts  []Option
+	}{
+		{name: "empty", opts: []Option{WithNumWorkers(4)}},
+		{name: "10 tasks", tasks: 10, opts: []Option{WithNumWorkers(4)}},
+		{name: "10 failing tasks", tasks: 10, err: errors.New("task failed"), opts: []Option{WithNumWorkers(4)}},
+		{name: "empty lazy", opts: []Option{WithNumWorkers(4), WithLazyWorkers()}},
+		{name: "10 tasks with cancellable context", tasks: 10, opts: []Option{WithNumWorkers(4), WithContext(ctx)}},
+	}
+
+	for _, tt := range tests {
+		b.Run(tt.name, func(b *testing.B) {
+			b.ReportAllocs()
+
+			for i := 0; i < b.N; i++ {
+				p, _ := New(10, tt.opts...)
+				for j := 0; j < tt.tasks; j++ {
+					_ = p.Submit(func() error { return tt.err })
+				}
+
+				_ = p.Wait()
+			}
+		})
+	}
+}